@@ -0,0 +1,50 @@
+package main
+
+import (
+	"encoding/hex"
+	"fmt"
+	"io"
+	"strings"
+
+	"shamir-cli/shamir"
+)
+
+// printAuthTag generates a key, authenticates the shares with it and writes
+// the key and the tag to w on separate lines, so that they can be kept apart
+// from each other and from the parts
+func printAuthTag(w io.Writer, shares []shamir.Share) error {
+	key, err := shamir.NewAuthKey()
+	if err != nil {
+		return err
+	}
+	fmt.Fprintln(w, tr("split.auth_key", hex.EncodeToString(key)))
+	fmt.Fprintf(w, "%s\n\n", tr("split.auth_tag", hex.EncodeToString(shamir.AuthTag(key, shares))))
+	return nil
+}
+
+// parseAuthHex decodes a key or tag given to combine --auth-key or
+// --auth-tag
+func parseAuthHex(s string, size int) ([]byte, error) {
+	b, err := hex.DecodeString(strings.TrimSpace(s))
+	if err != nil || len(b) != size {
+		return nil, fmt.Errorf("expected %d hex digits", 2*size)
+	}
+	return b, nil
+}
+
+// verifyAuthTag checks the parts against the tag printed by split --auth
+// before anything is recovered from them
+func verifyAuthTag(shares []shamir.Share, keyHex, tagHex string) error {
+	key, err := parseAuthHex(keyHex, shamir.AuthKeySize)
+	if err != nil {
+		return newError(codeUsage, "combine.invalid_auth_key", err)
+	}
+	tag, err := parseAuthHex(tagHex, shamir.AuthKeySize)
+	if err != nil {
+		return newError(codeUsage, "combine.invalid_auth_tag", err)
+	}
+	if !shamir.VerifyAuthTag(key, tag, shares) {
+		return newError(codeAuth, "combine.auth_failed", len(shares))
+	}
+	return nil
+}
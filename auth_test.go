@@ -0,0 +1,86 @@
+package main
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"testing"
+
+	"shamir-cli/shamir"
+)
+
+func TestPrintAuthTag(t *testing.T) {
+	shares, err := shamir.Split([]byte("tagged"), 3, 2)
+	if err != nil {
+		t.Fatalf("Split failed: %v", err)
+	}
+	var buf bytes.Buffer
+	if err := printAuthTag(&buf, shares); err != nil {
+		t.Fatalf("printAuthTag failed: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("Unexpected output:\n%s", buf.String())
+	}
+	key := lines[0][strings.LastIndex(lines[0], " ")+1:]
+	tag := lines[1][strings.LastIndex(lines[1], " ")+1:]
+	if err := verifyAuthTag(shares, key, tag); err != nil {
+		t.Errorf("verifyAuthTag rejected the printed key and tag: %v", err)
+	}
+}
+
+func TestCombineAuth(t *testing.T) {
+	shares, err := shamir.Split([]byte("authentic"), 3, 2)
+	if err != nil {
+		t.Fatalf("Split failed: %v", err)
+	}
+	key, err := shamir.NewAuthKey()
+	if err != nil {
+		t.Fatalf("NewAuthKey failed: %v", err)
+	}
+	keyHex, tagHex := hex.EncodeToString(key), hex.EncodeToString(shamir.AuthTag(key, shares))
+
+	var parts []string
+	for _, share := range shares {
+		parts = append(parts, shamir.ShareToString(share))
+	}
+	out, err := executeCommand(t, "", "combine", "--auth-key", keyHex, "--auth-tag", tagHex, parts[2]+","+parts[0]+","+parts[1])
+	if err != nil {
+		t.Fatalf("combine --auth-key failed: %v", err)
+	}
+	if out != "Recovered secret: authentic\n" {
+		t.Errorf("combine output = %q", out)
+	}
+
+	// A tampered part is detected even where it does not matter for recovery
+	tampered := shares[2]
+	tampered.Value = bytes.Clone(tampered.Value)
+	tampered.Value[0] ^= 0x80
+
+	tests := []struct {
+		name string
+		args []string
+		code string
+	}{
+		{"tampered part", []string{"combine", "--auth-key", keyHex, "--auth-tag", tagHex, parts[0] + "," + parts[1] + "," + shamir.ShareToString(tampered)}, codeAuth},
+		{"missing part", []string{"combine", "--auth-key", keyHex, "--auth-tag", tagHex, parts[0] + "," + parts[1]}, codeAuth},
+		{"wrong tag", []string{"combine", "--auth-key", keyHex, "--auth-tag", keyHex, strings.Join(parts, ",")}, codeAuth},
+		{"invalid key", []string{"combine", "--auth-key", "zz", "--auth-tag", tagHex, strings.Join(parts, ",")}, codeUsage},
+		{"gf16", []string{"combine", "--field", "gf16", "--auth-key", keyHex, "--auth-tag", tagHex, strings.Join(parts, ",")}, codeUsage},
+		{"split gf16", []string{"split", "--field", "gf16", "--auth", "secret", "3", "2"}, codeUsage},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, err := executeCommand(t, "", tt.args...)
+			var e *cliError
+			if !errors.As(err, &e) || e.Code != tt.code {
+				t.Errorf("%v returned %v, want error code %q", tt.args, err, tt.code)
+			}
+			if strings.Contains(out, "authentic") {
+				t.Errorf("secret output despite failure: %q", out)
+			}
+		})
+	}
+}
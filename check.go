@@ -0,0 +1,180 @@
+package main
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+)
+
+var checkCmd = &cobra.Command{
+	Use:   "check [dir]",
+	Short: "Report which secrets a directory of part files can recover",
+	Long: `Scans a directory and its subdirectories for part files, such as those
+written by "split --out-dir" or "split-key", and groups the parts by format
+version and length. For each group it reports the distinct part IDs found
+and, when the threshold is known from armored headers or a manifest.json,
+whether enough parts exist to recover the secret. Parts of different
+secrets with the same length fall into the same group.
+
+Nothing is reconstructed and no secret is printed. The command fails when a
+group with a known threshold does not have enough parts.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		out := cmd.OutOrStdout()
+
+		groups, skipped, err := scanShareDir(args[0])
+		if err != nil {
+			return newError(codeIO, "check.scan_failed", err)
+		}
+		for _, s := range skipped {
+			fmt.Fprintln(out, tr("check.skipped", s.path, s.err))
+		}
+		if len(groups) == 0 {
+			return newError(codeInsufficientShares, "check.no_parts", args[0])
+		}
+		if len(skipped) > 0 {
+			fmt.Fprintln(out)
+		}
+
+		missing := 0
+		w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, tr("check.header"))
+		for i, g := range groups {
+			ids := g.sortedIDs()
+			idList := make([]string, len(ids))
+			for j, id := range ids {
+				idList[j] = fmt.Sprint(id)
+			}
+
+			var status string
+			switch {
+			case g.threshold == 0:
+				status = tr("check.status_unknown")
+			case len(ids) >= g.threshold:
+				status = tr("check.status_ok")
+			default:
+				status = tr("check.status_missing", g.threshold-len(ids))
+				missing++
+			}
+
+			threshold := "?"
+			if g.threshold > 0 {
+				threshold = fmt.Sprint(g.threshold)
+			}
+			fmt.Fprintf(w, "%d\t%d\t%d\t%s\t%s\t%s\n", i+1, g.length, g.parts, strings.Join(idList, ", "), threshold, status)
+		}
+		w.Flush()
+
+		if missing > 0 {
+			return newError(codeNotRecoverable, "check.not_recoverable", missing)
+		}
+		return nil
+	},
+}
+
+// shareGroup collects the parts found by check that have the same format
+// version and length
+type shareGroup struct {
+	version   byte
+	length    int
+	parts     int
+	ids       map[byte]bool
+	threshold int
+}
+
+// sortedIDs returns the distinct part IDs of the group in ascending order
+func (g *shareGroup) sortedIDs() []int {
+	ids := make([]int, 0, len(g.ids))
+	for id := range g.ids {
+		ids = append(ids, int(id))
+	}
+	sort.Ints(ids)
+	return ids
+}
+
+// skippedFile is a file check could not parse as parts
+type skippedFile struct {
+	path string
+	err  error
+}
+
+// scanShareDir parses the part files below dir and groups their parts.
+// Thresholds come from armored headers and from manifest.json files, which
+// list the part files of their directory.
+func scanShareDir(dir string) ([]*shareGroup, []skippedFile, error) {
+	var files []string
+	thresholds := make(map[string]int)
+
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		if d.Name() != manifestName {
+			files = append(files, path)
+			return nil
+		}
+
+		m, err := readManifest(path)
+		if err != nil {
+			return nil
+		}
+		for _, s := range m.Shares {
+			thresholds[filepath.Join(filepath.Dir(path), s.File)] = m.Threshold
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	type groupKey struct {
+		version byte
+		length  int
+	}
+	var groups []*shareGroup
+	byKey := make(map[groupKey]*shareGroup)
+	var skipped []skippedFile
+
+	for _, path := range files {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		shares, threshold, err := parseShareInput(string(data))
+		if err == nil && len(shares) == 0 {
+			err = fmt.Errorf("%s", tr("parse.no_parts"))
+		}
+		if err != nil {
+			skipped = append(skipped, skippedFile{path, err})
+			continue
+		}
+		if threshold == 0 {
+			threshold = thresholds[path]
+		}
+
+		for _, share := range shares {
+			key := groupKey{share.Version, len(share.Value)}
+			g := byKey[key]
+			if g == nil {
+				g = &shareGroup{version: share.Version, length: len(share.Value), ids: make(map[byte]bool)}
+				byKey[key] = g
+				groups = append(groups, g)
+			}
+			g.parts++
+			g.ids[share.ID] = true
+			g.threshold = max(g.threshold, threshold)
+		}
+	}
+
+	return groups, skipped, nil
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"shamir-cli/shamir"
+)
+
+func TestCheckCommand(t *testing.T) {
+	dir := t.TempDir()
+
+	// A complete set with a manifest recording the threshold
+	complete := filepath.Join(dir, "complete")
+	shares, err := shamir.Split([]byte("complete"), 3, 2)
+	if err != nil {
+		t.Fatalf("Split failed: %v", err)
+	}
+	paths, err := writeShareFiles(complete, "share", shares, 2, formatText)
+	if err != nil {
+		t.Fatalf("writeShareFiles failed: %v", err)
+	}
+	if _, err := writeManifest(complete, shares, paths, 2, 8, time.Now()); err != nil {
+		t.Fatalf("writeManifest failed: %v", err)
+	}
+
+	// An armored set that lost two of its five parts
+	partial := filepath.Join(dir, "partial")
+	shares, err = shamir.Split([]byte("partial set"), 5, 4)
+	if err != nil {
+		t.Fatalf("Split failed: %v", err)
+	}
+	if _, err := writeShareFiles(partial, "share", shares[:3], 4, formatArmor); err != nil {
+		t.Fatalf("writeShareFiles failed: %v", err)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "broken.txt"), []byte("1:zz"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	out, err := executeCommand(t, "", "check", dir)
+	var e *cliError
+	if !errors.As(err, &e) || e.Code != codeNotRecoverable {
+		t.Fatalf("check returned %v, want error code %q\n%s", err, codeNotRecoverable, out)
+	}
+
+	for _, want := range []string{
+		"Skipped " + filepath.Join(dir, "broken.txt"),
+		"1      9            3      1, 2, 3  2          recoverable",
+		"2      12           3      1, 2, 3  4          parts missing: 1",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("check output lacks %q:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "partial set") {
+		t.Errorf("check must not print secrets:\n%s", out)
+	}
+
+	// Adding the missing part makes every group recoverable
+	if _, err := writeShareFiles(partial, "share", shares[3:4], 4, formatArmor); err != nil {
+		t.Fatalf("writeShareFiles failed: %v", err)
+	}
+	if out, err := executeCommand(t, "", "check", dir); err != nil {
+		t.Errorf("check failed: %v\n%s", err, out)
+	}
+}
+
+func TestCheckCommandEmpty(t *testing.T) {
+	_, err := executeCommand(t, "", "check", t.TempDir())
+	var e *cliError
+	if !errors.As(err, &e) || e.Code != codeInsufficientShares {
+		t.Errorf("check of an empty directory returned %v, want error code %q", err, codeInsufficientShares)
+	}
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"shamir-cli/shamir"
+)
+
+// chunkShares cuts every share into chunks whose hex value has at most
+// maxDigits digits, returning the chunk strings of each share
+func chunkShares(shares []shamir.Share, maxDigits int) ([][]string, error) {
+	chunked := make([][]string, len(shares))
+	for i, share := range shares {
+		chunks, err := shamir.ChunkShare(share, maxDigits/2)
+		if err != nil {
+			return nil, err
+		}
+		for _, c := range chunks {
+			chunked[i] = append(chunked[i], shamir.ChunkToString(c))
+		}
+	}
+	return chunked, nil
+}
+
+// verifyChunks parses the chunks of the first k shares back and checks that
+// they recover the secret
+func verifyChunks(secret []byte, chunked [][]string, k int) error {
+	var all []string
+	for _, chunks := range chunked[:k] {
+		all = append(all, chunks...)
+	}
+
+	parsed, _, err := parseShareInput(strings.Join(all, "\n"))
+	if err != nil {
+		return err
+	}
+	recovered, err := shamir.Combine(parsed)
+	if err != nil {
+		return err
+	}
+	if !shamir.SecretsEqual(recovered, secret) {
+		return errors.New("recovered secret does not match the original")
+	}
+	return nil
+}
+
+// printChunkedShares prints the chunks of each share, grouped by share
+func printChunkedShares(out io.Writer, chunked [][]string, n, k int) {
+	fmt.Fprintf(out, "%s\n\n", colorize(out, colorBold, tr("split.header", n, k)))
+	for i, chunks := range chunked {
+		for j, c := range chunks {
+			fmt.Fprintln(out, tr("split.part_chunk", i+1, j+1, len(chunks), c))
+		}
+	}
+	printSplitSummary(out, n, k)
+	fmt.Fprintf(out, "\n%s\n", tr("split.chunk_hint", k))
+}
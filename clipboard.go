@@ -0,0 +1,61 @@
+package main
+
+import (
+	"errors"
+	"strings"
+
+	"shamir-cli/shamir"
+
+	"github.com/atotto/clipboard"
+)
+
+// errNoClipboard is returned when the system has no usable clipboard, e.g.
+// on a headless server or when xclip, xsel and wl-clipboard are missing
+var errNoClipboard = errors.New("no clipboard available on this system")
+
+// Access to the system clipboard, replaced in tests
+var (
+	clipboardWrite = writeClipboard
+	clipboardRead  = readClipboard
+)
+
+// writeClipboard replaces the contents of the system clipboard with text
+func writeClipboard(text string) error {
+	if clipboard.Unsupported {
+		return errNoClipboard
+	}
+	return clipboard.WriteAll(text)
+}
+
+// readClipboard returns the contents of the system clipboard
+func readClipboard() (string, error) {
+	if clipboard.Unsupported {
+		return "", errNoClipboard
+	}
+	return clipboard.ReadAll()
+}
+
+// copySharesToClipboard copies the shares, one per line or as armored
+// blocks, to the clipboard
+func copySharesToClipboard(shares []shamir.Share, k int, format string) error {
+	if isCSVFormat(format) {
+		var b strings.Builder
+		if err := writeSharesCSV(&b, shares, k, tableDelimiter(format)); err != nil {
+			return err
+		}
+		return clipboardWrite(b.String())
+	}
+	if format == formatTable {
+		var b strings.Builder
+		if err := writeSharesGrid(&b, shares); err != nil {
+			return err
+		}
+		return clipboardWrite(b.String())
+	}
+
+	encoded := make([]string, len(shares))
+	for i, share := range shares {
+		encoded[i] = encodeShare(share, k, format)
+	}
+	return clipboardWrite(strings.Join(encoded, "\n"))
+}
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"golang.org/x/term"
+)
+
+// ANSI color codes used for highlights
+const (
+	colorBold  = "1"
+	colorRed   = "31"
+	colorGreen = "32"
+)
+
+// colorEnabled reports whether text written to w should be colored: w must
+// be a terminal and NO_COLOR (https://no-color.org) must be unset or empty
+func colorEnabled(w io.Writer) bool {
+	if os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+	f, ok := w.(*os.File)
+	return ok && term.IsTerminal(int(f.Fd()))
+}
+
+// colorize wraps s in the given ANSI color when writing to w is colored and
+// returns s unchanged otherwise, e.g. when the output is piped
+func colorize(w io.Writer, color, s string) string {
+	if !colorEnabled(w) {
+		return s
+	}
+	return "\x1b[" + color + "m" + s + "\x1b[0m"
+}
+
+// printSplitSummary prints how many parts were created, highlighted as a
+// success, and a reminder to keep them secret, highlighted as a warning
+func printSplitSummary(w io.Writer, n, k int) {
+	fmt.Fprintf(w, "\n%s\n%s\n", colorize(w, colorGreen, tr("split.summary", n, k)), colorize(w, colorRed, tr("split.keep_secret")))
+}
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestColorizeNonTerminal(t *testing.T) {
+	var buf bytes.Buffer
+	if got := colorize(&buf, colorRed, "warning"); got != "warning" {
+		t.Errorf("colorize on a buffer = %q", got)
+	}
+
+	f, err := os.Create(filepath.Join(t.TempDir(), "out"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if got := colorize(f, colorGreen, "ok"); got != "ok" {
+		t.Errorf("colorize on a file = %q", got)
+	}
+
+	t.Setenv("NO_COLOR", "1")
+	if colorEnabled(os.Stdout) {
+		t.Error("color enabled despite NO_COLOR")
+	}
+}
+
+func TestSplitOutputWithoutColor(t *testing.T) {
+	out, err := executeCommand(t, "", "split", "summary", "5", "3")
+	if err != nil {
+		t.Fatalf("split failed: %v", err)
+	}
+	if strings.Contains(out, "\x1b[") {
+		t.Errorf("piped output contains color codes:\n%q", out)
+	}
+	if !strings.Contains(out, "5 parts created, any 3 of them recover the secret.") {
+		t.Errorf("summary missing from output:\n%s", out)
+	}
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"strings"
+
+	"shamir-cli/shamir"
+)
+
+// commitment returns the hex SHA-256 of the secret printed by split
+// --commit. It reveals nothing useful about a secret with enough entropy
+// and lets holders confirm later that they recovered the right secret.
+func commitment(secret []byte) string {
+	sum := sha256.Sum256(secret)
+	return hex.EncodeToString(sum[:])
+}
+
+// parseCommitment decodes a commitment given to combine --verify-commit
+func parseCommitment(s string) ([]byte, error) {
+	want, err := hex.DecodeString(strings.TrimSpace(s))
+	if err != nil || len(want) != sha256.Size {
+		return nil, fmt.Errorf("expected %d hex digits", 2*sha256.Size)
+	}
+	return want, nil
+}
+
+// printCommitment writes the commitment to the secret to w
+func printCommitment(w io.Writer, secret []byte) {
+	fmt.Fprintf(w, "%s\n\n", tr("split.commitment", commitment(secret)))
+}
+
+// verifyCommitment checks the recovered secret against the commitment
+// given with --verify-commit
+func verifyCommitment(secret []byte, s string) error {
+	want, err := parseCommitment(s)
+	if err != nil {
+		return newError(codeUsage, "combine.invalid_commit", s, err)
+	}
+	sum := sha256.Sum256(secret)
+	if !shamir.SecretsEqual(sum[:], want) {
+		return newError(codeCommitMismatch, "combine.commit_mismatch")
+	}
+	return nil
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"shamir-cli/shamir"
+)
+
+func TestCommitment(t *testing.T) {
+	// SHA-256 of "hello"
+	const want = "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824"
+	if got := commitment([]byte("hello")); got != want {
+		t.Errorf("commitment = %s, want %s", got, want)
+	}
+
+	for _, s := range []string{"", "abcd", want + "00", strings.Replace(want, "2", "g", 1)} {
+		if _, err := parseCommitment(s); err == nil {
+			t.Errorf("parseCommitment(%q) succeeded", s)
+		}
+	}
+	if _, err := parseCommitment(" " + strings.ToUpper(want) + "\n"); err != nil {
+		t.Errorf("parseCommitment failed: %v", err)
+	}
+}
+
+func TestVerifyCommitCommand(t *testing.T) {
+	secret := []byte("committed secret")
+	shares, err := shamir.Split(secret, 3, 2)
+	if err != nil {
+		t.Fatalf("Split failed: %v", err)
+	}
+	parts := shamir.ShareToString(shares[0]) + "," + shamir.ShareToString(shares[2])
+
+	out, err := executeCommand(t, "", "combine", "--verify-commit", commitment(secret), parts)
+	if err != nil {
+		t.Fatalf("combine --verify-commit failed: %v", err)
+	}
+	if !strings.Contains(out, "Commitment verified") || !strings.Contains(out, "Recovered secret: committed secret") {
+		t.Errorf("Unexpected output:\n%s", out)
+	}
+
+	// A mismatch is reported distinctly from a checksum failure and the
+	// secret is not printed
+	out, err = executeCommand(t, "", "combine", "--verify-commit", commitment([]byte("other secret")), parts)
+	var e *cliError
+	if !errors.As(err, &e) || e.Code != codeCommitMismatch {
+		t.Errorf("combine with a wrong commitment returned %v, want error code %q", err, codeCommitMismatch)
+	}
+	if strings.Contains(out, "committed secret") {
+		t.Errorf("secret printed despite a mismatch:\n%s", out)
+	}
+
+	_, err = executeCommand(t, "", "combine", "--verify-commit", "abcd", parts)
+	if !errors.As(err, &e) || e.Code != codeUsage {
+		t.Errorf("combine with an invalid commitment returned %v, want error code %q", err, codeUsage)
+	}
+}
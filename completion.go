@@ -0,0 +1,73 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/cobra/doc"
+)
+
+var manOutputDir string
+
+var completionCmd = &cobra.Command{
+	Use:   "completion [bash|zsh|fish|powershell]",
+	Short: "Generate shell completion script",
+	Long: `Writes a shell completion script to standard output.
+
+Example:
+  shamir-cli completion zsh > _shamir-cli`,
+	Hidden:    true,
+	ValidArgs: []string{"bash", "zsh", "fish", "powershell"},
+	Args:      cobra.MatchAll(cobra.ExactArgs(1), cobra.OnlyValidArgs),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		out := cmd.OutOrStdout()
+
+		var err error
+		switch args[0] {
+		case "bash":
+			err = rootCmd.GenBashCompletionV2(out, true)
+		case "zsh":
+			err = rootCmd.GenZshCompletion(out)
+		case "fish":
+			err = rootCmd.GenFishCompletion(out, true)
+		case "powershell":
+			err = rootCmd.GenPowerShellCompletionWithDesc(out)
+		}
+		if err != nil {
+			return newError(codeIO, "completion.failed", err)
+		}
+		return nil
+	},
+}
+
+var manCmd = &cobra.Command{
+	Use:    "man",
+	Short:  "Generate man pages",
+	Long:   `Generates man pages for all commands into the output directory.`,
+	Hidden: true,
+	Args:   cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		out := cmd.OutOrStdout()
+
+		if err := os.MkdirAll(manOutputDir, 0755); err != nil {
+			return newError(codeIO, "man.mkdir_failed", err)
+		}
+
+		header := &doc.GenManHeader{
+			Title:   "SHAMIR-CLI",
+			Section: "1",
+			Source:  "shamir-cli " + version,
+		}
+		if err := doc.GenManTree(rootCmd, header, manOutputDir); err != nil {
+			return newError(codeIO, "man.failed", err)
+		}
+
+		fmt.Fprintln(out, tr("man.written", manOutputDir))
+		return nil
+	},
+}
+
+func init() {
+	manCmd.Flags().StringVarP(&manOutputDir, "output-dir", "o", "man", "directory to write man pages to")
+}
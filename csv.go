@@ -0,0 +1,104 @@
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"shamir-cli/shamir"
+)
+
+// csvHeader is the header row of parts exported with --format csv or tsv
+var csvHeader = []string{"id", "threshold", "hex"}
+
+// isCSVFormat reports whether format writes all parts as one CSV or TSV
+// table instead of one encoded part each
+func isCSVFormat(format string) bool {
+	return format == formatCSV || format == formatTSV
+}
+
+// tableDelimiter returns the field delimiter of a table format
+func tableDelimiter(format string) rune {
+	if format == formatTSV {
+		return '\t'
+	}
+	return ','
+}
+
+// writeSharesCSV writes a header row followed by one row of ID, threshold
+// and hex value per share, separated by delim
+func writeSharesCSV(w io.Writer, shares []shamir.Share, k int, delim rune) error {
+	cw := csv.NewWriter(w)
+	cw.Comma = delim
+	if err := cw.Write(csvHeader); err != nil {
+		return err
+	}
+	for _, share := range shares {
+		row := []string{strconv.Itoa(int(share.ID)), strconv.Itoa(k), fmt.Sprintf("%x", share.Value)}
+		if err := cw.Write(row); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// readSharesCSV reads parts written by writeSharesCSV. The delimiter, comma
+// or tab, is detected from the header row, which must name the columns id,
+// threshold and hex in that order. It also returns the threshold recorded
+// in the rows.
+func readSharesCSV(data []byte) ([]shamir.Share, int, error) {
+	header, _, _ := bytes.Cut(data, []byte("\n"))
+
+	cr := csv.NewReader(bytes.NewReader(data))
+	if bytes.ContainsRune(header, '\t') {
+		cr.Comma = '\t'
+	}
+	cr.FieldsPerRecord = len(csvHeader)
+	cr.TrimLeadingSpace = true
+
+	records, err := cr.ReadAll()
+	if err != nil {
+		return nil, 0, err
+	}
+	if len(records) == 0 {
+		return nil, 0, errors.New("missing header row")
+	}
+	for i, name := range records[0] {
+		if !strings.EqualFold(strings.TrimSpace(name), csvHeader[i]) {
+			return nil, 0, fmt.Errorf("invalid header %q, want %q", strings.Join(records[0], string(cr.Comma)), strings.Join(csvHeader, string(cr.Comma)))
+		}
+	}
+
+	shares := make([]shamir.Share, 0, len(records)-1)
+	threshold := 0
+	for i, record := range records[1:] {
+		line := i + 2
+		for j := range record {
+			record[j] = strings.TrimSpace(record[j])
+		}
+
+		k, err := strconv.Atoi(record[1])
+		if err != nil || k < 2 {
+			return nil, 0, fmt.Errorf("line %d: invalid threshold %q", line, record[1])
+		}
+		if threshold != 0 && k != threshold {
+			return nil, 0, fmt.Errorf("line %d: threshold %d differs from %d in the rows above", line, k, threshold)
+		}
+		threshold = k
+
+		if _, err := strconv.ParseUint(record[0], 10, 8); err != nil {
+			return nil, 0, fmt.Errorf("line %d: invalid ID %q", line, record[0])
+		}
+		share, err := shamir.StringToShare(record[0] + ":" + record[2])
+		if err != nil {
+			return nil, 0, fmt.Errorf("line %d: %w", line, err)
+		}
+		shares = append(shares, share)
+	}
+	return shares, threshold, nil
+}
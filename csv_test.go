@@ -0,0 +1,103 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCSVRoundTrip(t *testing.T) {
+	for _, format := range []string{formatCSV, formatTSV} {
+		t.Run(format, func(t *testing.T) {
+			out, err := executeCommand(t, "", "split", "--format", format, "--verify", "csv secret", "5", "3")
+			if err != nil {
+				t.Fatalf("split failed: %v", err)
+			}
+			lines := strings.Split(strings.TrimSpace(out), "\n")
+			if len(lines) != 6 {
+				t.Fatalf("expected a header and 5 rows, got:\n%s", out)
+			}
+			if want := strings.Join(csvHeader, string(tableDelimiter(format))); lines[0] != want {
+				t.Errorf("header = %q, want %q", lines[0], want)
+			}
+
+			// Keep the header and three rows, out of order
+			path := filepath.Join(t.TempDir(), "parts."+format)
+			table := strings.Join([]string{lines[0], lines[4], lines[2], lines[5]}, "\n") + "\n"
+			if err := os.WriteFile(path, []byte(table), 0600); err != nil {
+				t.Fatal(err)
+			}
+
+			out, err = executeCommand(t, "", "combine", "--csv", path)
+			if err != nil {
+				t.Fatalf("combine failed: %v\n%s", err, out)
+			}
+			if !strings.Contains(out, "csv secret") {
+				t.Errorf("Unexpected output: %s", out)
+			}
+		})
+	}
+}
+
+func TestReadSharesCSV(t *testing.T) {
+	// Quoted fields, surrounding whitespace and blank lines are accepted
+	shares, threshold, err := readSharesCSV([]byte(" ID , Threshold , Hex\r\n\"1\", 2, \"abcd\"\n\n 2 ,2,  ef01 \n"))
+	if err != nil {
+		t.Fatalf("readSharesCSV failed: %v", err)
+	}
+	if threshold != 2 || len(shares) != 2 || shares[0].ID != 1 || shares[1].ID != 2 || shares[1].Value[1] != 0x01 {
+		t.Errorf("readSharesCSV = %+v, %d", shares, threshold)
+	}
+
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{"empty", ""},
+		{"wrong header", "id,k,hex\n1,2,abcd\n"},
+		{"missing column", "id,threshold,hex\n1,abcd\n"},
+		{"invalid ID", "id,threshold,hex\nv2:1,2,abcd\n"},
+		{"invalid threshold", "id,threshold,hex\n1,one,abcd\n"},
+		{"different thresholds", "id,threshold,hex\n1,2,abcd\n2,3,ef01\n"},
+		{"invalid hex", "id,threshold,hex\n1,2,xyz\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, _, err := readSharesCSV([]byte(tt.input)); err == nil {
+				t.Errorf("expected error for %q", tt.input)
+			}
+		})
+	}
+}
+
+func TestCombineCSVErrors(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "parts.csv")
+	if err := os.WriteFile(path, []byte("id,threshold,hex\n1,3,abcd\n2,3,ef01\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		args []string
+		code string
+	}{
+		{"below threshold", []string{"combine", "--csv", path}, codeInsufficientShares},
+		{"with argument", []string{"combine", "--csv", path, "1:ab,2:cd"}, codeUsage},
+		{"missing file", []string{"combine", "--csv", path + ".missing"}, codeIO},
+		{"pad", []string{"split", "--format", "csv", "--pad", "16", "secret", "3", "2"}, codeUsage},
+		{"out-dir", []string{"split", "--format", "tsv", "--out-dir", t.TempDir(), "secret", "3", "2"}, codeUsage},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := executeCommand(t, "", tt.args...)
+			var e *cliError
+			if !errors.As(err, &e) || e.Code != tt.code {
+				t.Errorf("%v returned %v, want error code %q", tt.args, err, tt.code)
+			}
+		})
+	}
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"text/tabwriter"
+
+	"shamir-cli/shamir"
+
+	"github.com/spf13/cobra"
+)
+
+var doctorThreshold int
+
+var doctorCmd = &cobra.Command{
+	Use:   "doctor [parts_separated_by_commas]",
+	Short: "Find the corrupt parts among more parts than the threshold",
+	Long: `Diagnoses a set of parts that fails to combine, e.g. with a checksum
+error. Given more parts than the threshold, it combines every subset of
+exactly threshold parts and reports for each part how many of its subsets
+recover the secret most subsets agree on. Parts never in such a subset are
+listed first as likely corrupt. The secret is never printed.
+
+The threshold is taken from armored parts or given with --threshold. At
+most 10000 subsets are tried. Parts are read from standard input when no
+argument is given.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		out := cmd.OutOrStdout()
+		ctx, cancel := commandContext(cmd)
+		defer cancel()
+
+		input, err := readShareInput(ctx, cmd.InOrStdin(), args)
+		if err != nil {
+			return newError(codeIO, "parse.read_failed", err)
+		}
+		shares, threshold, err := parseShareInput(input)
+		if err != nil {
+			return err
+		}
+		if doctorThreshold > 0 {
+			threshold = doctorThreshold
+		}
+		if threshold == 0 {
+			return newError(codeUsage, "doctor.threshold")
+		}
+
+		shares, err = shamir.DedupShares(shares)
+		if err != nil {
+			return newError(codeParse, "combine.conflicting_parts", err)
+		}
+		d, err := shamir.Diagnose(shares, threshold)
+		if err != nil {
+			return newError(errorCode(err, codeInsufficientShares), "doctor.failed", err)
+		}
+
+		fmt.Fprintln(out, tr("doctor.summary", d.Tried, threshold, d.Consistent))
+		if d.Consistent == 0 {
+			return newError(codeNotRecoverable, "doctor.none", threshold)
+		}
+
+		suspects := make(map[byte]bool)
+		for _, id := range d.Suspects() {
+			suspects[id] = true
+		}
+
+		fmt.Fprintln(out)
+		w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, tr("doctor.header"))
+		for _, s := range d.Shares {
+			verdict := tr("doctor.ok")
+			if suspects[s.ID] {
+				verdict = tr("doctor.suspect")
+			}
+			fmt.Fprintln(w, tr("doctor.row", s.ID, s.Consistent, s.Subsets, verdict))
+		}
+		w.Flush()
+		fmt.Fprintln(out)
+
+		if len(suspects) == 0 {
+			fmt.Fprintln(out, tr("doctor.no_suspects"))
+			return nil
+		}
+		ids := make([]string, 0, len(suspects))
+		for _, id := range d.Suspects() {
+			ids = append(ids, fmt.Sprint(id))
+		}
+		fmt.Fprintln(out, tr("doctor.suspects", strings.Join(ids, ", ")))
+		return nil
+	},
+}
+
+func init() {
+	doctorCmd.Flags().IntVarP(&doctorThreshold, "threshold", "k", 0, "number of parts required for recovery, for parts that do not record it")
+}
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"shamir-cli/shamir"
+)
+
+func TestDoctorCommand(t *testing.T) {
+	shares, err := shamir.Split([]byte("corrupted"), 5, 3)
+	if err != nil {
+		t.Fatalf("Split failed: %v", err)
+	}
+	// Flip a bit of part 2
+	shares[1].Value[0] ^= 1
+
+	var parts []string
+	for _, share := range shares {
+		parts = append(parts, shamir.ShareToString(share))
+	}
+
+	out, err := executeCommand(t, "", "doctor", "-k", "3", strings.Join(parts, ","))
+	if err != nil {
+		t.Fatalf("doctor failed: %v\n%s", err, out)
+	}
+	lines := strings.Split(out, "\n")
+	if len(lines) < 4 || !strings.HasPrefix(lines[3], "2 ") || !strings.Contains(lines[3], "likely corrupt") {
+		t.Errorf("Part 2 not ranked first as corrupt:\n%s", out)
+	}
+	if !strings.Contains(out, "Likely corrupt parts: 2.") || strings.Contains(out, "corrupted") {
+		t.Errorf("Unexpected output:\n%s", out)
+	}
+
+	_, err = executeCommand(t, "", "doctor", strings.Join(parts, ","))
+	var e *cliError
+	if !errors.As(err, &e) || e.Code != codeUsage {
+		t.Errorf("doctor without a threshold returned %v, want error code %q", err, codeUsage)
+	}
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"shamir-cli/shamir"
+
+	"github.com/spf13/cobra"
+)
+
+// envSharePrefix is the prefix of the environment variables holding parts,
+// followed by the part ID, e.g. SHAMIR_SHARE_1=1:a1b2c3
+const envSharePrefix = "SHAMIR_SHARE_"
+
+// envShareLine returns the assignment of a share to its environment variable
+func envShareLine(share shamir.Share) string {
+	return fmt.Sprintf("%s%d=%s", envSharePrefix, share.ID, shamir.ShareToString(share))
+}
+
+// writeSharesEnv writes one assignment per share
+func writeSharesEnv(w io.Writer, shares []shamir.Share) error {
+	for _, share := range shares {
+		if _, err := fmt.Fprintln(w, envShareLine(share)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// readSharesEnv parses the parts in SHAMIR_SHARE_<n> variables of an
+// environment in the form returned by os.Environ, ordered by n. Other
+// variables are ignored; a variable with the prefix but without a valid
+// index is an error.
+func readSharesEnv(env []string) ([]shamir.Share, error) {
+	type indexed struct {
+		index int
+		share shamir.Share
+	}
+
+	var found []indexed
+	for _, kv := range env {
+		name, value, _ := strings.Cut(kv, "=")
+		suffix, ok := strings.CutPrefix(name, envSharePrefix)
+		if !ok {
+			continue
+		}
+
+		index, err := strconv.Atoi(suffix)
+		if err != nil || index < 1 || strconv.Itoa(index) != suffix {
+			return nil, fmt.Errorf("invalid variable name %s: want %s followed by a positive number", name, envSharePrefix)
+		}
+		share, err := shamir.ParseShare(value)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		found = append(found, indexed{index, share})
+	}
+
+	sort.Slice(found, func(i, j int) bool { return found[i].index < found[j].index })
+	shares := make([]shamir.Share, len(found))
+	for i, f := range found {
+		shares[i] = f.share
+	}
+	return shares, nil
+}
+
+// combineEnv recovers the secret from the parts in SHAMIR_SHARE_*
+// environment variables
+func combineEnv(ctx context.Context, cmd *cobra.Command, args []string) error {
+	if len(args) > 0 {
+		return newError(codeUsage, "combine.env_args")
+	}
+	if combineField != fieldGF8 {
+		return newError(codeUsage, "combine.env_gf8_only")
+	}
+
+	shares, err := readSharesEnv(os.Environ())
+	if err != nil {
+		return newError(codeParse, "parse.env", err)
+	}
+	if len(shares) == 0 {
+		return newError(codeInsufficientShares, "combine.env_none", envSharePrefix)
+	}
+	return combineShares(ctx, cmd, shares, 0)
+}
@@ -0,0 +1,132 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestEnvRoundTrip(t *testing.T) {
+	out, err := executeCommand(t, "", "split", "--format", "env", "--pad", "8", "env secret", "5", "3")
+	if err != nil {
+		t.Fatalf("split --format env failed: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	if len(lines) != 5 || !strings.HasPrefix(lines[0], "SHAMIR_SHARE_1=v2:1:") {
+		t.Fatalf("Unexpected output:\n%s", out)
+	}
+
+	// Pass three of the parts, set in reverse order
+	for _, line := range []string{lines[4], lines[2], lines[1]} {
+		name, value, _ := strings.Cut(line, "=")
+		t.Setenv(name, value)
+	}
+	t.Setenv("SHAMIR_SHARED", "not a part")
+
+	out, err = executeCommand(t, "", "combine", "--from-env")
+	if err != nil {
+		t.Fatalf("combine --from-env failed: %v\n%s", err, out)
+	}
+	if out != "Recovered secret: env secret\n" {
+		t.Errorf("combine output = %q", out)
+	}
+}
+
+func TestReadSharesEnv(t *testing.T) {
+	shares, err := readSharesEnv([]string{"HOME=/root", "SHAMIR_SHARE_10=10:ab", "SHAMIR_SHARE_2=0302cd", "SHAMIR_SHARE_9=9:ef"})
+	if err != nil {
+		t.Fatalf("readSharesEnv failed: %v", err)
+	}
+	// Ordered by index, not by ID or position
+	if len(shares) != 3 || shares[0].ID != 3 || shares[1].ID != 9 || shares[2].ID != 10 {
+		t.Errorf("readSharesEnv = %+v", shares)
+	}
+
+	for _, env := range [][]string{
+		{"SHAMIR_SHARE_=1:ab"},
+		{"SHAMIR_SHARE_X=1:ab"},
+		{"SHAMIR_SHARE_0=1:ab"},
+		{"SHAMIR_SHARE_01=1:ab"},
+		{"SHAMIR_SHARE_1=1:zz"},
+	} {
+		if _, err := readSharesEnv(env); err == nil {
+			t.Errorf("readSharesEnv(%q) succeeded", env)
+		}
+	}
+}
+
+func TestCombineFromEnvErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		env  map[string]string
+		args []string
+		code string
+	}{
+		{"no variables", nil, []string{"combine", "--from-env"}, codeInsufficientShares},
+		{"invalid name", map[string]string{"SHAMIR_SHARE_A": "1:ab"}, []string{"combine", "--from-env"}, codeParse},
+		{"with argument", nil, []string{"combine", "--from-env", "1:ab,2:cd"}, codeUsage},
+		{"out-dir", nil, []string{"split", "--format", "env", "--out-dir", t.TempDir(), "secret", "3", "2"}, codeUsage},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for name, value := range tt.env {
+				t.Setenv(name, value)
+			}
+			_, err := executeCommand(t, "", tt.args...)
+			var e *cliError
+			if !errors.As(err, &e) || e.Code != tt.code {
+				t.Errorf("%v returned %v, want error code %q", tt.args, err, tt.code)
+			}
+		})
+	}
+}
+
+func TestSplitSecretEnv(t *testing.T) {
+	t.Setenv("SHAMIR_TEST_SECRET", "from the environment")
+	out, err := executeCommand(t, "", "split", "--secret-env", "SHAMIR_TEST_SECRET", "3", "2")
+	if err != nil {
+		t.Fatalf("split --secret-env failed: %v", err)
+	}
+	if _, ok := os.LookupEnv("SHAMIR_TEST_SECRET"); ok {
+		t.Error("split --secret-env left the variable set")
+	}
+
+	lines := strings.Split(out, "\n")
+	parts := strings.TrimPrefix(lines[2], "Part 1: ") + "," + strings.TrimPrefix(lines[4], "Part 3: ")
+	out, err = executeCommand(t, "", "combine", parts)
+	if err != nil {
+		t.Fatalf("combine failed: %v", err)
+	}
+	if out != "Recovered secret: from the environment\n" {
+		t.Errorf("combine output = %q", out)
+	}
+
+	tests := []struct {
+		name string
+		env  map[string]string
+		args []string
+		code string
+	}{
+		{"unset", nil, []string{"split", "--secret-env", "SHAMIR_TEST_UNSET", "3", "2"}, codeUsage},
+		{"empty", map[string]string{"SHAMIR_TEST_EMPTY": ""}, []string{"split", "--secret-env", "SHAMIR_TEST_EMPTY", "3", "2"}, codeInvalidSecretLength},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for name, value := range tt.env {
+				t.Setenv(name, value)
+			}
+			_, err := executeCommand(t, "", tt.args...)
+			var e *cliError
+			if !errors.As(err, &e) || e.Code != tt.code {
+				t.Errorf("%v returned %v, want error code %q", tt.args, err, tt.code)
+			}
+		})
+	}
+
+	t.Setenv("SHAMIR_TEST_EMPTY", "")
+	if _, err := executeCommand(t, "", "split", "--secret-env", "SHAMIR_TEST_EMPTY", "--allow-empty", "3", "2"); err != nil {
+		t.Errorf("split --secret-env --allow-empty of an empty variable failed: %v", err)
+	}
+}
@@ -0,0 +1,175 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+
+	"shamir-cli/shamir"
+)
+
+// jsonErrors makes failures print a JSON object to stderr, set by the
+// --json-errors flag
+var jsonErrors bool
+
+// Stable error codes reported with --json-errors
+const (
+	codeUsage               = "usage"
+	codeThresholdTooSmall   = "threshold_too_small"
+	codeThresholdTooLarge   = "threshold_too_large"
+	codeTooManyShares       = "too_many_shares"
+	codeInvalidSecretLength = "invalid_secret_length"
+	codeInsufficientShares  = "insufficient_shares"
+	codeParse               = "parse_error"
+	codeChecksum            = "checksum_failed"
+	codeVerify              = "verify_failed"
+	codeNotRecoverable      = "not_recoverable"
+	codeIO                  = "io_error"
+	codeSplit               = "split_failed"
+	codeCombine             = "combine_failed"
+	codePartialRecovery     = "partial_recovery"
+	codeSelfTest            = "selftest_failed"
+	codeTimeout             = "timeout"
+	codeCommitMismatch      = "commitment_mismatch"
+	codeExec                = "exec_failed"
+	codeExcessShares        = "excess_shares"
+	codeAuth                = "authentication_failed"
+	codeInsufficientHolders = "insufficient_holders"
+)
+
+// Exit codes for classes of failures
+const (
+	exitFailure  = 1 // I/O and other failures
+	exitUsage    = 2 // invalid arguments or flags
+	exitParse    = 3 // parts that cannot be parsed
+	exitRecovery = 4 // parts that do not recover a secret
+	exitTimeout  = 5 // the --timeout expired
+)
+
+// exitCodes maps error codes to exit codes; codes not listed exit with exitFailure
+var exitCodes = map[string]int{
+	codeUsage:               exitUsage,
+	codeThresholdTooSmall:   exitUsage,
+	codeThresholdTooLarge:   exitUsage,
+	codeTooManyShares:       exitUsage,
+	codeExcessShares:        exitUsage,
+	codeInvalidSecretLength: exitUsage,
+	codeParse:               exitParse,
+	codeInsufficientShares:  exitRecovery,
+	codeChecksum:            exitRecovery,
+	codeVerify:              exitRecovery,
+	codeNotRecoverable:      exitRecovery,
+	codeCombine:             exitRecovery,
+	codePartialRecovery:     exitRecovery,
+	codeCommitMismatch:      exitRecovery,
+	codeAuth:                exitRecovery,
+	codeInsufficientHolders: exitRecovery,
+	codeTimeout:             exitTimeout,
+}
+
+// cliError is a failure reported to the user: a stable code, the localized
+// message and, when caused by another error, its text as detail
+type cliError struct {
+	Code    string `json:"error"`
+	Message string `json:"message"`
+	Detail  string `json:"detail,omitempty"`
+
+	err error
+}
+
+func (e *cliError) Error() string {
+	return e.Message
+}
+
+// Unwrap returns the error that caused the failure, if any
+func (e *cliError) Unwrap() error {
+	return e.err
+}
+
+// exitCode returns the process exit code for err. A command run by combine
+// --exec that fails passes on its own exit code.
+func exitCode(err error) int {
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode() > 0 {
+		return exitErr.ExitCode()
+	}
+
+	var e *cliError
+	if errors.As(err, &e) {
+		if code, ok := exitCodes[e.Code]; ok {
+			return code
+		}
+	}
+	return exitFailure
+}
+
+// newError builds a cliError from the catalog message id. When the last
+// argument is an error its text becomes the detail and it is kept as the
+// cause.
+func newError(code, id string, args ...interface{}) *cliError {
+	e := &cliError{Code: code, Message: tr(id, args...)}
+	if len(args) > 0 {
+		if err, ok := args[len(args)-1].(error); ok {
+			e.Detail = err.Error()
+			e.err = err
+		}
+	}
+	return e
+}
+
+// newUsageError wraps an error about the command line, such as an unknown
+// flag or a wrong number of arguments
+func newUsageError(err error) *cliError {
+	return &cliError{Code: codeUsage, Message: err.Error()}
+}
+
+// errorCode returns the code matching a sentinel error of the shamir
+// package, or fallback when err is none of them
+func errorCode(err error, fallback string) string {
+	switch {
+	case errors.Is(err, shamir.ErrThresholdTooSmall):
+		return codeThresholdTooSmall
+	case errors.Is(err, shamir.ErrThresholdTooLarge):
+		return codeThresholdTooLarge
+	case errors.Is(err, shamir.ErrTooManyShares):
+		return codeTooManyShares
+	case errors.Is(err, shamir.ErrInvalidSecretLength):
+		return codeInvalidSecretLength
+	case errors.Is(err, shamir.ErrExcessShares):
+		return codeExcessShares
+	case errors.Is(err, shamir.ErrTooFewShares):
+		return codeInsufficientShares
+	case errors.Is(err, shamir.ErrChecksumMismatch):
+		return codeChecksum
+	}
+	return fallback
+}
+
+// writeError reports err to w as a JSON object when asJSON is set, or as
+// its message otherwise
+func writeError(w io.Writer, err error, asJSON bool) {
+	if !asJSON {
+		fmt.Fprintln(w, err)
+		return
+	}
+
+	var e *cliError
+	if !errors.As(err, &e) {
+		e = newUsageError(err)
+	}
+	json.NewEncoder(w).Encode(e)
+}
+
+// fail reports err and exits with the exit code of its class. The message
+// goes to stdout, or with --json-errors a JSON object goes to stderr.
+func fail(err error) {
+	if jsonErrors {
+		writeError(os.Stderr, err, true)
+	} else {
+		writeError(os.Stdout, err, false)
+	}
+	os.Exit(exitCode(err))
+}
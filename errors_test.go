@@ -0,0 +1,163 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"shamir-cli/shamir"
+)
+
+func TestWriteErrorJSON(t *testing.T) {
+	lang = "en"
+
+	var buf bytes.Buffer
+	writeError(&buf, newError(codeInsufficientShares, "combine.below_threshold", 3, 2), true)
+
+	var got map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("Output is not JSON: %v\n%s", err, buf.String())
+	}
+
+	want := map[string]interface{}{
+		"error":   "insufficient_shares",
+		"message": "Error: 3 parts required for recovery, only 2 provided",
+	}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("JSON = %v, want %v", got, want)
+	}
+}
+
+func TestWriteErrorJSONDetail(t *testing.T) {
+	lang = "en"
+
+	_, err := shamir.Combine([]shamir.Share{{ID: 1, Value: []byte{1}}})
+	var buf bytes.Buffer
+	writeError(&buf, newError(errorCode(err, codeCombine), "combine.failed", err), true)
+
+	var got cliError
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("Output is not JSON: %v\n%s", err, buf.String())
+	}
+	if got.Code != codeInsufficientShares || got.Detail != shamir.ErrTooFewShares.Error() {
+		t.Errorf("Got %+v", got)
+	}
+
+	// Errors not built by newError are reported as usage errors
+	buf.Reset()
+	writeError(&buf, errors.New(`unknown flag: --nope`), true)
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil || got.Code != codeUsage {
+		t.Errorf("Got %+v, %v", got, err)
+	}
+
+	// Without --json-errors only the message is written
+	buf.Reset()
+	writeError(&buf, newError(codeInsufficientShares, "combine.min_parts"), false)
+	if buf.String() != "Error: minimum 2 parts required for recovery\n" {
+		t.Errorf("Text output = %q", buf.String())
+	}
+}
+
+func TestErrorCode(t *testing.T) {
+	tests := []struct {
+		err  error
+		want string
+	}{
+		{shamir.ErrThresholdTooSmall, codeThresholdTooSmall},
+		{shamir.ErrThresholdTooLarge, codeThresholdTooLarge},
+		{shamir.ErrTooManyShares, codeTooManyShares},
+		{shamir.ErrChecksumMismatch, codeChecksum},
+		{fmt.Errorf("wrapped: %w", shamir.ErrTooFewShares), codeInsufficientShares},
+		{errors.New("other"), codeCombine},
+	}
+
+	for _, tt := range tests {
+		if got := errorCode(tt.err, codeCombine); got != tt.want {
+			t.Errorf("errorCode(%v) = %q, want %q", tt.err, got, tt.want)
+		}
+	}
+}
+
+// TestExitCodes runs the binary's main in a subprocess for each case and
+// checks its exit status
+func TestExitCodes(t *testing.T) {
+	if args := os.Getenv("SHAMIR_CLI_TEST_ARGS"); args != "" {
+		os.Args = append([]string{"shamir-cli"}, strings.Split(args, "\n")...)
+		main()
+		os.Exit(0)
+	}
+
+	shares, err := shamir.Split([]byte("exit codes"), 3, 2)
+	if err != nil {
+		t.Fatalf("Split failed: %v", err)
+	}
+	valid := shamir.ShareToString(shares[0]) + "," + shamir.ShareToString(shares[1])
+
+	// A server that accepts connections but never answers
+	silent, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen failed: %v", err)
+	}
+	defer silent.Close()
+	go func() {
+		var conns []net.Conn
+		defer func() {
+			for _, c := range conns {
+				c.Close()
+			}
+		}()
+		for {
+			c, err := silent.Accept()
+			if err != nil {
+				return
+			}
+			conns = append(conns, c)
+		}
+	}()
+	silentURL := "https://" + silent.Addr().String() + "/part"
+
+	tests := []struct {
+		name string
+		args []string
+		want int
+	}{
+		{"success", []string{"combine", valid}, 0},
+		{"missing arguments", []string{"split", "secret"}, exitUsage},
+		{"unknown flag", []string{"combine", "--nope", valid}, exitUsage},
+		{"threshold too small", []string{"split", "secret", "3", "1"}, exitUsage},
+		{"unparsable part", []string{"combine", "1:zz,2:ab"}, exitParse},
+		{"checksum failure", []string{"combine", "1:ab,2:cd"}, exitRecovery},
+		{"single part", []string{"combine", "1:ab"}, exitRecovery},
+		{"not recoverable", []string{"inspect", "1:ab"}, exitRecovery},
+		{"timeout", []string{"--timeout", "1ns", "combine"}, exitTimeout},
+		{"timeout fetching a part", []string{"--timeout", "300ms", "combine", "--share-url", silentURL, "--share-url", silentURL}, exitTimeout},
+		{"unreadable file", []string{"split", "--in-file", filepath.Join(t.TempDir(), "missing"), "3", "2"}, exitFailure},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := exec.Command(os.Args[0], "-test.run=^TestExitCodes$")
+			cmd.Env = append(os.Environ(), "SHAMIR_CLI_TEST_ARGS="+strings.Join(tt.args, "\n"), "LANG=en")
+			out, err := cmd.CombinedOutput()
+
+			code := 0
+			var exitErr *exec.ExitError
+			if errors.As(err, &exitErr) {
+				code = exitErr.ExitCode()
+			} else if err != nil {
+				t.Fatalf("Running %v failed: %v", tt.args, err)
+			}
+
+			if code != tt.want {
+				t.Errorf("%v exited with %d, want %d\n%s", tt.args, code, tt.want, out)
+			}
+		})
+	}
+}
@@ -0,0 +1,32 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// execWithSecret runs command with the secret on its standard input, so
+// the secret is neither printed nor written to a file. The command is split
+// at spaces and run without a shell. Its output goes to out and stderr; a
+// nonzero exit status is kept in the returned error so that combine exits
+// with it.
+func execWithSecret(ctx context.Context, out io.Writer, command string, secret []byte) error {
+	args := strings.Fields(command)
+	if len(args) == 0 {
+		return newError(codeUsage, "combine.exec_empty")
+	}
+
+	c := exec.CommandContext(ctx, args[0], args[1:]...)
+	c.Stdin = bytes.NewReader(secret)
+	c.Stdout = out
+	c.Stderr = os.Stderr
+	if err := c.Run(); err != nil {
+		return newError(codeExec, "combine.exec_failed", args[0], err)
+	}
+	logger.Info("secret passed to command", "command", args[0], "secret_len", len(secret))
+	return nil
+}
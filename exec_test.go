@@ -0,0 +1,55 @@
+package main
+
+import (
+	"io"
+	"os"
+	"strconv"
+	"testing"
+
+	"shamir-cli/shamir"
+)
+
+// TestExecHelperProcess is not a real test: run by combine --exec in the
+// tests below, it echoes its stdin and exits with $EXEC_HELPER_EXIT
+func TestExecHelperProcess(t *testing.T) {
+	if os.Getenv("EXEC_HELPER") != "1" {
+		return
+	}
+	data, _ := io.ReadAll(os.Stdin)
+	os.Stdout.WriteString("child got: " + string(data) + "\n")
+	code, _ := strconv.Atoi(os.Getenv("EXEC_HELPER_EXIT"))
+	os.Exit(code)
+}
+
+func TestCombineExec(t *testing.T) {
+	shares, err := shamir.Split([]byte("piped secret"), 3, 2)
+	if err != nil {
+		t.Fatalf("Split failed: %v", err)
+	}
+	parts := shamir.ShareToString(shares[0]) + "," + shamir.ShareToString(shares[1])
+	helper := os.Args[0] + " -test.run=^TestExecHelperProcess$"
+	t.Setenv("EXEC_HELPER", "1")
+
+	out, err := executeCommand(t, "", "combine", "--exec", helper, parts)
+	if err != nil {
+		t.Fatalf("combine --exec failed: %v", err)
+	}
+	if out != "child got: piped secret\n" {
+		t.Errorf("combine --exec output = %q", out)
+	}
+
+	// The exit status of the command is passed on
+	t.Setenv("EXEC_HELPER_EXIT", "7")
+	_, err = executeCommand(t, "", "combine", "--exec", helper, parts)
+	if err == nil || exitCode(err) != 7 {
+		t.Errorf("combine --exec returned %v with exit code %d, want 7", err, exitCode(err))
+	}
+
+	_, err = executeCommand(t, "", "combine", "--exec", " ", parts)
+	if exitCode(err) != exitUsage {
+		t.Errorf("combine --exec with an empty command returned %v", err)
+	}
+	if _, err = executeCommand(t, "", "combine", "--exec", "shamir-cli-no-such-command", parts); err == nil {
+		t.Error("combine --exec with a missing command succeeded")
+	}
+}
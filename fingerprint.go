@@ -0,0 +1,81 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"shamir-cli/shamir"
+
+	"github.com/spf13/cobra"
+)
+
+// fingerprintDigits is the number of hex digits of the short fingerprint
+// printed by split --rich and inspect
+const fingerprintDigits = 4
+
+// shareFingerprint returns the short fingerprint of a share, the first
+// digits of shamir.ShareFingerprint as printed by the fingerprint command.
+// Holders note it down at split time and compare it with the one inspect
+// prints to confirm they kept the right part. Sixteen bits of a hash
+// reveal nothing usable about the value.
+func shareFingerprint(share shamir.Share) string {
+	return shamir.ShareFingerprint(share)[:fingerprintDigits]
+}
+
+// shareFingerprints lists the fingerprints of the shares as "ID=fingerprint"
+func shareFingerprints(shares []shamir.Share) string {
+	list := make([]string, len(shares))
+	for i, share := range shares {
+		list[i] = fmt.Sprintf("%d=%s", share.ID, shareFingerprint(share))
+	}
+	return strings.Join(list, ", ")
+}
+
+// printRichShares prints each part with its fingerprint and a note on
+// checking it, for a holder reading the parts off a terminal
+func printRichShares(out io.Writer, shares []shamir.Share, k int, format string) {
+	for i, share := range shares {
+		fingerprint := shareFingerprint(share)
+		fmt.Fprintln(out, tr("split.part_rich", i+1, colorize(out, colorBold, fingerprint), encodeShare(share, k, format)))
+		fmt.Fprintln(out, tr("split.rich_note", share.ID, fingerprint))
+	}
+}
+
+var fingerprintCmd = &cobra.Command{
+	Use:   "fingerprint [parts_separated_by_commas]",
+	Short: "Print a short non-secret fingerprint of each part",
+	Long: `Prints the fingerprint of each part: the first 8 hex digits of the
+SHA-256 of its ID and value. A fingerprint does not reveal the part, so
+holders can read theirs out, e.g. over the phone, to confirm they hold an
+intact copy of the right part. The first 4 digits are the fingerprint shown
+by "split --rich" and inspect. Parts are read from standard input when no
+argument is given.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		out := cmd.OutOrStdout()
+		ctx, cancel := commandContext(cmd)
+		defer cancel()
+
+		input, err := readShareInput(ctx, cmd.InOrStdin(), args)
+		if err != nil {
+			return newError(codeIO, "parse.read_failed", err)
+		}
+		shares, _, err := parseShareInput(input)
+		if err != nil {
+			return err
+		}
+		if len(shares) == 0 {
+			return newError(codeInsufficientShares, "parse.no_parts")
+		}
+
+		for _, share := range shares {
+			if share.Label != "" {
+				fmt.Fprintln(out, tr("fingerprint.part_labeled", share.ID, share.Label, shamir.ShareFingerprint(share)))
+			} else {
+				fmt.Fprintln(out, tr("fingerprint.part", share.ID, shamir.ShareFingerprint(share)))
+			}
+		}
+		return nil
+	},
+}
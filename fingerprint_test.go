@@ -0,0 +1,139 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"shamir-cli/shamir"
+)
+
+func TestShareFingerprint(t *testing.T) {
+	share := shamir.Share{ID: 1, Value: []byte{0xab, 0xcd}}
+	got := shareFingerprint(share)
+	if len(got) != fingerprintDigits || strings.Trim(got, "0123456789abcdef") != "" {
+		t.Fatalf("shareFingerprint() = %q, want %d hex digits", got, fingerprintDigits)
+	}
+	if again := shareFingerprint(shamir.Share{ID: 1, Value: []byte{0xab, 0xcd}}); again != got {
+		t.Errorf("shareFingerprint() = %q, then %q for the same share", got, again)
+	}
+
+	// The label and version are not hashed, but ID and value are
+	labeled := share
+	labeled.Label = "alice"
+	if shareFingerprint(labeled) != got {
+		t.Error("shareFingerprint() depends on the label")
+	}
+	for _, other := range []shamir.Share{
+		{ID: 2, Value: []byte{0xab, 0xcd}},
+		{ID: 1, Value: []byte{0xab, 0xce}},
+	} {
+		if shareFingerprint(other) == got {
+			t.Errorf("shareFingerprint(%+v) = %q, same as for %+v", other, got, share)
+		}
+	}
+
+	if full := shamir.ShareFingerprint(share); !strings.HasPrefix(full, got) {
+		t.Errorf("shareFingerprint() = %q, not a prefix of %q", got, full)
+	}
+
+	// Parsing the text form gives back the same fingerprint
+	parsed, err := shamir.StringToShare(shamir.ShareToString(share))
+	if err != nil || shareFingerprint(parsed) != got {
+		t.Errorf("Fingerprint of the parsed share = %q, %v, want %q", shareFingerprint(parsed), err, got)
+	}
+}
+
+func TestSplitRich(t *testing.T) {
+	share := shamir.Share{ID: 1, Value: []byte{0xab, 0xcd}}
+
+	// Output that is not a terminal stays plain
+	out, err := executeCommand(t, "", "split", "--rich", "secret", "3", "2")
+	if err != nil {
+		t.Fatalf("split --rich failed: %v", err)
+	}
+	if !strings.Contains(out, "Part 1: 1:") || strings.Contains(out, "fingerprint") {
+		t.Errorf("Unexpected split --rich output:\n%s", out)
+	}
+
+	// On a terminal each part is followed by its fingerprint
+	saved := stdoutIsTerminal
+	stdoutIsTerminal = func() bool { return true }
+	out, err = executeCommand(t, "", "split", "--rich", "secret", "3", "2")
+	stdoutIsTerminal = saved
+	if err != nil {
+		t.Fatalf("split --rich on a terminal failed: %v", err)
+	}
+	var parts []string
+	for _, line := range strings.Split(out, "\n") {
+		if rest, ok := strings.CutPrefix(line, "Part "); ok {
+			_, part, _ := strings.Cut(rest, "]: ")
+			parts = append(parts, part)
+			parsed, err := shamir.StringToShare(part)
+			if err != nil || !strings.HasPrefix(rest, fmt.Sprintf("%d [%s]: ", parsed.ID, shareFingerprint(parsed))) {
+				t.Errorf("Unexpected rich part line %q: %v", line, err)
+			}
+		}
+	}
+	if len(parts) != 3 || strings.Count(out, "Note fingerprint") != 3 {
+		t.Fatalf("Unexpected split --rich output on a terminal:\n%s", out)
+	}
+	out, err = executeCommand(t, "", "combine", parts[0]+","+parts[1])
+	if err != nil || out != "Recovered secret: secret\n" {
+		t.Errorf("combine of rich parts = %q, %v", out, err)
+	}
+
+	var buf bytes.Buffer
+	printRichShares(&buf, []shamir.Share{share}, 2, formatText)
+	if want := "Part 1 [" + shareFingerprint(share) + "]: 1:abcd\n"; !strings.HasPrefix(buf.String(), want) {
+		t.Errorf("printRichShares() = %q, want prefix %q", buf.String(), want)
+	}
+
+	// inspect lists the fingerprints to compare with
+	out, _ = executeCommand(t, "", "inspect", "1:abcd,2:1234")
+	if !strings.Contains(out, "Fingerprints    1="+shareFingerprint(share)+", 2=") {
+		t.Errorf("inspect output lacks the fingerprints:\n%s", out)
+	}
+
+	for _, args := range [][]string{
+		{"split", "--rich", "--format", "armor", "secret", "3", "2"},
+		{"split", "--rich", "--field", "gf16", "secret", "3", "2"},
+		{"split", "--rich", "--out-dir", t.TempDir(), "secret", "3", "2"},
+	} {
+		_, err := executeCommand(t, "", args...)
+		var e *cliError
+		if !errors.As(err, &e) || e.Code != codeUsage {
+			t.Errorf("%v returned %v, want error code %q", args, err, codeUsage)
+		}
+	}
+}
+
+func TestFingerprintCommand(t *testing.T) {
+	out, err := executeCommand(t, "", "fingerprint", "1:abcd,2#bob:1234")
+	if err != nil {
+		t.Fatalf("fingerprint failed: %v", err)
+	}
+	want := "Part 1: " + shamir.ShareFingerprint(shamir.Share{ID: 1, Value: []byte{0xab, 0xcd}}) + "\n" +
+		"Part 2 (bob): " + shamir.ShareFingerprint(shamir.Share{ID: 2, Value: []byte{0x12, 0x34}}) + "\n"
+	if out != want {
+		t.Errorf("fingerprint output = %q, want %q", out, want)
+	}
+
+	// The same part in the compact form, read from standard input, has the
+	// same fingerprint
+	out, err = executeCommand(t, "01abcd\n", "fingerprint")
+	if err != nil {
+		t.Fatalf("fingerprint of standard input failed: %v", err)
+	}
+	if first, _, _ := strings.Cut(want, "\n"); out != first+"\n" {
+		t.Errorf("fingerprint output = %q, want %q", out, first+"\n")
+	}
+
+	_, err = executeCommand(t, "\n", "fingerprint")
+	var e *cliError
+	if !errors.As(err, &e) || e.Code != codeInsufficientShares {
+		t.Errorf("fingerprint without parts returned %v, want error code %q", err, codeInsufficientShares)
+	}
+}
@@ -2,9 +2,18 @@ module shamir-cli
 
 go 1.21
 
-require github.com/spf13/cobra v1.8.0
+require (
+	github.com/atotto/clipboard v0.1.4
+	github.com/spf13/cobra v1.8.0
+	github.com/spf13/pflag v1.0.5
+	golang.org/x/crypto v0.16.0
+	golang.org/x/term v0.15.0
+)
 
 require (
+	github.com/cpuguy83/go-md2man/v2 v2.0.3 // indirect
 	github.com/inconshreveable/mousetrap v1.1.0 // indirect
-	github.com/spf13/pflag v1.0.5 // indirect
+	github.com/russross/blackfriday/v2 v2.1.0 // indirect
+	golang.org/x/sys v0.15.0 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
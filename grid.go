@@ -0,0 +1,165 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"shamir-cli/shamir"
+
+	"github.com/spf13/cobra"
+)
+
+// gridHeader is the header row of parts printed with --format table
+var gridHeader = []string{"ID", "Value", "CRC"}
+
+// gridCRC returns the CRC of a table row, computed over the part in the
+// form "ID:hex". It is printed as two hex digits at the end of the row, so a
+// mistake in copying a row by hand is found by recomputing the CRC of that
+// row alone.
+func gridCRC(id byte, value []byte) byte {
+	return crc8([]byte(fmt.Sprintf("%d:%x", id, value)))
+}
+
+// crc8 returns the CRC-8 of data with the polynomial 0x07, which detects
+// any change confined to a single byte
+func crc8(data []byte) byte {
+	var crc byte
+	for _, b := range data {
+		crc ^= b
+		for i := 0; i < 8; i++ {
+			if crc&0x80 != 0 {
+				crc = crc<<1 ^ 0x07
+			} else {
+				crc <<= 1
+			}
+		}
+	}
+	return crc
+}
+
+// writeSharesGrid writes the shares as a table with one row of ID, hex
+// value and CRC per share
+func writeSharesGrid(w io.Writer, shares []shamir.Share) error {
+	idWidth, valueWidth := len(gridHeader[0]), len(gridHeader[1])
+	for _, share := range shares {
+		idWidth = max(idWidth, len(strconv.Itoa(int(share.ID))))
+		valueWidth = max(valueWidth, 2*len(share.Value))
+	}
+
+	rows := []string{
+		fmt.Sprintf("| %-*s | %-*s | %s |", idWidth, gridHeader[0], valueWidth, gridHeader[1], gridHeader[2]),
+		fmt.Sprintf("|-%s-|-%s-|-----|", strings.Repeat("-", idWidth), strings.Repeat("-", valueWidth)),
+	}
+	for _, share := range shares {
+		rows = append(rows, fmt.Sprintf("| %*d | %-*x | %02x  |", idWidth, share.ID, valueWidth, share.Value, gridCRC(share.ID, share.Value)))
+	}
+	for _, row := range rows {
+		if _, err := fmt.Fprintln(w, row); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// gridRow is a part read back from a table with the CRC written next to it
+type gridRow struct {
+	line  int
+	share shamir.Share
+	crc   byte
+}
+
+// readSharesGrid reads the rows of a table written by writeSharesGrid.
+// Lines not starting with "|", the header row and the separator are
+// skipped, as are spaces within the value, which may be grouped when copied
+// by hand. The CRCs are returned as written, not checked.
+func readSharesGrid(input string) ([]gridRow, error) {
+	var rows []gridRow
+	for i, line := range strings.Split(input, "\n") {
+		line = strings.TrimSpace(line)
+		if !strings.HasPrefix(line, "|") {
+			continue
+		}
+
+		fields := strings.Split(strings.Trim(line, "|"), "|")
+		if len(fields) != len(gridHeader) {
+			return nil, fmt.Errorf("line %d: expected %d columns, got %d", i+1, len(gridHeader), len(fields))
+		}
+		for j := range fields {
+			fields[j] = strings.TrimSpace(fields[j])
+		}
+		if strings.EqualFold(fields[0], gridHeader[0]) || strings.Trim(fields[0], "-") == "" {
+			continue
+		}
+
+		if _, err := strconv.ParseUint(fields[0], 10, 8); err != nil {
+			return nil, fmt.Errorf("line %d: invalid ID %q", i+1, fields[0])
+		}
+		share, err := shamir.StringToShare(fields[0] + ":" + strings.Join(strings.Fields(fields[1]), ""))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", i+1, err)
+		}
+		crc, err := strconv.ParseUint(fields[2], 16, 8)
+		if err != nil || len(fields[2]) != 2 {
+			return nil, fmt.Errorf("line %d: invalid CRC %q, want 2 hex digits", i+1, fields[2])
+		}
+		rows = append(rows, gridRow{line: i + 1, share: share, crc: byte(crc)})
+	}
+	if len(rows) == 0 {
+		return nil, errors.New("no table rows found")
+	}
+	return rows, nil
+}
+
+var verifyTableCmd = &cobra.Command{
+	Use:   "verify-table [file]",
+	Short: "Check the per-row CRCs of parts copied from a table",
+	Long: `Recomputes the CRC of every row of a table printed by
+"split --format table", e.g. after copying it from paper, and reports the
+rows whose CRC does not match, which contain a transcription error. The
+CRC only protects each row against copying mistakes; whether the parts
+recover the secret is checked by combine. The table is read from standard
+input when no file is given.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		out := cmd.OutOrStdout()
+		ctx, cancel := commandContext(cmd)
+		defer cancel()
+
+		var data []byte
+		var err error
+		if len(args) > 0 {
+			data, err = readFileContext(ctx, args[0])
+		} else {
+			data, err = readAllContext(ctx, cmd.InOrStdin())
+		}
+		if err != nil {
+			return newError(codeIO, "parse.read_failed", err)
+		}
+
+		rows, err := readSharesGrid(string(data))
+		if err != nil {
+			return newError(codeParse, "parse.table", err)
+		}
+
+		failed := 0
+		for _, row := range rows {
+			crc := gridCRC(row.share.ID, row.share.Value)
+			if crc != row.crc {
+				failed++
+				fmt.Fprintln(out, tr("verify_table.mismatch", row.line, row.share.ID, row.crc, crc))
+			} else {
+				fmt.Fprintln(out, tr("verify_table.ok", row.line, row.share.ID))
+			}
+		}
+
+		fmt.Fprintln(out)
+		if failed > 0 {
+			return newError(codeVerify, "verify_table.failed", failed, len(rows))
+		}
+		fmt.Fprintln(out, tr("verify_table.passed", len(rows)))
+		return nil
+	},
+}
@@ -0,0 +1,122 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"shamir-cli/shamir"
+)
+
+func TestGridCRC(t *testing.T) {
+	// Check value of CRC-8 with polynomial 0x07
+	if got := crc8([]byte("123456789")); got != 0xf4 {
+		t.Errorf("crc8 check value = %02x, want f4", got)
+	}
+
+	// Any single mistyped digit of a row changes its CRC
+	value := []byte{0xde, 0xad, 0xbe, 0xef}
+	want := gridCRC(7, value)
+	row := fmt.Sprintf("7:%x", value)
+	for i := range row {
+		for _, c := range "0123456789abcdef:" {
+			if byte(c) == row[i] {
+				continue
+			}
+			typo := row[:i] + string(c) + row[i+1:]
+			if crc8([]byte(typo)) == want {
+				t.Errorf("CRC of %q equals that of %q", typo, row)
+			}
+		}
+	}
+}
+
+func TestGridRoundTrip(t *testing.T) {
+	shares, err := shamir.Split([]byte("paper"), 12, 3)
+	if err != nil {
+		t.Fatalf("Split failed: %v", err)
+	}
+
+	var b strings.Builder
+	if err := writeSharesGrid(&b, shares); err != nil {
+		t.Fatalf("writeSharesGrid failed: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(b.String()), "\n")
+	if len(lines) != 14 || !strings.HasPrefix(lines[0], "| ID | Value") || !strings.HasPrefix(lines[2], "|  1 | ") {
+		t.Fatalf("Unexpected table:\n%s", b.String())
+	}
+
+	// Surrounding text is ignored and values may be grouped by spaces
+	input := "Secret split into 12 parts\n\n" + strings.Replace(b.String(), fmt.Sprintf("%x", shares[0].Value[:2]), fmt.Sprintf("%x %x", shares[0].Value[:1], shares[0].Value[1:2]), 1)
+	rows, err := readSharesGrid(input)
+	if err != nil {
+		t.Fatalf("readSharesGrid failed: %v", err)
+	}
+	if len(rows) != len(shares) {
+		t.Fatalf("readSharesGrid returned %d rows, want %d", len(rows), len(shares))
+	}
+	for i, row := range rows {
+		if !row.share.Equal(shares[i]) || row.crc != gridCRC(shares[i].ID, shares[i].Value) {
+			t.Errorf("Row %d = %+v, want %+v", i+1, row, shares[i])
+		}
+	}
+
+	for _, input := range []string{
+		"",
+		"no table here",
+		"| 1 | abcd |",
+		"| x | abcd | 00 |",
+		"| 1 | abzz | 00 |",
+		"| 1 | abcd | 0 |",
+		"| 1 | abcd | zz |",
+	} {
+		if _, err := readSharesGrid(input); err == nil {
+			t.Errorf("readSharesGrid(%q) should fail", input)
+		}
+	}
+}
+
+func TestVerifyTableCommand(t *testing.T) {
+	out, err := executeCommand(t, "", "split", "--format", "table", "--verify", "on paper", "3", "2")
+	if err != nil {
+		t.Fatalf("split --format table failed: %v", err)
+	}
+	path := filepath.Join(t.TempDir(), "table.txt")
+	if err := os.WriteFile(path, []byte(out), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	out, err = executeCommand(t, "", "verify-table", path)
+	if err != nil {
+		t.Fatalf("verify-table failed: %v\n%s", err, out)
+	}
+	if !strings.Contains(out, "All 3 rows verified") {
+		t.Errorf("Unexpected output:\n%s", out)
+	}
+
+	// A typo in the value of part 2
+	data, _ := os.ReadFile(path)
+	lines := strings.Split(string(data), "\n")
+	for i, line := range lines {
+		if strings.HasPrefix(line, "|  2 | ") || strings.HasPrefix(line, "| 2  | ") {
+			digit := line[7]
+			typo := byte('0')
+			if digit == '0' {
+				typo = '1'
+			}
+			lines[i] = line[:7] + string(typo) + line[8:]
+		}
+	}
+
+	out, err = executeCommand(t, strings.Join(lines, "\n"), "verify-table")
+	var e *cliError
+	if !errors.As(err, &e) || e.Code != codeVerify {
+		t.Errorf("verify-table with a typo returned %v, want error code %q", err, codeVerify)
+	}
+	if !strings.Contains(out, "(part 2): CRC mismatch") || !strings.Contains(out, "(part 3): OK") {
+		t.Errorf("Unexpected output:\n%s", out)
+	}
+}
@@ -0,0 +1,134 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"shamir-cli/shamir"
+
+	"github.com/spf13/cobra"
+)
+
+var inspectThreshold int
+
+var inspectCmd = &cobra.Command{
+	Use:   "inspect [parts_separated_by_commas]",
+	Short: "Check whether a set of parts can recover a secret",
+	Long: `Audits a set of parts without revealing the secret: reports how many distinct
+IDs are present, whether all parts have the same length and whether the parts
+reconstruct a value that passes checksum verification, ending with a
+recoverable yes/no verdict. When the threshold is neither recorded in the parts
+nor given with --threshold, it is inferred from the smallest number of parts
+that recover the same secret. The recovered secret is never printed. Parts are
+read from standard input when no argument is given.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		out := cmd.OutOrStdout()
+		ctx, cancel := commandContext(cmd)
+		defer cancel()
+
+		input, err := readShareInput(ctx, cmd.InOrStdin(), args)
+		if err != nil {
+			return newError(codeIO, "parse.read_failed", err)
+		}
+
+		shares, threshold, err := parseShareInput(input)
+		if err != nil {
+			return err
+		}
+		if inspectThreshold > 0 {
+			threshold = inspectThreshold
+		}
+
+		if len(shares) == 0 {
+			return newError(codeInsufficientShares, "parse.no_parts")
+		}
+
+		// Collect distinct IDs and lengths
+		seen := make(map[byte]bool)
+		ids := make([]int, 0, len(shares))
+		lengths := make(map[int]bool)
+		for _, share := range shares {
+			if !seen[share.ID] {
+				seen[share.ID] = true
+				ids = append(ids, int(share.ID))
+			}
+			lengths[len(share.Value)] = true
+		}
+		sort.Ints(ids)
+
+		idList := make([]string, len(ids))
+		for i, id := range ids {
+			idList[i] = fmt.Sprint(id)
+		}
+
+		lengthsMatch := len(lengths) == 1
+		recoverable := lengthsMatch && len(ids) >= 2
+
+		w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, tr("inspect.header"))
+		fmt.Fprintln(w, tr("inspect.parts", len(shares)))
+		fmt.Fprintln(w, tr("inspect.ids", len(ids), strings.Join(idList, ", ")))
+		fmt.Fprintln(w, tr("inspect.fingerprints", shareFingerprints(shares)))
+		if len(ids) != len(shares) {
+			fmt.Fprintln(w, tr("inspect.duplicates", len(shares)-len(ids)))
+		}
+		if lengthsMatch {
+			fmt.Fprintln(w, tr("inspect.lengths_match", len(shares[0].Value)))
+		} else {
+			fmt.Fprintln(w, tr("inspect.lengths_mismatch", len(lengths)))
+		}
+
+		if threshold > 0 {
+			if len(ids) >= threshold {
+				fmt.Fprintln(w, tr("inspect.threshold_met", threshold))
+			} else {
+				fmt.Fprintln(w, tr("inspect.threshold_missing", threshold, threshold-len(ids)))
+				recoverable = false
+			}
+		}
+
+		if recoverable {
+			unique := make([]shamir.Share, 0, len(ids))
+			used := make(map[byte]bool)
+			for _, share := range shares {
+				if !used[share.ID] {
+					used[share.ID] = true
+					unique = append(unique, share)
+				}
+			}
+
+			secret, err := shamir.Combine(unique)
+			if err != nil {
+				fmt.Fprintln(w, tr("inspect.recovery_failed", err))
+				recoverable = false
+			} else {
+				fmt.Fprintln(w, tr("inspect.recovery_ok", len(secret)))
+				if threshold == 0 {
+					if k, err := shamir.InferThreshold(unique); err == nil {
+						fmt.Fprintln(w, tr("inspect.threshold_inferred", k, len(unique)))
+					}
+				}
+			}
+		} else {
+			fmt.Fprintln(w, tr("inspect.recovery_skipped"))
+		}
+		if recoverable {
+			fmt.Fprintln(w, tr("inspect.recoverable_yes"))
+		} else {
+			fmt.Fprintln(w, tr("inspect.recoverable_no"))
+		}
+		w.Flush()
+
+		if !recoverable {
+			return newError(codeNotRecoverable, "inspect.not_recoverable")
+		}
+		return nil
+	},
+}
+
+func init() {
+	inspectCmd.Flags().IntVarP(&inspectThreshold, "threshold", "k", 0, "expected number of parts required for recovery")
+}
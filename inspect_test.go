@@ -0,0 +1,63 @@
+package main
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"shamir-cli/shamir"
+)
+
+func TestInspect(t *testing.T) {
+	// The CRC-32 keeps too few parts from passing the checksum by chance
+	shares, err := shamir.SplitWithChecksum([]byte("inspected"), 5, 3, shamir.CRC32Checksum{})
+	if err != nil {
+		t.Fatalf("SplitWithChecksum failed: %v", err)
+	}
+	var parts []string
+	for _, share := range shares {
+		parts = append(parts, shamir.ShareToString(share))
+	}
+
+	out, err := executeCommand(t, "", "inspect", strings.Join(parts, ","))
+	if err != nil {
+		t.Fatalf("inspect of the complete set failed: %v\n%s", err, out)
+	}
+	for _, want := range []string{"Distinct IDs    5 (1, 2, 3, 4, 5)", "Lengths match   yes", "Reconstruction  OK", "Threshold       3 (inferred from 5 parts)", "Recoverable     yes"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("inspect output lacks %q:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "inspected") {
+		t.Errorf("inspect revealed the secret:\n%s", out)
+	}
+
+	// A given threshold is reported as such, not inferred
+	out, err = executeCommand(t, "", "inspect", "-k", "3", strings.Join(parts[1:4], ","))
+	if err != nil {
+		t.Fatalf("inspect -k 3 failed: %v\n%s", err, out)
+	}
+	if !strings.Contains(out, "Threshold       3 (met)") || strings.Contains(out, "inferred") {
+		t.Errorf("Unexpected inspect output:\n%s", out)
+	}
+
+	incomplete := []struct {
+		name string
+		args []string
+	}{
+		{"too few parts", []string{"inspect", parts[0] + "," + parts[4]}},
+		{"below given threshold", []string{"inspect", "-k", "4", strings.Join(parts[:3], ",")}},
+	}
+	for _, tt := range incomplete {
+		t.Run(tt.name, func(t *testing.T) {
+			out, err := executeCommand(t, "", tt.args...)
+			var e *cliError
+			if !errors.As(err, &e) || e.Code != codeNotRecoverable {
+				t.Errorf("%v returned %v, want error code %q", tt.args, err, codeNotRecoverable)
+			}
+			if !strings.Contains(out, "Recoverable     no") || strings.Contains(out, "inferred") {
+				t.Errorf("Unexpected inspect output:\n%s", out)
+			}
+		})
+	}
+}
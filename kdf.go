@@ -0,0 +1,128 @@
+package main
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"math/bits"
+	"strconv"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/scrypt"
+)
+
+// KDFs accepted by split --kdf
+const (
+	kdfNone     = "none"
+	kdfArgon2id = "argon2id"
+	kdfScrypt   = "scrypt"
+)
+
+// kdfSalt is the fixed salt of the seed KDFs. The parts must be reproducible
+// from the seed and the KDF parameters alone, so there is no random salt to
+// record; the memory-hard KDF is what makes guessing seeds expensive.
+var kdfSalt = []byte("shamir-cli seed")
+
+// Fixed parameters of the seed KDFs, recorded with the configurable ones
+const (
+	argon2Threads = 4
+	scryptR       = 8
+	scryptP       = 1
+)
+
+// kdfParams are the parameters of the KDF deriving the random coefficients
+// from the seed given to split --seed
+type kdfParams struct {
+	name string
+	// memKiB is the memory cost in KiB; for scrypt it is rounded down so
+	// that N is a power of two
+	memKiB uint32
+	// time is the number of passes of argon2id; scrypt has none
+	time uint32
+}
+
+// newKDFParams validates the --kdf, --kdf-mem and --kdf-time flags
+func newKDFParams(name, mem string, time int) (kdfParams, error) {
+	memKiB, err := parseMemSize(mem)
+	if err != nil {
+		return kdfParams{}, err
+	}
+	switch name {
+	case kdfArgon2id:
+		if memKiB < 8*argon2Threads {
+			return kdfParams{}, fmt.Errorf("argon2id needs at least %d KB of memory", 8*argon2Threads)
+		}
+		if time < 1 {
+			return kdfParams{}, fmt.Errorf("invalid number of passes %d", time)
+		}
+		return kdfParams{name: name, memKiB: memKiB, time: uint32(time)}, nil
+	case kdfScrypt:
+		// scrypt uses 128*r*N bytes
+		n := uint64(memKiB) * 1024 / (128 * scryptR)
+		if n < 2 {
+			return kdfParams{}, fmt.Errorf("scrypt needs at least %d KB of memory", 2*128*scryptR/1024)
+		}
+		n = 1 << (bits.Len64(n) - 1)
+		return kdfParams{name: name, memKiB: uint32(n * 128 * scryptR / 1024)}, nil
+	default:
+		return kdfParams{}, fmt.Errorf("unknown KDF %q, expected %s, %s or %s", name, kdfNone, kdfArgon2id, kdfScrypt)
+	}
+}
+
+// parseMemSize parses a memory size such as 64MB, 512KB or 1GB into KiB.
+// The units are binary: 1MB is 1024 KB.
+func parseMemSize(s string) (uint32, error) {
+	upper := strings.ToUpper(strings.TrimSpace(s))
+	for _, unit := range []struct {
+		suffix string
+		kib    uint64
+	}{{"GB", 1 << 20}, {"MB", 1 << 10}, {"KB", 1}} {
+		number, ok := strings.CutSuffix(upper, unit.suffix)
+		if !ok {
+			continue
+		}
+		n, err := strconv.ParseUint(strings.TrimSpace(number), 10, 32)
+		if err != nil || n == 0 || n*unit.kib > 1<<32-1 {
+			break
+		}
+		return uint32(n * unit.kib), nil
+	}
+	return 0, fmt.Errorf("invalid memory size %q, expected e.g. 64MB", s)
+}
+
+// seedKDF holds the parameters of the KDF used by the current split, or
+// the zero value without --kdf, for encodeShare to record in armored parts
+var seedKDF kdfParams
+
+// String returns the parameters as recorded in the KDF header of armored
+// parts, e.g. "argon2id m=65536 t=3 p=4" with the memory in KiB, or "" for
+// the zero value
+func (p kdfParams) String() string {
+	switch p.name {
+	case "":
+		return ""
+	case kdfScrypt:
+		return fmt.Sprintf("scrypt N=%d r=%d p=%d", uint64(p.memKiB)*1024/(128*scryptR), scryptR, scryptP)
+	default:
+		return fmt.Sprintf("argon2id m=%d t=%d p=%d", p.memKiB, p.time, argon2Threads)
+	}
+}
+
+// reader returns the deterministic random stream for seed, keyed by the
+// KDF of the seed instead of its SHA-256
+func (p kdfParams) reader(seed string) (io.Reader, error) {
+	var key [sha256.Size]byte
+	switch p.name {
+	case kdfScrypt:
+		n := int(uint64(p.memKiB) * 1024 / (128 * scryptR))
+		derived, err := scrypt.Key([]byte(seed), kdfSalt, n, scryptR, scryptP, len(key))
+		if err != nil {
+			return nil, err
+		}
+		copy(key[:], derived)
+	default:
+		copy(key[:], argon2.IDKey([]byte(seed), kdfSalt, p.time, p.memKiB, argon2Threads, uint32(len(key))))
+	}
+	return newKeyedReader(key), nil
+}
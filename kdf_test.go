@@ -0,0 +1,119 @@
+package main
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestKDFReproducible(t *testing.T) {
+	stream := func(p kdfParams, seed string) []byte {
+		t.Helper()
+		r, err := p.reader(seed)
+		if err != nil {
+			t.Fatalf("reader failed: %v", err)
+		}
+		buf := make([]byte, 64)
+		if _, err := io.ReadFull(r, buf); err != nil {
+			t.Fatal(err)
+		}
+		return buf
+	}
+
+	argon, err := newKDFParams(kdfArgon2id, "1MB", 1)
+	if err != nil {
+		t.Fatalf("newKDFParams failed: %v", err)
+	}
+	if argon.String() != "argon2id m=1024 t=1 p=4" {
+		t.Errorf("argon2id params = %q", argon)
+	}
+	scrypt, err := newKDFParams(kdfScrypt, "1MB", 0)
+	if err != nil {
+		t.Fatalf("newKDFParams failed: %v", err)
+	}
+	if scrypt.String() != "scrypt N=1024 r=8 p=1" {
+		t.Errorf("scrypt params = %q", scrypt)
+	}
+
+	for _, p := range []kdfParams{argon, scrypt} {
+		if !bytes.Equal(stream(p, "seed"), stream(p, "seed")) {
+			t.Errorf("%s is not reproducible", p)
+		}
+		if bytes.Equal(stream(p, "seed"), stream(p, "seed2")) {
+			t.Errorf("%s gives the same stream for different seeds", p)
+		}
+	}
+	slower := argon
+	slower.time = 2
+	if bytes.Equal(stream(argon, "seed"), stream(slower, "seed")) {
+		t.Error("argon2id gives the same stream for different passes")
+	}
+	if bytes.Equal(stream(argon, "seed"), stream(scrypt, "seed")) {
+		t.Error("argon2id and scrypt give the same stream")
+	}
+}
+
+func TestNewKDFParamsErrors(t *testing.T) {
+	for _, tt := range []struct {
+		name, mem string
+		time      int
+	}{
+		{"sha256", "64MB", 3},
+		{kdfArgon2id, "64", 3},
+		{kdfArgon2id, "0MB", 3},
+		{kdfArgon2id, "16KB", 3},
+		{kdfArgon2id, "64MB", 0},
+		{kdfArgon2id, "8192GB", 3},
+		{kdfScrypt, "1KB", 0},
+	} {
+		if p, err := newKDFParams(tt.name, tt.mem, tt.time); err == nil {
+			t.Errorf("newKDFParams(%q, %q, %d) = %s, want an error", tt.name, tt.mem, tt.time, p)
+		}
+	}
+}
+
+func TestSplitKDF(t *testing.T) {
+	args := []string{"split", "--seed", "correct horse", "--kdf", "argon2id", "--kdf-mem", "1MB", "--kdf-time", "1", "--format", "armor", "kdf secret", "3", "2"}
+	first, err := executeCommand(t, "", args...)
+	if err != nil {
+		t.Fatalf("split --kdf failed: %v", err)
+	}
+	second, err := executeCommand(t, "", args...)
+	if err != nil {
+		t.Fatalf("split --kdf failed: %v", err)
+	}
+	if first != second {
+		t.Errorf("split --kdf is not reproducible:\n%s\n%s", first, second)
+	}
+	if strings.Count(first, "KDF: argon2id m=1024 t=1 p=4\n") != 3 {
+		t.Errorf("Armored parts do not record the KDF:\n%s", first)
+	}
+
+	plain, err := executeCommand(t, "", "split", "--seed", "correct horse", "--format", "armor", "kdf secret", "3", "2")
+	if err != nil {
+		t.Fatalf("split --seed failed: %v", err)
+	}
+	if strings.Contains(plain, "KDF:") || plain == strings.ReplaceAll(first, "KDF: argon2id m=1024 t=1 p=4\n", "") {
+		t.Errorf("split --kdf gave the parts of a plain --seed:\n%s", plain)
+	}
+
+	blocks := first[strings.Index(first, "-----BEGIN"):]
+	out, err := executeCommand(t, "", "combine", "--", blocks)
+	if err != nil {
+		t.Fatalf("combine failed: %v", err)
+	}
+	if out != "Recovered secret: kdf secret\n" {
+		t.Errorf("combine output = %q", out)
+	}
+
+	for _, args := range [][]string{
+		{"split", "--kdf", "argon2id", "secret", "3", "2"},
+		{"split", "--seed", "x", "--kdf", "bcrypt", "secret", "3", "2"},
+		{"split", "--seed", "x", "--kdf", "argon2id", "--kdf-mem", "lots", "secret", "3", "2"},
+	} {
+		if _, err := executeCommand(t, "", args...); err == nil {
+			t.Errorf("%v succeeded", args)
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"io"
+	"log/slog"
+	"os"
+
+	"shamir-cli/shamir"
+)
+
+// logLevel is the minimum level of diagnostics written to stderr, set by
+// the --log-level flag
+var logLevel = "warn"
+
+// logger writes diagnostics to stderr so stdout only carries results. It
+// must never be given secret values or share values.
+var logger = newLogger(os.Stderr, slog.LevelWarn)
+
+// newLogger returns a text logger writing records of at least level to w
+func newLogger(w io.Writer, level slog.Level) *slog.Logger {
+	return slog.New(slog.NewTextHandler(w, &slog.HandlerOptions{Level: level}))
+}
+
+// setupLogger replaces logger with one at the named level
+// (debug, info, warn or error)
+func setupLogger(w io.Writer, name string) error {
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(name)); err != nil {
+		return err
+	}
+	logger = newLogger(w, level)
+	return nil
+}
+
+// shareIDs returns the IDs of shares for logging
+func shareIDs(shares []shamir.Share) []int {
+	ids := make([]int, len(shares))
+	for i, share := range shares {
+		ids[i] = int(share.ID)
+	}
+	return ids
+}
+
+// logCombineInput logs the parsed parts: their number, IDs and lengths, and
+// a warning for each repeated ID
+func logCombineInput(shares []shamir.Share, threshold int) {
+	lengths := make(map[int]int)
+	seen := make(map[byte]bool)
+	for _, share := range shares {
+		lengths[len(share.Value)]++
+		if seen[share.ID] {
+			logger.Warn("duplicate part ID", "id", share.ID)
+		}
+		seen[share.ID] = true
+	}
+
+	logger.Info("parts parsed", "count", len(shares), "threshold", threshold, "lengths", lengths)
+	logger.Debug("part IDs", "ids", shareIDs(shares))
+}
@@ -1,130 +1,1318 @@
 package main
 
 import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
 	"fmt"
+	"io"
 	"os"
+	"path/filepath"
 	"strconv"
 	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
 
 	"shamir-cli/shamir"
 
 	"github.com/spf13/cobra"
 )
 
-// version will be set by build flags
-var version = "dev"
+// Build metadata, set with -ldflags "-X main.version=... -X main.commit=...
+// -X main.buildDate=..."
+var (
+	version   = "dev"
+	commit    = "unknown"
+	buildDate = "unknown"
+)
+
+// Output formats of split
+const (
+	formatText    = "text"
+	formatArmor   = "armor"
+	formatCompact = "compact"
+	formatCSV     = "csv"
+	formatTSV     = "tsv"
+	formatEnv     = "env"
+	formatBase32  = "base32"
+	formatTable   = "table"
+
+	// formatRawBytes writes the recovered secret as is, for combine only
+	formatRawBytes = "raw-bytes"
+)
+
+var (
+	splitInteractive bool
+	splitInFile      string
+	splitSecretEnv   string
+	splitOutDir      string
+	splitQRDir       string
+	splitProgress    bool
+	splitParallel    bool
+	splitVerify      bool
+	splitVerbose     bool
+	splitPad         int
+	splitClipboard   bool
+	splitSeed        string
+	splitKDF         string
+	splitKDFMem      string
+	splitKDFTime     int
+	splitMaxSize     int
+	splitCommit      bool
+	splitAuth        bool
+	splitRich        bool
+	splitAllowEmpty  bool
+	splitFormat      string
+	splitField       string
+	splitShareIDs    []uint
+	splitLabels      []string
+
+	combineField      string
+	combineCSV        string
+	combineQRDir      string
+	combineShareURLs  []string
+	combineFromEnv    bool
+	combineOutFile    string
+	combineProgress   bool
+	combineBinary     bool
+	combineFormat     string
+	combineInfo       bool
+	combineLenient    bool
+	combineClipboard  bool
+	combineCommit     string
+	combineAuthKey    string
+	combineAuthTag    string
+	combineExec       string
+	combineOffset     int
+	combineLength     int
+	combineStrict     bool
+	combineStrictHex  bool
+	combineHolders    int
+	combineThreshold  int
+	combinePositional bool
+)
 
 var rootCmd = &cobra.Command{
 	Use:     "shamir-cli",
 	Short:   "CLI application for secret sharing using Shamir's algorithm",
 	Long:    `Application for splitting a string into parts with the ability to recover from fewer parts using Shamir's secret sharing algorithm.`,
 	Version: version,
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		if _, ok := messages[lang]; !ok {
+			return newError(codeUsage, "lang.unsupported", lang, "en, ru")
+		}
+		if err := setupLogger(cmd.ErrOrStderr(), logLevel); err != nil {
+			return newError(codeUsage, "log.invalid_level", logLevel)
+		}
+		return nil
+	},
 }
 
 var splitCmd = &cobra.Command{
 	Use:   "split [string] [total_parts] [threshold]",
 	Short: "Split a string into parts",
 	Long: `Splits the input string into the specified number of parts, where a minimum
-number of parts (threshold) is required for recovery.`,
-	Args: cobra.ExactArgs(3),
-	Run: func(cmd *cobra.Command, args []string) {
-		secret := args[0]
-		n, err := strconv.Atoi(args[1])
+number of parts (threshold) is required for recovery.
+
+With --interactive the string is not passed as an argument but read from the
+terminal with echo disabled, so it never ends up in the shell history.
+With --in-file the secret is read from a file instead, and with
+--secret-env from an environment variable, e.g. one injected by a CI
+system; the variable is removed from the environment of the process once
+read.
+
+With --out-dir each part is written to its own file in the directory
+instead of being printed, along with a manifest.json recording n, k, the
+secret length and the part files, but no part values.
+
+With --parallel the secret is split on all CPUs regardless of its size;
+secrets of 64KB and more are always split in parallel.
+
+With --verify the first k parts are encoded, parsed back and combined
+before anything is printed; if that does not yield the original secret
+the command fails instead of emitting unusable parts.
+
+With --verbose the polynomial generated for the first byte of the secret
+and its value at each part ID are printed to stderr. This reveals the
+random coefficients and with them the secret, so use it for demonstrations
+only.
+
+With --clipboard the parts are copied to the system clipboard, one per
+line, instead of being printed. Other applications can read the clipboard,
+so clear it once the parts are stored.
+
+With --seed the random coefficients are derived from the given string, so
+repeated splits produce identical parts, e.g. for tests and demos. INSECURE:
+anyone who knows or guesses the seed can recover the secret from a single
+part. Never use --seed for real secrets.
+
+With --kdf argon2id or --kdf scrypt the coefficients are derived from the
+seed with a memory-hard KDF instead of SHA-256, set by --kdf-mem and, for
+argon2id, --kdf-time. This makes guessing the seed from leaked parts
+expensive, but it is only as strong as the seed. The KDF parameters are
+printed to stderr and recorded in armored parts; the same seed, secret and
+parameters reproduce the same parts.
+
+With --pad N the secret is padded to a multiple of N bytes (1-255) before
+splitting, so the length of the parts does not reveal its exact length.
+combine removes the padding again.
+
+With --format armor each part is printed as a PEM-style armored block
+that also records the threshold. With --format compact each part is
+printed as hex(ID||Value) without a colon, so all parts have the same width.
+With --format base32 each part is printed as "ID-VALUE" with the value in
+uppercase base32, which suits alphanumeric QR codes and reading aloud.
+With --format table the parts are printed as a table of ID, hex value and
+a 2-digit CRC of the row, for paper backups; verify-table recomputes the
+CRCs to find rows that were copied wrongly.
+With --format csv or tsv only a table with the columns id, threshold and
+hex is printed, e.g. for a spreadsheet; combine reads it back with --csv.
+With --format env only assignments SHAMIR_SHARE_<ID>=<part> are printed,
+which combine reads back from the environment with --from-env.
+
+With --max-share-size N each part whose hex value would be longer than N
+digits is cut into sequenced chunks "c<seq>/<total>:ID:hex" of at most N
+digits, e.g. for QR codes. Each holder keeps all chunks of their part;
+combine reassembles them, in any order, before recovering the secret.
+
+With --qr-sequence DIR the parts are cut into chunks of 256 hex digits, or
+--max-share-size, and every chunk is written to its own file in DIR, named
+after the holder and its place in the sequence, e.g. alice-1of3.txt with
+--labels or part1-1of3.txt without. Each file holds the string to encode
+as one QR code for offline backups.
+
+An empty secret is refused unless --allow-empty is given, and a warning is
+printed for a secret of a single byte.
+
+With --commit the SHA-256 of the secret is printed to stderr. It can be
+published and checked after recovery with combine --verify-commit.
+
+With --auth a random key and an HMAC-SHA256 tag over the complete set of
+parts are printed to stderr on separate lines. Given both, combine
+--auth-key detects a part altered since the split before recovering
+anything.
+
+With --share-ids the parts get the given IDs (1-255) instead of 1..n,
+e.g. --share-ids 10,20,30.
+
+With --labels each part is annotated with the name of its holder, e.g.
+--labels alice,bob,carol prints parts such as "1#alice:a1b2". Labels are
+only kept in the text format and are ignored when combining.
+
+With --field gf16 the secret is split over GF(2^16), which allows up to
+65535 parts. Such parts must be combined with --field gf16 as well.`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if splitInteractive || splitInFile != "" || splitSecretEnv != "" {
+			return cobra.ExactArgs(2)(cmd, args)
+		}
+		return cobra.ExactArgs(3)(cmd, args)
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		out := cmd.OutOrStdout()
+		ctx, cancel := commandContext(cmd)
+		defer cancel()
+
+		if err := validateSplitFlags(); err != nil {
+			return err
+		}
+
+		var secret []byte
+		var err error
+		switch {
+		case splitInteractive:
+			secret, err = promptSecret(cmd.InOrStdin(), cmd.ErrOrStderr())
+		case splitInFile != "":
+			secret, err = readFileContext(ctx, splitInFile)
+		case splitSecretEnv != "":
+			value, ok := os.LookupEnv(splitSecretEnv)
+			if !ok {
+				return newError(codeUsage, "split.secret_env_unset", splitSecretEnv)
+			}
+			// Child processes, e.g. of a clipboard helper, do not inherit it
+			os.Unsetenv(splitSecretEnv)
+			secret = []byte(value)
+		default:
+			secret, args = []byte(args[0]), args[1:]
+		}
+		if err != nil {
+			return newError(codeIO, "split.read_failed", err)
+		}
+
+		n, err := strconv.Atoi(args[0])
+		if errors.Is(err, strconv.ErrRange) {
+			return newError(codeTooManyShares, "split.parts_out_of_range", args[0])
+		}
 		if err != nil {
-			fmt.Printf("Error: invalid number of parts '%s'\n", args[1])
-			os.Exit(1)
+			return newError(codeUsage, "split.invalid_parts", args[0])
 		}
 
-		k, err := strconv.Atoi(args[2])
+		k, err := strconv.Atoi(args[1])
+		if errors.Is(err, strconv.ErrRange) {
+			return newError(codeUsage, "split.threshold_out_of_range", args[1])
+		}
 		if err != nil {
-			fmt.Printf("Error: invalid threshold '%s'\n", args[2])
-			os.Exit(1)
+			return newError(codeUsage, "split.invalid_threshold", args[1])
+		}
+
+		if n < 1 {
+			return newError(codeUsage, "split.parts_not_positive", n)
+		}
+
+		if k < 1 {
+			return newError(codeUsage, "split.threshold_not_positive", k)
 		}
 
 		if k < 2 {
-			fmt.Println("Error: minimum number of parts for recovery must be at least 2")
-			os.Exit(1)
+			return newError(codeThresholdTooSmall, "split.threshold_too_small")
 		}
 
 		if n < k {
-			fmt.Println("Error: total number of parts cannot be less than threshold")
-			os.Exit(1)
+			return newError(codeThresholdTooLarge, "split.parts_below_threshold")
+		}
+
+		if maxShares := maxSharesForField(splitField); n > maxShares {
+			return newError(codeTooManyShares, "split.too_many_parts", maxShares)
+		}
+
+		if len(splitLabels) > 0 {
+			if len(splitLabels) != n {
+				return newError(codeUsage, "split.labels_count", len(splitLabels), n)
+			}
+			for _, label := range splitLabels {
+				if err := shamir.ValidateLabel(label); err != nil {
+					return newError(codeUsage, "split.invalid_label", err)
+				}
+			}
+		}
+
+		seedKDF = kdfParams{}
+		if splitKDF != kdfNone {
+			if seedKDF, err = newKDFParams(splitKDF, splitKDFMem, splitKDFTime); err != nil {
+				return newError(codeUsage, "split.invalid_kdf", err)
+			}
+		}
+
+		// An empty secret is almost certainly a mistake, e.g. an empty
+		// file or variable; its parts would only carry the checksum
+		switch len(secret) {
+		case 0:
+			if !splitAllowEmpty {
+				return newError(codeInvalidSecretLength, "split.empty_secret")
+			}
+			fmt.Fprintf(cmd.ErrOrStderr(), "%s\n\n", colorize(cmd.ErrOrStderr(), colorRed, tr("split.empty_warning")))
+		case 1:
+			fmt.Fprintf(cmd.ErrOrStderr(), "%s\n\n", colorize(cmd.ErrOrStderr(), colorRed, tr("split.short_warning")))
+		}
+
+		if splitField == fieldGF16 {
+			if err := splitGF16(out, secret, n, k); err != nil {
+				return err
+			}
+			if splitCommit {
+				printCommitment(cmd.ErrOrStderr(), secret)
+			}
+			return nil
+		}
+
+		var ids []byte
+		if len(splitShareIDs) > 0 {
+			if len(splitShareIDs) != n {
+				return newError(codeUsage, "split.ids_count", len(splitShareIDs), n)
+			}
+			for _, id := range splitShareIDs {
+				if id < 1 || id > shamir.MaxShares {
+					return newError(codeUsage, "split.invalid_id", id, shamir.MaxShares)
+				}
+				ids = append(ids, byte(id))
+			}
+		}
+
+		data := secret
+		if splitPad != 0 {
+			if data, err = shamir.Pad(secret, splitPad); err != nil {
+				return newError(codeUsage, "split.invalid_pad", splitPad, shamir.MaxPadBlock)
+			}
 		}
 
-		if n > 255 {
-			fmt.Println("Error: total number of parts cannot be greater than 255")
-			os.Exit(1)
+		fileMode := splitInFile != "" || splitOutDir != ""
+
+		if splitProgress || (fileMode && stdoutIsTerminal()) {
+			ctx = shamir.WithProgress(ctx, newProgressBar(cmd.ErrOrStderr(), tr("progress.split")))
+		}
+		if splitSeed != "" {
+			fmt.Fprintf(cmd.ErrOrStderr(), "%s\n\n", colorize(cmd.ErrOrStderr(), colorRed, tr("seed.warning")))
+		}
+		if seedKDF.name != "" {
+			fmt.Fprintf(cmd.ErrOrStderr(), "%s\n\n", tr("seed.kdf", seedKDF))
+		}
+		if splitVerbose {
+			fmt.Fprintf(cmd.ErrOrStderr(), "%s\n\n", colorize(cmd.ErrOrStderr(), colorRed, tr("verbose.warning")))
+			ctx = shamir.WithTrace(ctx, newTracePrinter(cmd.ErrOrStderr()))
 		}
 
-		shares, err := shamir.Split([]byte(secret), n, k)
+		logger.Debug("splitting secret", "secret_len", len(secret), "padded_len", len(data), "parts", n, "threshold", k, "custom_ids", ids != nil)
+		start := time.Now()
+
+		var shares []shamir.Share
+		switch {
+		case ids != nil:
+			shares, err = shamir.SplitWithIDsContext(ctx, data, ids, k)
+		case splitSeed != "":
+			rng := newSeededReader(splitSeed)
+			if seedKDF.name != "" {
+				if rng, err = seedKDF.reader(splitSeed); err != nil {
+					return newError(codeSplit, "split.failed", err)
+				}
+			}
+			shares, err = shamir.SplitWithReaderContext(ctx, data, n, k, rng)
+		case splitParallel && !splitVerbose:
+			shares, err = shamir.SplitParallel(ctx, data, n, k, 0)
+		default:
+			shares, err = shamir.SplitContext(ctx, data, n, k)
+		}
 		if err != nil {
-			fmt.Printf("Error during splitting: %v\n", err)
-			os.Exit(1)
+			return newError(errorCode(err, codeSplit), "split.failed", err)
+		}
+		if splitPad != 0 {
+			shamir.MarkPadded(shares)
+		}
+		for i, label := range splitLabels {
+			shares[i].Label = label
 		}
+		logger.Info("secret split", "parts", len(shares), "threshold", k, "share_len", len(shares[0].Value), "duration", time.Since(start))
+		logger.Debug("share IDs", "ids", shareIDs(shares))
 
-		fmt.Printf("Secret split into %d parts, %d parts required for recovery:\n\n", n, k)
-		for i, share := range shares {
-			fmt.Printf("Part %d: %s\n", i+1, shamir.ShareToString(share))
+		if splitVerify {
+			if err := verifyShares(secret, shares, k, splitFormat); err != nil {
+				return newError(codeVerify, "split.verify_failed", err)
+			}
+		}
+
+		if splitCommit {
+			printCommitment(cmd.ErrOrStderr(), secret)
+		}
+		if splitAuth {
+			if err := printAuthTag(cmd.ErrOrStderr(), shares); err != nil {
+				return newError(codeSplit, "split.failed", err)
+			}
 		}
 
-		fmt.Printf("\nTo recover the secret use the command:\n")
-		fmt.Printf("shamir-cli combine \"[parts_separated_by_commas]\"\n")
-		fmt.Printf("Example: shamir-cli combine \"%s,%s\"\n",
-			shamir.ShareToString(shares[0]), shamir.ShareToString(shares[1]))
+		if splitQRDir != "" {
+			digits := splitMaxSize
+			if digits == 0 {
+				digits = qrSequenceDigits
+			}
+			chunked, err := chunkShares(shares, digits)
+			if err != nil {
+				return newError(codeSplit, "split.failed", err)
+			}
+			if splitVerify {
+				if err := verifyChunks(secret, chunked, k); err != nil {
+					return newError(codeVerify, "split.verify_failed", err)
+				}
+			}
+			paths, err := writeQRSequence(splitQRDir, shares, chunked)
+			if err != nil {
+				return newError(codeIO, "split.write_failed", err)
+			}
+			fmt.Fprintf(out, "%s\n\n", colorize(out, colorBold, tr("split.header", n, k)))
+			for i, sharePaths := range paths {
+				for j, path := range sharePaths {
+					fmt.Fprintln(out, tr("split.qr_written", i+1, j+1, len(sharePaths), path))
+				}
+			}
+			printSplitSummary(out, n, k)
+			fmt.Fprintf(out, "\n%s\n", tr("split.qr_hint", k))
+			return nil
+		}
+
+		if splitMaxSize > 0 && 2*len(shares[0].Value) > splitMaxSize {
+			chunked, err := chunkShares(shares, splitMaxSize)
+			if err != nil {
+				return newError(codeSplit, "split.failed", err)
+			}
+			if splitVerify {
+				if err := verifyChunks(secret, chunked, k); err != nil {
+					return newError(codeVerify, "split.verify_failed", err)
+				}
+			}
+			logger.Info("parts chunked", "chunks", len(chunked[0]), "max_size", splitMaxSize)
+			printChunkedShares(out, chunked, n, k)
+			return nil
+		}
+
+		if splitClipboard {
+			if err := copySharesToClipboard(shares, k, splitFormat); err != nil {
+				return newError(codeIO, "clipboard.write_failed", err)
+			}
+			fmt.Fprintf(cmd.ErrOrStderr(), "%s\n", colorize(cmd.ErrOrStderr(), colorRed, tr("clipboard.warning")))
+			fmt.Fprintf(out, "%s\n\n", colorize(out, colorBold, tr("split.header", n, k)))
+			fmt.Fprintln(out, tr("split.clipboard_copied", len(shares)))
+			return nil
+		}
+
+		// Tables are printed without any other text so they can be
+		// redirected to a file and opened in a spreadsheet
+		if isCSVFormat(splitFormat) {
+			if err := writeSharesCSV(out, shares, k, tableDelimiter(splitFormat)); err != nil {
+				return newError(codeIO, "split.write_failed", err)
+			}
+			return nil
+		}
+
+		// Likewise assignments, so they can be sourced by a shell or
+		// passed to docker run --env-file
+		if splitFormat == formatEnv {
+			if err := writeSharesEnv(out, shares); err != nil {
+				return newError(codeIO, "split.write_failed", err)
+			}
+			return nil
+		}
+
+		fmt.Fprintf(out, "%s\n\n", colorize(out, colorBold, tr("split.header", n, k)))
+		if splitFormat == formatTable {
+			if err := writeSharesGrid(out, shares); err != nil {
+				return newError(codeIO, "split.write_failed", err)
+			}
+			printSplitSummary(out, n, k)
+			fmt.Fprintf(out, "\n%s\n", tr("split.table_hint"))
+			return nil
+		}
+		if splitOutDir != "" {
+			paths, err := writeShareFiles(splitOutDir, "share", shares, k, splitFormat)
+			if err != nil {
+				return newError(codeIO, "split.write_failed", err)
+			}
+			for i, path := range paths {
+				fmt.Fprintln(out, tr("split.part_written", i+1, path))
+			}
+
+			manifestPath, err := writeManifest(splitOutDir, shares, paths, k, len(data), time.Now())
+			if err != nil {
+				return newError(codeIO, "split.write_failed", err)
+			}
+			fmt.Fprintln(out, tr("split.manifest_written", manifestPath))
+			printSplitSummary(out, n, k)
+			return nil
+		}
+
+		if splitFormat == formatArmor {
+			for _, share := range shares {
+				fmt.Fprintln(out, encodeShare(share, k, formatArmor))
+			}
+			printSplitSummary(out, n, k)
+			fmt.Fprintf(out, "\n%s\n", tr("split.armor_hint"))
+			return nil
+		}
+
+		// Fingerprints are for a holder reading the parts off the screen;
+		// redirected output keeps the plain format scripts parse
+		if splitRich && stdoutIsTerminal() {
+			printRichShares(out, shares, k, splitFormat)
+		} else {
+			for i, share := range shares {
+				fmt.Fprintln(out, tr("split.part", i+1, encodeShare(share, k, splitFormat)))
+			}
+		}
+		printSplitSummary(out, n, k)
+
+		fmt.Fprintf(out, "\n%s\n", tr("split.recover_hint"))
+		fmt.Fprintf(out, "shamir-cli combine \"[parts_separated_by_commas]\"\n")
+		fmt.Fprintln(out, tr("split.example", encodeShare(shares[0], k, splitFormat), encodeShare(shares[1], k, splitFormat)))
+		return nil
 	},
 }
 
+// validateSplitFlags checks the split flags before the secret is read,
+// refusing combinations that cannot be used together. Each rule reports
+// its message when invalid is true; rules are checked in order.
+func validateSplitFlags() error {
+	switch splitFormat {
+	case formatText, formatArmor, formatCompact, formatBase32, formatTable, formatCSV, formatTSV, formatEnv:
+	default:
+		return newError(codeUsage, "split.invalid_format", splitFormat)
+	}
+	if err := validateField(splitField); err != nil {
+		return err
+	}
+
+	gf16 := splitField != fieldGF8
+	rules := []struct {
+		invalid bool
+		msgID   string
+		args    []any
+	}{
+		{len(splitShareIDs) > 0 && gf16, "split.ids_gf8_only", nil},
+		{len(splitLabels) > 0 && gf16, "split.labels_gf8_only", nil},
+		{len(splitLabels) > 0 && (splitFormat != formatText || (splitMaxSize != 0 && splitQRDir == "")), "split.labels_text", nil},
+		{splitVerbose && gf16, "split.verbose_gf8_only", nil},
+		{splitPad != 0 && gf16, "split.pad_gf8_only", nil},
+		{splitPad != 0 && (splitFormat == formatCompact || splitFormat == formatTable || isCSVFormat(splitFormat)), "split.pad_format", []any{splitFormat}},
+		{splitOutDir != "" && (isCSVFormat(splitFormat) || splitFormat == formatEnv || splitFormat == formatTable), "split.single_output", []any{splitFormat}},
+		{splitSeed != "" && gf16, "split.seed_gf8_only", nil},
+		{splitSeed != "" && len(splitShareIDs) > 0, "split.seed_ids", nil},
+		{splitKDF != kdfNone && splitSeed == "", "split.kdf_seed", nil},
+		{splitMaxSize != 0 && splitMaxSize < 2, "split.invalid_max_size", []any{splitMaxSize}},
+		{splitMaxSize != 0 && gf16, "split.max_size_gf8_only", nil},
+		{splitMaxSize != 0 && (splitFormat != formatText || splitOutDir != "" || splitClipboard), "split.max_size_text", nil},
+		{splitQRDir != "" && (gf16 || splitFormat != formatText), "split.qr_sequence_text", nil},
+		{splitClipboard && gf16, "split.clipboard_gf8_only", nil},
+		{splitAuth && gf16, "split.auth_gf8_only", nil},
+		{splitRich && (gf16 || (splitFormat != formatText && splitFormat != formatCompact && splitFormat != formatBase32) ||
+			splitOutDir != "" || splitClipboard || splitQRDir != "" || splitMaxSize != 0), "split.rich_format", nil},
+	}
+	for _, rule := range rules {
+		if rule.invalid {
+			return newError(codeUsage, rule.msgID, rule.args...)
+		}
+	}
+	return nil
+}
+
 var combineCmd = &cobra.Command{
 	Use:   "combine [parts_separated_by_commas]",
 	Short: "Recover a string from parts",
 	Long: `Recovers the original string from parts separated by commas.
-Each part must be in the format "ID:hex_value".`,
-	Args: cobra.ExactArgs(1),
-	Run: func(cmd *cobra.Command, args []string) {
-		shareStrings := strings.Split(args[0], ",")
-		if len(shareStrings) < 2 {
-			fmt.Println("Error: minimum 2 parts required for recovery")
-			os.Exit(1)
+Each part may be given as "ID:hex_value", in the compact form produced by
+"split --format compact", in the base32 form "ID-VALUE" produced by
+"split --format base32" (in any case), as base64 of ID||value or as an armored block
+produced by "split --format armor"; the formats can be mixed. When no argument is given the
+parts are read from standard input.
+
+Parts created with --field gf16 must be combined with --field gf16.
+
+With --out-file the recovered bytes are written unchanged to a file readable
+only by the owner, and the secret is not printed.
+
+Secrets that are not printable text are shown base64-encoded. With --binary
+only the base64 encoding is printed, without any other text.
+With --format raw-bytes exactly the bytes of the secret are written to
+standard output, without a prefix, a newline or any encoding, e.g. to pipe
+a binary key into another tool.
+
+With --info the secret is recovered and its checksum verified, but only
+"recovery OK" and its length are printed, never the secret itself. This
+allows validating backups in front of others.
+
+With --clipboard the parts are read from the system clipboard instead of
+an argument or standard input.
+
+With --csv the parts are read from a CSV or TSV file written by
+"split --format csv" or "split --format tsv". Rows may be deleted or
+reordered, but the header row must be kept.
+
+With --from-env the parts are read from the environment variables
+SHAMIR_SHARE_1, SHAMIR_SHARE_2 and so on, in the order of their numbers,
+as printed by split --format env.
+
+With --exec the recovered secret is written to the standard input of the
+given command instead of being printed, e.g. --exec "gpg --import". The
+command is split at spaces and run without a shell; its output is passed
+through and its exit status becomes that of combine.
+
+With --verify-commit the recovered secret is checked against the SHA-256
+printed by split --commit, independently of the checksum in the parts. A
+mismatch is reported as such and the secret is not output.
+
+With --offset and --length only a byte range of the recovered secret is
+output, e.g. the header of a large file; without --length the rest from
+--offset on. The whole secret is still recovered and verified first.
+
+With --qr-dir the parts are read from the files of a directory holding the
+strings scanned from the codes written by split --qr-sequence. The chunks
+are reassembled by the sequence and part ID they carry, whatever the files
+are named.
+
+With --auth-key and --auth-tag the parts are checked against the tag
+printed by split --auth before the secret is recovered. The tag covers
+the set as a unit, so all parts created by split must be given.
+
+With --lenient parts of different lengths, e.g. one truncated while being
+copied, are trimmed to the shortest one and the recoverable prefix of the
+secret is printed. Such a partial secret cannot be verified by the checksum,
+so the command still reports how many bytes were recovered and fails.
+
+With --share-url, given once per part, each part is fetched from an https
+URL whose response body is the part in the form ID:hex. Server
+certificates are verified and each request times out after 10 seconds.
+
+With --positional each line (or comma-separated entry) holds only the hex
+value of a part, as in exports that dropped the IDs; the part ID is the
+1-based line number. An empty line skips an ID.
+
+Hex values are written in lowercase, the canonical form, but are read in
+either case. With --strict-hex a part with uppercase hex digits is
+rejected, which catches accidental edits of stored parts.
+
+With --require-holders N combine fails unless the parts carry at least N
+distinct labels, even when there are enough parts, so that a holder of
+several parts of split-weighted cannot recover the secret alone. Unlabeled
+parts are not attributed to any holder.
+
+With --strict combine fails if more parts than the threshold are given,
+before recovering anything, so that no more part material than necessary
+is gathered in one place. The threshold is taken from armored parts or a
+table, or given with --threshold.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		out := cmd.OutOrStdout()
+
+		ctx, cancel := commandContext(cmd)
+		defer cancel()
+
+		if err := validateField(combineField); err != nil {
+			return err
+		}
+		if combineCommit != "" {
+			if _, err := parseCommitment(combineCommit); err != nil {
+				return newError(codeUsage, "combine.invalid_commit", combineCommit, err)
+			}
+		}
+		if combineAuthKey != "" {
+			if combineField != fieldGF8 {
+				return newError(codeUsage, "combine.auth_gf8_only")
+			}
+			if _, err := parseAuthHex(combineAuthKey, shamir.AuthKeySize); err != nil {
+				return newError(codeUsage, "combine.invalid_auth_key", err)
+			}
+			if _, err := parseAuthHex(combineAuthTag, shamir.AuthKeySize); err != nil {
+				return newError(codeUsage, "combine.invalid_auth_tag", err)
+			}
+		}
+		if combineFormat != formatText && combineFormat != formatRawBytes {
+			return newError(codeUsage, "combine.invalid_format", combineFormat)
+		}
+		if combineOffset < 0 || combineLength < 0 {
+			return newError(codeUsage, "combine.invalid_range")
+		}
+		if combineThreshold != 0 && combineThreshold < 2 {
+			return newError(codeThresholdTooSmall, "combine.invalid_threshold", combineThreshold)
+		}
+		if (combineStrict || combineThreshold != 0) && combineField != fieldGF8 {
+			return newError(codeUsage, "combine.strict_gf8_only")
+		}
+		if combineHolders < 0 {
+			return newError(codeUsage, "combine.invalid_require_holders", combineHolders)
+		}
+		if combineHolders > 0 && combineField != fieldGF8 {
+			return newError(codeUsage, "combine.require_holders_gf8_only")
+		}
+		if combinePositional && combineField != fieldGF8 {
+			return newError(codeUsage, "combine.positional_gf8_only")
 		}
 
-		shares := make([]shamir.Share, 0, len(shareStrings))
-		for i, shareStr := range shareStrings {
-			shareStr = strings.TrimSpace(shareStr)
-			if shareStr == "" {
-				continue
+		if combineCSV != "" {
+			return combineCSVFile(ctx, cmd, combineCSV, args)
+		}
+		if combineFromEnv {
+			return combineEnv(ctx, cmd, args)
+		}
+		if len(combineShareURLs) > 0 {
+			return combineURLs(ctx, cmd, combineShareURLs, args)
+		}
+
+		var input string
+		var err error
+		if combineQRDir != "" {
+			if len(args) > 0 {
+				return newError(codeUsage, "combine.qr_dir_args")
+			}
+			if input, err = readQRDir(ctx, combineQRDir); err != nil {
+				return newError(codeIO, "parse.read_failed", err)
 			}
+		} else if combineClipboard {
+			if len(args) > 0 {
+				return newError(codeUsage, "combine.clipboard_args")
+			}
+			if input, err = clipboardRead(); err != nil {
+				return newError(codeIO, "clipboard.read_failed", err)
+			}
+			fmt.Fprintf(cmd.ErrOrStderr(), "%s\n", colorize(cmd.ErrOrStderr(), colorRed, tr("clipboard.warning")))
+		} else if input, err = readShareInput(ctx, cmd.InOrStdin(), args); err != nil {
+			return newError(codeIO, "parse.read_failed", err)
+		}
+
+		if combineField == fieldGF16 {
+			return combineGF16(ctx, out, input)
+		}
 
-			share, err := shamir.StringToShare(shareStr)
+		if combinePositional {
+			shares, err := parsePositionalShares(input)
 			if err != nil {
-				fmt.Printf("Error parsing part %d ('%s'): %v\n", i+1, shareStr, err)
-				os.Exit(1)
+				return err
 			}
-			shares = append(shares, share)
+			return combineShares(ctx, cmd, shares, 0)
 		}
 
-		if len(shares) < 2 {
-			fmt.Println("Error: minimum 2 valid parts required for recovery")
-			os.Exit(1)
+		if !shamir.IsArmored(input) && len(splitShareList(input)) < 2 {
+			return newError(codeInsufficientShares, "combine.min_parts")
 		}
 
-		secret, err := shamir.Combine(shares)
+		shares, threshold, err := parseShareInput(input)
 		if err != nil {
-			fmt.Printf("Error during recovery: %v\n", err)
-			os.Exit(1)
+			return err
 		}
-
-		fmt.Printf("Recovered secret: %s\n", string(secret))
+		return combineShares(ctx, cmd, shares, threshold)
 	},
 }
 
+// combineCSVFile recovers the secret from the parts in a table written by
+// split --format csv or tsv
+func combineCSVFile(ctx context.Context, cmd *cobra.Command, path string, args []string) error {
+	if len(args) > 0 {
+		return newError(codeUsage, "combine.csv_args")
+	}
+	if combineField != fieldGF8 {
+		return newError(codeUsage, "combine.csv_gf8_only")
+	}
+
+	data, err := readFileContext(ctx, path)
+	if err != nil {
+		return newError(codeIO, "parse.read_failed", err)
+	}
+	shares, threshold, err := readSharesCSV(data)
+	if err != nil {
+		return newError(codeParse, "parse.csv", path, err)
+	}
+	return combineShares(ctx, cmd, shares, threshold)
+}
+
+// combineShares recovers the secret from parsed parts and outputs it.
+// threshold is the number of parts required, or 0 when unknown.
+func combineShares(ctx context.Context, cmd *cobra.Command, shares []shamir.Share, threshold int) error {
+	out := cmd.OutOrStdout()
+	if combineThreshold > 0 {
+		threshold = combineThreshold
+	}
+
+	if len(shares) < 2 {
+		return newError(codeInsufficientShares, "combine.min_valid_parts")
+	}
+	logCombineInput(shares, threshold)
+
+	// Parts may be given in any order, with gaps in their IDs or repeated
+	shares, err := shamir.DedupShares(shares)
+	if err != nil {
+		return newError(codeParse, "combine.conflicting_parts", err)
+	}
+	printPartsPresent(cmd.ErrOrStderr(), shares, threshold)
+	if combineAuthKey != "" {
+		if err := verifyAuthTag(shares, combineAuthKey, combineAuthTag); err != nil {
+			return err
+		}
+	}
+	if len(shares) < 2 {
+		return newError(codeInsufficientShares, "combine.min_valid_parts")
+	}
+	if combineStrict {
+		if threshold == 0 {
+			return newError(codeUsage, "combine.strict_threshold")
+		}
+		if len(shares) > threshold {
+			return newError(codeExcessShares, "combine.strict_excess", len(shares), threshold)
+		}
+	}
+	if threshold > 0 && len(shares) < threshold {
+		return newError(codeInsufficientShares, "combine.below_threshold", threshold, len(shares))
+	}
+	if combineHolders > 0 {
+		if holders := shareHolders(shares); len(holders) < combineHolders {
+			given := tr("combine.no_holders")
+			if len(holders) > 0 {
+				given = strings.Join(holders, ", ")
+			}
+			return newError(codeInsufficientHolders, "combine.insufficient_holders", combineHolders, len(holders), given)
+		}
+	}
+
+	if combineProgress || (combineOutFile != "" && stdoutIsTerminal()) {
+		ctx = shamir.WithProgress(ctx, newProgressBar(cmd.ErrOrStderr(), tr("progress.combine")))
+	}
+
+	if combineLenient {
+		return combineLenientShares(ctx, out, shares)
+	}
+
+	start := time.Now()
+	secret, err := shamir.CombineContext(ctx, shares)
+	if err != nil {
+		return newError(errorCode(err, codeCombine), "combine.failed", err)
+	}
+	logger.Info("secret recovered", "parts", len(shares), "secret_len", len(secret), "duration", time.Since(start))
+
+	return outputSecret(ctx, out, secret)
+}
+
+// printPartsPresent reports the IDs and labels of the parts given to combine
+// and, when the threshold is known, whether there are enough of them
+func printPartsPresent(w io.Writer, shares []shamir.Share, threshold int) {
+	ids := make([]string, len(shares))
+	for i, share := range shares {
+		ids[i] = strconv.Itoa(int(share.ID))
+		if share.Label != "" {
+			ids[i] += " (" + share.Label + ")"
+		}
+	}
+	line := tr("combine.parts_present", strings.Join(ids, ", "))
+	switch {
+	case threshold == 0:
+	case len(shares) >= threshold:
+		line += " " + tr("combine.parts_enough", threshold)
+	default:
+		line += " " + tr("combine.parts_missing", threshold, threshold-len(shares))
+	}
+	fmt.Fprintln(w, line)
+}
+
+// shareHolders returns the distinct labels of the shares in the order they
+// first appear. Unlabeled shares cannot be attributed to a holder and are
+// not counted.
+func shareHolders(shares []shamir.Share) []string {
+	var holders []string
+	seen := make(map[string]bool)
+	for _, share := range shares {
+		if share.Label != "" && !seen[share.Label] {
+			seen[share.Label] = true
+			holders = append(holders, share.Label)
+		}
+	}
+	return holders
+}
+
+// combineLenientShares recovers what it can from parts of different lengths
+// or with a failing checksum, prints it and reports how much was recovered
+func combineLenientShares(ctx context.Context, out io.Writer, shares []shamir.Share) error {
+	secret, verified, err := shamir.CombineLenient(shares)
+	if err != nil {
+		return newError(errorCode(err, codeCombine), "combine.failed", err)
+	}
+	if verified {
+		return outputSecret(ctx, out, secret)
+	}
+
+	logger.Info("secret recovered partially", "parts", len(shares), "recovered_len", len(secret))
+	if err := outputSecret(ctx, out, secret); err != nil {
+		return err
+	}
+	return newError(codePartialRecovery, "combine.partial", len(secret))
+}
+
+// outputSecret prints the recovered secret, writes it to --out-file, feeds
+// it to the --exec command or, with --info, only reports its length. With
+// --verify-commit the secret is only output if it matches the commitment,
+// and with --offset and --length only that range of it is output.
+func outputSecret(ctx context.Context, out io.Writer, secret []byte) error {
+	if combineCommit != "" {
+		if err := verifyCommitment(secret, combineCommit); err != nil {
+			return err
+		}
+		if !combineBinary && combineFormat != formatRawBytes {
+			fmt.Fprintln(out, tr("combine.commit_ok"))
+		}
+	}
+
+	if combineOffset != 0 || combineLength != 0 {
+		var err error
+		if secret, err = secretRange(secret, combineOffset, combineLength); err != nil {
+			return err
+		}
+	}
+
+	if combineInfo {
+		fmt.Fprintln(out, tr("combine.info_ok", len(secret)))
+		return nil
+	}
+
+	if combineExec != "" {
+		return execWithSecret(ctx, out, combineExec, secret)
+	}
+
+	if combineOutFile != "" {
+		if err := writeSecretFile(combineOutFile, secret); err != nil {
+			return newError(codeIO, "combine.write_failed", err)
+		}
+		fmt.Fprintln(out, tr("combine.written", len(secret), combineOutFile))
+		return nil
+	}
+
+	// Raw bytes are written without any decoration, so the output can be
+	// piped into tools expecting exactly the secret
+	if combineFormat == formatRawBytes {
+		if _, err := out.Write(secret); err != nil {
+			return newError(codeIO, "combine.write_failed", err)
+		}
+		return nil
+	}
+
+	fmt.Fprintln(out, formatSecret(secret, combineBinary))
+	return nil
+}
+
+// secretRange returns length bytes of the recovered secret starting at
+// offset, or the rest of it when length is 0
+func secretRange(secret []byte, offset, length int) ([]byte, error) {
+	if offset > len(secret) {
+		return nil, newError(codeUsage, "combine.range_out_of_bounds", offset, offset+length, len(secret))
+	}
+	if length == 0 {
+		return secret[offset:], nil
+	}
+	if length > len(secret)-offset {
+		return nil, newError(codeUsage, "combine.range_out_of_bounds", offset, offset+length, len(secret))
+	}
+	return secret[offset : offset+length], nil
+}
+
+// formatSecret returns the line printed for a recovered secret. Binary
+// secrets are base64-encoded so they are not mangled by the terminal.
+func formatSecret(secret []byte, binary bool) string {
+	encoded := base64.StdEncoding.EncodeToString(secret)
+	switch {
+	case binary:
+		return encoded
+	case !isPrintable(secret):
+		return tr("combine.result_base64", encoded)
+	default:
+		return tr("combine.result", string(secret))
+	}
+}
+
+// isPrintable reports whether data is valid UTF-8 text without control
+// characters other than tabs and newlines
+func isPrintable(data []byte) bool {
+	if !utf8.Valid(data) {
+		return false
+	}
+	for _, r := range string(data) {
+		if !unicode.IsPrint(r) && r != '\t' && r != '\n' && r != '\r' {
+			return false
+		}
+	}
+	return true
+}
+
+// writeSecretFile writes the secret to path with permissions 0600
+func writeSecretFile(path string, secret []byte) error {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+
+	// Tighten permissions of a file that already existed
+	if err := f.Chmod(0600); err != nil {
+		f.Close()
+		return err
+	}
+
+	if _, err := f.Write(secret); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// encodeShare returns a share in the given output format. Armored shares
+// record the threshold k and the parameters of a --kdf.
+func encodeShare(share shamir.Share, k int, format string) string {
+	switch format {
+	case formatArmor:
+		return shamir.ArmorShareKDF(share, k, seedKDF.String())
+	case formatCompact:
+		return shamir.ShareToCompact(share)
+	case formatBase32:
+		return shamir.ShareToBase32(share)
+	case formatEnv:
+		return envShareLine(share)
+	default:
+		return shamir.ShareToString(share)
+	}
+}
+
+// verifyShares encodes the first k shares in the given output format, parses
+// them back and checks that they recover the secret
+func verifyShares(secret []byte, shares []shamir.Share, k int, format string) error {
+	var parsed []shamir.Share
+	var err error
+	if isCSVFormat(format) {
+		var buf bytes.Buffer
+		if err := writeSharesCSV(&buf, shares[:k], k, tableDelimiter(format)); err != nil {
+			return err
+		}
+		parsed, _, err = readSharesCSV(buf.Bytes())
+	} else if format == formatTable {
+		var buf bytes.Buffer
+		if err := writeSharesGrid(&buf, shares[:k]); err != nil {
+			return err
+		}
+		var rows []gridRow
+		rows, err = readSharesGrid(buf.String())
+		for _, row := range rows {
+			if row.crc != gridCRC(row.share.ID, row.share.Value) {
+				return fmt.Errorf("CRC mismatch in the row of part %d", row.share.ID)
+			}
+			parsed = append(parsed, row.share)
+		}
+	} else {
+		encoded := make([]string, k)
+		for i, share := range shares[:k] {
+			encoded[i] = encodeShare(share, k, format)
+		}
+
+		if format == formatEnv {
+			parsed, err = readSharesEnv(encoded)
+		} else {
+			parsed, _, err = parseShareInput(strings.Join(encoded, "\n"))
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	recovered, err := shamir.Combine(parsed)
+	if err != nil {
+		return err
+	}
+	if !shamir.SecretsEqual(recovered, secret) {
+		return errors.New("recovered secret does not match the original")
+	}
+	return nil
+}
+
+// writeShareFiles writes each share to its own file in dir, named
+// <prefix>-<ID> with an extension matching the output format, and returns
+// the paths
+func writeShareFiles(dir, prefix string, shares []shamir.Share, k int, format string) ([]string, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, err
+	}
+
+	paths := make([]string, 0, len(shares))
+	for _, share := range shares {
+		name, data := fmt.Sprintf("%s-%d.txt", prefix, share.ID), encodeShare(share, k, format)+"\n"
+		if format == formatArmor {
+			name, data = fmt.Sprintf("%s-%d.asc", prefix, share.ID), encodeShare(share, k, format)
+		}
+
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(data), 0600); err != nil {
+			return nil, err
+		}
+		paths = append(paths, path)
+	}
+	return paths, nil
+}
+
+// readShareInput returns the parts passed as an argument, or read from
+// stdin when there is none
+func readShareInput(ctx context.Context, stdin io.Reader, args []string) (string, error) {
+	if len(args) > 0 {
+		return args[0], nil
+	}
+	data, err := readAllContext(ctx, stdin)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// splitShareList splits a list of parts separated by commas or newlines
+func splitShareList(input string) []string {
+	return strings.FieldsFunc(input, func(r rune) bool {
+		return r == ',' || r == '\n' || r == '\r'
+	})
+}
+
+// parsePositionalShares parses parts given as hex values only, one per line
+// or comma-separated entry, numbering them by their 1-based position. Empty
+// entries keep their position, so a missing part leaves a gap in the IDs.
+// All values must have the same length.
+func parsePositionalShares(input string) ([]shamir.Share, error) {
+	// Only trailing blanks are dropped: leading empty lines stand for
+	// missing parts and keep the following ones in place
+	input = strings.NewReplacer("\r\n", "\n", ",", "\n").Replace(strings.TrimRightFunc(input, unicode.IsSpace))
+	lines := strings.Split(input, "\n")
+	if len(lines) > shamir.MaxShares {
+		return nil, newError(codeParse, "parse.positional_too_many", len(lines), shamir.MaxShares)
+	}
+
+	var shares []shamir.Share
+	for i, line := range lines {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		if combineStrictHex {
+			if err := shamir.CheckCanonicalHex(line); err != nil {
+				return nil, newError(codeParse, "parse.part", i+1, line, err)
+			}
+		}
+		share, err := shamir.StringToShare(fmt.Sprintf("%d:%s", i+1, line))
+		if err != nil {
+			return nil, newError(codeParse, "parse.part", i+1, line, err)
+		}
+		if len(shares) > 0 && len(share.Value) != len(shares[0].Value) {
+			return nil, newError(codeParse, "parse.positional_length", i+1, 2*len(share.Value), shares[0].ID, 2*len(shares[0].Value))
+		}
+		shares = append(shares, share)
+	}
+	return shares, nil
+}
+
+// parseShareInput parses armored blocks and lists of parts in any encoding
+// supported by shamir.ParseShare. It also returns the threshold recorded in
+// armored blocks, or 0 when unknown.
+func parseShareInput(input string) ([]shamir.Share, int, error) {
+	if !shamir.IsArmored(input) {
+		shares, err := parseShares(splitShareList(input))
+		return shares, 0, err
+	}
+
+	shares, threshold, err := shamir.ParseArmor(input)
+	if err != nil {
+		return nil, 0, newError(codeParse, "parse.armor", err)
+	}
+
+	// Parts may be pasted along with armored blocks. Text around the blocks
+	// that contains spaces, such as the hints printed by split, is skipped.
+	var others []string
+	for _, s := range splitShareList(shamir.CutArmor(input)) {
+		if s = strings.TrimSpace(s); s != "" && !strings.ContainsAny(s, " \t") {
+			others = append(others, s)
+		}
+	}
+	more, err := parseShares(others)
+	if err != nil {
+		return nil, 0, err
+	}
+	return append(shares, more...), threshold, nil
+}
+
+// parseShares converts share strings in any supported encoding into shares,
+// skipping empty entries. Chunks of shares cut by --max-share-size are
+// joined into whole shares. With --strict-hex, hex values must be in the
+// canonical lowercase.
+func parseShares(shareStrings []string) ([]shamir.Share, error) {
+	if combineStrictHex {
+		for i, s := range shareStrings {
+			if err := shamir.CheckCanonicalHex(s); err != nil {
+				return nil, newError(codeParse, "parse.part", i+1, strings.TrimSpace(s), err)
+			}
+		}
+	}
+	shares, err := shamir.ParseShares(shareStrings)
+	var perr *shamir.ParseError
+	if errors.As(err, &perr) {
+		return nil, newError(codeParse, "parse.part", perr.Index, perr.Input, perr.Err)
+	}
+	if err != nil {
+		return nil, newError(codeParse, "parse.chunks", err)
+	}
+	return shares, nil
+}
+
 func init() {
+	rootCmd.PersistentFlags().StringVar(&lang, "lang", lang, "language of messages (en, ru); defaults from $LANG")
+	rootCmd.PersistentFlags().BoolVar(&jsonErrors, "json-errors", false, "report failures as a JSON object on stderr")
+	rootCmd.PersistentFlags().DurationVar(&timeout, "timeout", 0, "abort the command after this long, e.g. 30s (default no limit)")
+	rootCmd.PersistentFlags().StringVar(&logLevel, "log-level", logLevel, "level of diagnostics written to stderr: debug, info, warn or error")
+
+	splitCmd.Flags().BoolVarP(&splitInteractive, "interactive", "i", false, "read the string from the terminal without echo instead of an argument")
+	splitCmd.Flags().StringVar(&splitInFile, "in-file", "", "read the secret from a file instead of an argument")
+	splitCmd.Flags().StringVar(&splitSecretEnv, "secret-env", "", "read the secret from this environment variable instead of an argument")
+	splitCmd.Flags().StringVar(&splitOutDir, "out-dir", "", "write each part to a separate file in this directory")
+	splitCmd.Flags().BoolVar(&splitProgress, "progress", false, "show a progress bar on stderr (default when using files on a terminal)")
+	splitCmd.Flags().BoolVar(&splitParallel, "parallel", false, "split the secret on all CPUs (default for secrets of 64KB and more)")
+	splitCmd.Flags().BoolVar(&splitVerify, "verify", false, "check that the parts recover the secret before printing them")
+	splitCmd.Flags().BoolVar(&splitVerbose, "verbose", false, "print the polynomial of the first byte to stderr (reveals the secret)")
+	splitCmd.Flags().IntVar(&splitPad, "pad", 0, "pad the secret to a multiple of this many bytes (1-255) to hide its length")
+	splitCmd.Flags().StringVar(&splitQRDir, "qr-sequence", "", "write the parts cut into numbered chunks, one file per QR code, e.g. alice-1of3.txt, to this directory")
+	splitCmd.Flags().StringVar(&splitFormat, "format", formatText, "output format of the parts: text, armor, compact, base32, table, csv, tsv or env")
+	splitCmd.Flags().StringVar(&splitField, "field", fieldGF8, "finite field: gf8 (up to 255 parts) or gf16 (up to 65535 parts)")
+	splitCmd.Flags().UintSliceVar(&splitShareIDs, "share-ids", nil, "comma-separated IDs to assign to the parts instead of 1..n")
+	splitCmd.Flags().StringSliceVar(&splitLabels, "labels", nil, "comma-separated labels naming the holder of each part, e.g. alice,bob,carol")
+	splitCmd.Flags().StringVar(&splitSeed, "seed", "", "INSECURE: derive the random coefficients from this string for reproducible parts (tests and demos only)")
+	splitCmd.Flags().StringVar(&splitKDF, "kdf", kdfNone, "derive the coefficients from --seed with a memory-hard KDF: none, argon2id or scrypt")
+	splitCmd.Flags().StringVar(&splitKDFMem, "kdf-mem", "64MB", "memory used by --kdf, e.g. 64MB")
+	splitCmd.Flags().IntVar(&splitKDFTime, "kdf-time", 3, "number of passes of --kdf argon2id")
+	splitCmd.Flags().IntVar(&splitMaxSize, "max-share-size", 0, "cut parts whose hex value is longer than this many digits into sequenced chunks")
+	splitCmd.Flags().BoolVar(&splitCommit, "commit", false, "print the SHA-256 of the secret to stderr, to publish and check after recovery")
+	splitCmd.Flags().BoolVar(&splitAuth, "auth", false, "print a random key and an HMAC tag over all parts to stderr, to detect tampering on combine")
+	splitCmd.Flags().BoolVar(&splitRich, "rich", false, "on a terminal, print each part with a short fingerprint to confirm it later with inspect")
+	splitCmd.Flags().BoolVar(&splitAllowEmpty, "allow-empty", false, "split an empty secret instead of refusing it")
+	splitCmd.Flags().BoolVar(&splitClipboard, "clipboard", false, "copy the parts to the system clipboard instead of printing them")
+	splitCmd.MarkFlagsMutuallyExclusive("interactive", "in-file", "secret-env")
+	splitCmd.MarkFlagsMutuallyExclusive("clipboard", "out-dir", "qr-sequence")
+
+	combineCmd.Flags().StringVar(&combineField, "field", fieldGF8, "finite field the parts were created with: gf8 or gf16")
+	combineCmd.Flags().StringVar(&combineOutFile, "out-file", "", "write the recovered secret to a file (mode 0600) instead of printing it")
+	combineCmd.Flags().BoolVar(&combineBinary, "binary", false, "print only the base64 encoding of the recovered secret")
+	combineCmd.Flags().StringVar(&combineFormat, "format", formatText, "output of the recovered secret: text, or raw-bytes for exactly its bytes with nothing added")
+	combineCmd.Flags().BoolVar(&combineInfo, "info", false, "only report whether the parts recover a secret, without revealing it")
+	combineCmd.Flags().BoolVar(&combineClipboard, "clipboard", false, "read the parts from the system clipboard")
+	combineCmd.Flags().BoolVar(&combineFromEnv, "from-env", false, "read the parts from SHAMIR_SHARE_<n> environment variables")
+	combineCmd.Flags().StringVar(&combineCSV, "csv", "", "read the parts from a CSV or TSV file written by split --format csv or tsv")
+	combineCmd.Flags().StringArrayVar(&combineShareURLs, "share-url", nil, "fetch a part in the form ID:hex from this https URL (repeatable)")
+	combineCmd.Flags().StringVar(&combineQRDir, "qr-dir", "", "read the parts from a directory of files holding the chunks scanned from QR codes")
+	combineCmd.Flags().StringVar(&combineExec, "exec", "", "run this command with the recovered secret on its stdin instead of printing it")
+	combineCmd.Flags().IntVar(&combineOffset, "offset", 0, "output the recovered secret from this byte offset on")
+	combineCmd.Flags().IntVar(&combineLength, "length", 0, "output only this many bytes of the recovered secret (0 for the rest)")
+	combineCmd.Flags().StringVar(&combineCommit, "verify-commit", "", "check the recovered secret against the SHA-256 printed by split --commit")
+	combineCmd.Flags().StringVar(&combineAuthKey, "auth-key", "", "check the complete set of parts against the tag printed by split --auth, using this key")
+	combineCmd.Flags().StringVar(&combineAuthTag, "auth-tag", "", "the tag printed by split --auth, checked with --auth-key")
+	combineCmd.Flags().BoolVar(&combineLenient, "lenient", false, "recover the common prefix of parts with different lengths (unverified)")
+	combineCmd.Flags().BoolVar(&combinePositional, "positional", false, "read parts as hex values only, numbered by their line")
+	combineCmd.Flags().IntVar(&combineHolders, "require-holders", 0, "fail unless the parts carry at least this many distinct holder labels")
+	combineCmd.Flags().BoolVar(&combineStrictHex, "strict-hex", false, "reject parts whose hex value is not in the canonical lowercase, to catch accidental edits")
+	combineCmd.Flags().BoolVar(&combineStrict, "strict", false, "fail if more parts than the threshold are given")
+	combineCmd.Flags().IntVarP(&combineThreshold, "threshold", "k", 0, "number of parts required for recovery, for parts that do not record it")
+	combineCmd.Flags().BoolVar(&combineProgress, "progress", false, "show a progress bar on stderr (default when writing a file on a terminal)")
+	combineCmd.MarkFlagsMutuallyExclusive("info", "out-file")
+	combineCmd.MarkFlagsMutuallyExclusive("info", "binary")
+	combineCmd.MarkFlagsMutuallyExclusive("info", "lenient")
+	combineCmd.MarkFlagsMutuallyExclusive("info", "offset")
+	combineCmd.MarkFlagsMutuallyExclusive("info", "length")
+	combineCmd.MarkFlagsMutuallyExclusive("csv", "clipboard", "from-env", "qr-dir")
+	combineCmd.MarkFlagsMutuallyExclusive("positional", "csv", "from-env", "qr-dir")
+	combineCmd.MarkFlagsMutuallyExclusive("verify-commit", "lenient")
+	combineCmd.MarkFlagsRequiredTogether("auth-key", "auth-tag")
+	combineCmd.MarkFlagsMutuallyExclusive("auth-key", "strict")
+	combineCmd.MarkFlagsMutuallyExclusive("exec", "out-file", "info", "binary", "lenient")
+	combineCmd.MarkFlagsMutuallyExclusive("format", "binary", "info", "out-file", "exec")
+
 	rootCmd.AddCommand(splitCmd)
 	rootCmd.AddCommand(combineCmd)
+	rootCmd.AddCommand(splitKeyCmd)
+	rootCmd.AddCommand(splitWeightedCmd)
+	rootCmd.AddCommand(inspectCmd)
+	rootCmd.AddCommand(fingerprintCmd)
+	rootCmd.AddCommand(checkCmd)
+	rootCmd.AddCommand(verifyTableCmd)
+	rootCmd.AddCommand(doctorCmd)
+	rootCmd.AddCommand(migrateCmd)
+	rootCmd.AddCommand(selfTestCmd)
+	rootCmd.AddCommand(versionCmd)
+	rootCmd.AddCommand(completionCmd)
+	rootCmd.AddCommand(manCmd)
 }
 
 func main() {
-	if err := rootCmd.Execute(); err != nil {
-		fmt.Println(err)
-		os.Exit(1)
+	// Errors are reported by fail so that --json-errors applies to usage
+	// errors as well
+	rootCmd.SilenceErrors = true
+	rootCmd.SilenceUsage = true
+
+	if cmd, err := rootCmd.ExecuteC(); err != nil {
+		var e *cliError
+		if !errors.As(err, &e) {
+			// Errors from cobra itself are about the command line
+			if !jsonErrors {
+				cmd.PrintErrln(cmd.ErrPrefix(), err.Error())
+				cmd.PrintErrln(cmd.UsageString())
+			}
+			err = newUsageError(err)
+		}
+		fail(timeoutError(err))
 	}
 }
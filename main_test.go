@@ -0,0 +1,993 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"shamir-cli/shamir"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+func TestFormatSecret(t *testing.T) {
+	lang = "en"
+
+	tests := []struct {
+		name   string
+		secret []byte
+		binary bool
+		want   string
+	}{
+		{"Text", []byte("Hello, World!"), false, "Recovered secret: Hello, World!"},
+		{"Multiline text", []byte("a\nb"), false, "Recovered secret: a\nb"},
+		{"Binary auto-detected", []byte{0x00, 0xFF, 'a'}, false, "Recovered secret (binary, base64): AP9h"},
+		{"Binary flag", []byte{0x00, 0xFF, 'a'}, true, "AP9h"},
+		{"Binary flag on text", []byte("hi"), true, "aGk="},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := formatSecret(tt.secret, tt.binary)
+			if got != tt.want {
+				t.Errorf("formatSecret() = %q, want %q", got, tt.want)
+			}
+
+			if tt.binary {
+				decoded, err := base64.StdEncoding.DecodeString(got)
+				if err != nil || !bytes.Equal(decoded, tt.secret) {
+					t.Errorf("Decoded output = %x, want %x", decoded, tt.secret)
+				}
+			}
+		})
+	}
+}
+
+func TestWriteSecretFileBinary(t *testing.T) {
+	secret := []byte{0x00, 0xFF, 0x0A, 0x80, 'o', 'k', 0x00}
+
+	shares, err := shamir.Split(secret, 5, 3)
+	if err != nil {
+		t.Fatalf("Split failed: %v", err)
+	}
+
+	input := strings.Join([]string{
+		shamir.ShareToString(shares[0]),
+		shamir.ShareToString(shares[2]),
+		shamir.ShareToString(shares[4]),
+	}, ",")
+
+	parsed, _, err := parseShareInput(input)
+	if err != nil {
+		t.Fatalf("parseShareInput failed: %v", err)
+	}
+
+	recovered, err := shamir.Combine(parsed)
+	if err != nil {
+		t.Fatalf("Combine failed: %v", err)
+	}
+
+	path := filepath.Join(t.TempDir(), "secret.bin")
+	if err := os.WriteFile(path, []byte("previous, longer content"), 0644); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+
+	if err := writeSecretFile(path, recovered); err != nil {
+		t.Fatalf("writeSecretFile failed: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile failed: %v", err)
+	}
+	if !bytes.Equal(data, secret) {
+		t.Errorf("File content = %x, want %x", data, secret)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("Stat failed: %v", err)
+	}
+	if perm := info.Mode().Perm(); runtime.GOOS != "windows" && perm != 0600 {
+		t.Errorf("File permissions = %o, want 600", perm)
+	}
+}
+
+func TestVerifyShares(t *testing.T) {
+	secret := []byte("verify me")
+
+	shares, err := shamir.Split(secret, 5, 3)
+	if err != nil {
+		t.Fatalf("Split failed: %v", err)
+	}
+
+	for _, format := range []string{formatText, formatArmor, formatCompact} {
+		if err := verifyShares(secret, shares, 3, format); err != nil {
+			t.Errorf("verifyShares(%s) failed: %v", format, err)
+		}
+	}
+
+	// Corrupted parts must not pass verification
+	broken := make([]shamir.Share, len(shares))
+	copy(broken, shares)
+	broken[1] = shamir.Share{ID: shares[1].ID, Value: bytes.Repeat([]byte{0xAA}, len(shares[1].Value))}
+	if err := verifyShares(secret, broken, 3, formatText); err == nil {
+		t.Error("Expected verification of corrupted parts to fail")
+	}
+}
+
+func TestFormatPolynomial(t *testing.T) {
+	tests := []struct {
+		coeffs []byte
+		want   string
+	}{
+		{[]byte{0x68, 0x1f}, "0x68 + 0x1f*x"},
+		{[]byte{0x00, 0xff, 0x0a}, "0x00 + 0xff*x + 0x0a*x^2"},
+	}
+
+	for _, tt := range tests {
+		if got := formatPolynomial(tt.coeffs); got != tt.want {
+			t.Errorf("formatPolynomial(%x) = %q, want %q", tt.coeffs, got, tt.want)
+		}
+	}
+}
+
+func TestSetupLogger(t *testing.T) {
+	defer func() { logger = newLogger(os.Stderr, slog.LevelWarn) }()
+
+	var buf bytes.Buffer
+	if err := setupLogger(&buf, "info"); err != nil {
+		t.Fatalf("setupLogger failed: %v", err)
+	}
+
+	shares := []shamir.Share{
+		{ID: 1, Value: []byte{0xde, 0xad}},
+		{ID: 1, Value: []byte{0xbe, 0xef}},
+	}
+	logCombineInput(shares, 0)
+
+	out := buf.String()
+	if !strings.Contains(out, "duplicate part ID") || !strings.Contains(out, "count=2") {
+		t.Errorf("Expected warning and info records, got:\n%s", out)
+	}
+	if strings.Contains(out, "part IDs") {
+		t.Errorf("Debug records must not be written at info level, got:\n%s", out)
+	}
+	if strings.Contains(out, "dead") || strings.Contains(out, "beef") {
+		t.Errorf("Share values must never be logged, got:\n%s", out)
+	}
+
+	if err := setupLogger(&buf, "verbose"); err == nil {
+		t.Error("Expected error for an unknown level")
+	}
+}
+
+// executeCommand runs rootCmd with args and returns what it wrote to
+// stdout. Flags are reset to their defaults first.
+func executeCommand(t *testing.T, stdin string, args ...string) (string, error) {
+	t.Helper()
+	return executeCommandReader(t, strings.NewReader(stdin), args...)
+}
+
+// executeCommandReader is like executeCommand but reads stdin from r
+func executeCommandReader(t *testing.T, stdin io.Reader, args ...string) (string, error) {
+	t.Helper()
+	lang = "en"
+
+	var reset func(cmd *cobra.Command)
+	reset = func(cmd *cobra.Command) {
+		cmd.Flags().VisitAll(func(f *pflag.Flag) {
+			if v, ok := f.Value.(pflag.SliceValue); ok {
+				v.Replace(nil)
+			} else {
+				f.Value.Set(f.DefValue)
+			}
+			f.Changed = false
+		})
+		for _, sub := range cmd.Commands() {
+			reset(sub)
+		}
+	}
+	reset(rootCmd)
+	rootCmd.PersistentFlags().Set("lang", "en")
+
+	// As in main, errors are reported by the caller without usage text
+	rootCmd.SilenceErrors = true
+	rootCmd.SilenceUsage = true
+
+	var stdout, stderr bytes.Buffer
+	rootCmd.SetOut(&stdout)
+	rootCmd.SetErr(&stderr)
+	rootCmd.SetIn(stdin)
+	rootCmd.SetArgs(args)
+	defer func() {
+		rootCmd.SetOut(nil)
+		rootCmd.SetErr(nil)
+		rootCmd.SetIn(nil)
+		rootCmd.SetArgs(nil)
+	}()
+
+	err := rootCmd.Execute()
+	return stdout.String(), err
+}
+
+func TestSplitCombineCommands(t *testing.T) {
+	out, err := executeCommand(t, "", "split", "round trip", "4", "3")
+	if err != nil {
+		t.Fatalf("split failed: %v", err)
+	}
+
+	var parts []string
+	for _, line := range strings.Split(out, "\n") {
+		if strings.HasPrefix(line, "Part ") {
+			parts = append(parts, strings.Fields(line)[2])
+		}
+	}
+	if len(parts) != 4 {
+		t.Fatalf("Expected 4 parts in output, got %d:\n%s", len(parts), out)
+	}
+
+	out, err = executeCommand(t, "", "combine", strings.Join(parts[1:], ","))
+	if err != nil {
+		t.Fatalf("combine failed: %v", err)
+	}
+	if out != "Recovered secret: round trip\n" {
+		t.Errorf("combine output = %q", out)
+	}
+
+	// Parts are read from stdin when no argument is given
+	out, err = executeCommand(t, strings.Join(parts[:3], "\n"), "combine", "--info")
+	if err != nil {
+		t.Fatalf("combine --info failed: %v", err)
+	}
+	if out != "Recovery OK (10 bytes)\n" {
+		t.Errorf("combine --info output = %q", out)
+	}
+}
+
+func TestCombineShuffledParts(t *testing.T) {
+	shares, err := shamir.Split([]byte("out of order"), 6, 3)
+	if err != nil {
+		t.Fatalf("Split failed: %v", err)
+	}
+	// Out of order, with gaps and a repeated part
+	var parts []string
+	for _, i := range []int{5, 1, 5, 3} {
+		parts = append(parts, shamir.ShareToString(shares[i]))
+	}
+
+	out, err := executeCommand(t, "", "combine", strings.Join(parts, ","))
+	if err != nil {
+		t.Fatalf("combine failed: %v", err)
+	}
+	if out != "Recovered secret: out of order\n" {
+		t.Errorf("combine output = %q", out)
+	}
+
+	conflicting := parts[0] + "," + parts[1] + "," + shamir.ShareToString(shamir.Share{ID: shares[1].ID, Value: shares[3].Value})
+	_, err = executeCommand(t, "", "combine", conflicting)
+	var e *cliError
+	if !errors.As(err, &e) || e.Code != codeParse {
+		t.Errorf("combine with conflicting parts returned %v, want error code %q", err, codeParse)
+	}
+}
+
+func TestCombineStrict(t *testing.T) {
+	shares, err := shamir.Split([]byte("strict"), 4, 2)
+	if err != nil {
+		t.Fatalf("Split failed: %v", err)
+	}
+	var parts []string
+	for _, share := range shares {
+		parts = append(parts, shamir.ShareToString(share))
+	}
+
+	out, err := executeCommand(t, "", "combine", "--strict", "--threshold", "2", parts[0]+","+parts[3])
+	if err != nil {
+		t.Fatalf("combine --strict failed: %v", err)
+	}
+	if out != "Recovered secret: strict\n" {
+		t.Errorf("combine output = %q", out)
+	}
+
+	tests := []struct {
+		name string
+		args []string
+		code string
+	}{
+		{"k+1 parts", []string{"combine", "--strict", "-k", "2", strings.Join(parts[:3], ",")}, codeExcessShares},
+		{"k+1 armored parts", []string{"combine", "--strict", "--", shamir.ArmorShare(shares[0], 2) + shamir.ArmorShare(shares[1], 2) + shamir.ArmorShare(shares[2], 2)}, codeExcessShares},
+		{"unknown threshold", []string{"combine", "--strict", strings.Join(parts[:2], ",")}, codeUsage},
+		{"invalid threshold", []string{"combine", "--threshold", "1", strings.Join(parts[:2], ",")}, codeThresholdTooSmall},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, err := executeCommand(t, "", tt.args...)
+			var e *cliError
+			if !errors.As(err, &e) || e.Code != tt.code {
+				t.Errorf("%v returned %v, want error code %q", tt.args, err, tt.code)
+			}
+			if strings.Contains(out, "strict") {
+				t.Errorf("secret printed despite the error:\n%s", out)
+			}
+		})
+	}
+}
+
+func TestCombinePositional(t *testing.T) {
+	shares, err := shamir.Split([]byte("legacy export"), 4, 3)
+	if err != nil {
+		t.Fatalf("Split failed: %v", err)
+	}
+	lines := make([]string, len(shares))
+	for i, share := range shares {
+		lines[i] = fmt.Sprintf("%x", share.Value)
+	}
+
+	// Part 2 is missing; its empty line keeps parts 3 and 4 in place
+	input := lines[0] + "\n\n" + lines[2] + "\r\n" + lines[3] + "\n"
+	out, err := executeCommand(t, input, "combine", "--positional")
+	if err != nil {
+		t.Fatalf("combine --positional failed: %v", err)
+	}
+	if out != "Recovered secret: legacy export\n" {
+		t.Errorf("combine output = %q", out)
+	}
+
+	// Part 1 is missing; the leading empty line keeps the others in place
+	out, err = executeCommand(t, "\n"+lines[1]+"\n"+lines[2]+"\n"+lines[3]+"\n", "combine", "--positional")
+	if err != nil {
+		t.Fatalf("combine --positional without part 1 failed: %v", err)
+	}
+	if out != "Recovered secret: legacy export\n" {
+		t.Errorf("combine output without part 1 = %q", out)
+	}
+
+	// Out of place, the values are interpolated at the wrong IDs. The
+	// checksum usually catches that, but the result is never the secret
+	out, err = executeCommand(t, lines[2]+","+lines[3]+","+lines[0], "combine", "--positional")
+	if err == nil && strings.Contains(out, "legacy export") {
+		t.Errorf("combine of misplaced values recovered the secret:\n%s", out)
+	}
+
+	var e *cliError
+
+	_, err = executeCommand(t, lines[0]+"\n"+lines[1]+"00\n"+lines[2], "combine", "--positional")
+	if !errors.As(err, &e) || e.Code != codeParse {
+		t.Errorf("combine of values of different lengths returned %v, want error code %q", err, codeParse)
+	}
+}
+
+func TestPrintPartsPresent(t *testing.T) {
+	lang = "en"
+	shares := []shamir.Share{{ID: 2}, {ID: 5, Label: "bob"}, {ID: 9}}
+	tests := []struct {
+		threshold int
+		want      string
+	}{
+		{0, "Parts present: 2, 5 (bob), 9\n"},
+		{3, "Parts present: 2, 5 (bob), 9 (3 required, enough to recover)\n"},
+		{5, "Parts present: 2, 5 (bob), 9 (5 required, 2 more needed)\n"},
+	}
+
+	for _, tt := range tests {
+		var buf bytes.Buffer
+		printPartsPresent(&buf, shares, tt.threshold)
+		if buf.String() != tt.want {
+			t.Errorf("printPartsPresent(threshold %d) = %q, want %q", tt.threshold, buf.String(), tt.want)
+		}
+	}
+}
+
+func TestSplitInteractive(t *testing.T) {
+	// Without a terminal the secret is read from stdin as is
+	out, err := executeCommand(t, "piped secret\n", "split", "--interactive", "3", "2")
+	if err != nil {
+		t.Fatalf("split --interactive failed: %v", err)
+	}
+	var parts []string
+	for _, line := range strings.Split(out, "\n") {
+		if strings.HasPrefix(line, "Part ") {
+			parts = append(parts, strings.Fields(line)[2])
+		}
+	}
+	if len(parts) != 3 {
+		t.Fatalf("Expected 3 parts in output, got %d:\n%s", len(parts), out)
+	}
+	out, err = executeCommand(t, "", "combine", parts[0]+","+parts[2])
+	if err != nil || out != "Recovered secret: piped secret\n" {
+		t.Errorf("combine of interactive parts = %q, %v", out, err)
+	}
+}
+
+func TestSplitInteractiveMismatch(t *testing.T) {
+	// Simulate a terminal reading one line per prompt
+	saved := passwordReader
+	passwordReader = func(in io.Reader) func() ([]byte, error) {
+		r := bufio.NewReader(in)
+		return func() ([]byte, error) {
+			line, err := r.ReadString('\n')
+			return []byte(strings.TrimSuffix(line, "\n")), err
+		}
+	}
+	t.Cleanup(func() { passwordReader = saved })
+
+	out, err := executeCommand(t, "same\nsame\n", "split", "--interactive", "3", "2")
+	if err != nil || strings.Count(out, "Part ") != 3 {
+		t.Errorf("split --interactive with a confirmed secret = %v:\n%s", err, out)
+	}
+
+	out, err = executeCommand(t, "first\nsecond\n", "split", "--interactive", "3", "2")
+	if err == nil || !strings.Contains(err.Error(), "secrets do not match") {
+		t.Errorf("split --interactive with a mismatch returned %v, want a mismatch error", err)
+	}
+	if out != "" {
+		t.Errorf("split --interactive with a mismatch printed:\n%s", out)
+	}
+}
+
+func TestSplitCompactCommand(t *testing.T) {
+	out, err := executeCommand(t, "", "split", "--format", "compact", "grid", "3", "2")
+	if err != nil {
+		t.Fatalf("split --format compact failed: %v", err)
+	}
+
+	var parts []string
+	for _, line := range strings.Split(out, "\n") {
+		if strings.HasPrefix(line, "Part ") {
+			parts = append(parts, strings.Fields(line)[2])
+		}
+	}
+	if len(parts) != 3 {
+		t.Fatalf("Expected 3 parts in output, got %d:\n%s", len(parts), out)
+	}
+	for _, part := range parts {
+		// ID, 4 bytes of secret and a checksum byte
+		if strings.Contains(part, ":") || len(part) != 2*6 {
+			t.Errorf("Unexpected compact part %q", part)
+		}
+	}
+
+	out, err = executeCommand(t, "", "combine", parts[2]+","+parts[0])
+	if err != nil {
+		t.Fatalf("combine failed: %v", err)
+	}
+	if out != "Recovered secret: grid\n" {
+		t.Errorf("combine output = %q", out)
+	}
+}
+
+func TestSplitBase32Command(t *testing.T) {
+	out, err := executeCommand(t, "", "split", "--format", "base32", "--pad", "8", "--verify", "spoken", "3", "2")
+	if err != nil {
+		t.Fatalf("split --format base32 failed: %v", err)
+	}
+
+	var parts []string
+	for _, line := range strings.Split(out, "\n") {
+		if strings.HasPrefix(line, "Part ") {
+			parts = append(parts, strings.Fields(line)[2])
+		}
+	}
+	if len(parts) != 3 {
+		t.Fatalf("Expected 3 parts in output, got %d:\n%s", len(parts), out)
+	}
+	for _, part := range parts {
+		if !strings.HasPrefix(part, "V2-") || strings.ToUpper(part) != part {
+			t.Errorf("Unexpected base32 part %q", part)
+		}
+	}
+
+	// Parts may be typed back in lowercase
+	out, err = executeCommand(t, "", "combine", strings.ToLower(parts[1])+","+parts[2])
+	if err != nil {
+		t.Fatalf("combine failed: %v", err)
+	}
+	if out != "Recovered secret: spoken\n" {
+		t.Errorf("combine output = %q", out)
+	}
+}
+
+func TestSplitLabels(t *testing.T) {
+	out, err := executeCommand(t, "", "split", "--labels", "alice,bob,carol", "--verify", "named", "3", "2")
+	if err != nil {
+		t.Fatalf("split --labels failed: %v", err)
+	}
+
+	var parts []string
+	for _, line := range strings.Split(out, "\n") {
+		if strings.HasPrefix(line, "Part ") {
+			parts = append(parts, strings.Fields(line)[2])
+		}
+	}
+	if len(parts) != 3 || !strings.HasPrefix(parts[0], "1#alice:") || !strings.HasPrefix(parts[2], "3#carol:") {
+		t.Fatalf("Unexpected labeled parts:\n%s", out)
+	}
+
+	// Labeled and unlabeled parts can be mixed
+	unlabeled := strings.Replace(parts[1], "#bob", "", 1)
+	out, err = executeCommand(t, "", "combine", parts[2]+","+unlabeled)
+	if err != nil {
+		t.Fatalf("combine failed: %v", err)
+	}
+	if out != "Recovered secret: named\n" {
+		t.Errorf("combine output = %q", out)
+	}
+}
+
+func TestSplitEmptySecret(t *testing.T) {
+	_, err := executeCommand(t, "", "split", "", "3", "2")
+	var e *cliError
+	if !errors.As(err, &e) || e.Code != codeInvalidSecretLength {
+		t.Errorf("split of an empty secret returned %v, want error code %q", err, codeInvalidSecretLength)
+	}
+
+	out, err := executeCommand(t, "", "split", "--allow-empty", "--verify", "", "3", "2")
+	if err != nil {
+		t.Fatalf("split --allow-empty failed: %v", err)
+	}
+	if !strings.Contains(out, "Part 3: 3:") {
+		t.Errorf("Unexpected output:\n%s", out)
+	}
+
+	// A single byte is split with a warning only
+	if _, err := executeCommand(t, "", "split", "x", "3", "2"); err != nil {
+		t.Errorf("split of a 1-byte secret failed: %v", err)
+	}
+}
+
+func TestSplitMaxShareSize(t *testing.T) {
+	// 10 bytes and a checksum byte need 22 hex digits, so a limit of 12
+	// forces two chunks per part
+	out, err := executeCommand(t, "", "split", "--max-share-size", "12", "--verify", "ten bytes!", "3", "2")
+	if err != nil {
+		t.Fatalf("split --max-share-size failed: %v", err)
+	}
+
+	chunks := make(map[string][]string)
+	for _, line := range strings.Split(out, "\n") {
+		if !strings.HasPrefix(line, "Part ") {
+			continue
+		}
+		fields := strings.Fields(line)
+		chunk := fields[len(fields)-1]
+		if _, value, _ := strings.Cut(chunk[strings.Index(chunk, ":")+1:], ":"); len(value) > 12 {
+			t.Errorf("chunk %q exceeds 12 hex digits", chunk)
+		}
+		part := strings.TrimSuffix(fields[1], ",")
+		chunks[part] = append(chunks[part], chunk)
+	}
+	if len(chunks) != 3 || len(chunks["3"]) != 2 || !strings.HasPrefix(chunks["3"][1], "c2/2:3:") {
+		t.Fatalf("Unexpected chunks %v in output:\n%s", chunks, out)
+	}
+
+	// Chunks may be passed in any order
+	input := strings.Join([]string{chunks["3"][1], chunks["1"][0], chunks["3"][0], chunks["1"][1]}, ",")
+	out, err = executeCommand(t, "", "combine", input)
+	if err != nil {
+		t.Fatalf("combine failed: %v\n%s", err, out)
+	}
+	if out != "Recovered secret: ten bytes!\n" {
+		t.Errorf("combine output = %q", out)
+	}
+
+	// A missing chunk is reported instead of combining a truncated part
+	_, err = executeCommand(t, "", "combine", strings.Join([]string{chunks["3"][1], chunks["1"][0], chunks["1"][1]}, ","))
+	var e *cliError
+	if !errors.As(err, &e) || e.Code != codeParse {
+		t.Errorf("combine with a missing chunk returned %v, want error code %q", err, codeParse)
+	}
+
+	// Parts within the limit are printed as usual
+	out, err = executeCommand(t, "", "split", "--max-share-size", "64", "ten bytes!", "3", "2")
+	if err != nil || strings.Contains(out, "chunk") {
+		t.Errorf("split below the limit returned %v:\n%s", err, out)
+	}
+}
+
+func TestCombineMixedFormats(t *testing.T) {
+	secret := []byte("mixed formats")
+	shares, err := shamir.Split(secret, 5, 4)
+	if err != nil {
+		t.Fatalf("Split failed: %v", err)
+	}
+	raw := append([]byte{shares[2].ID}, shares[2].Value...)
+
+	input := strings.Join([]string{
+		shamir.ShareToString(shares[0]),
+		shamir.ShareToCompact(shares[1]),
+		base64.StdEncoding.EncodeToString(raw),
+	}, ",")
+
+	out, err := executeCommand(t, "", "combine", input+","+shamir.ShareToString(shares[3]))
+	if err != nil {
+		t.Fatalf("combine failed: %v", err)
+	}
+	if out != "Recovered secret: mixed formats\n" {
+		t.Errorf("combine output = %q", out)
+	}
+
+	// Armored blocks with other parts and the hints printed by split
+	stdin := "Secret split into 5 parts, 4 parts required for recovery:\n" +
+		shamir.ArmorShare(shares[3], 4) + "\n" + strings.ReplaceAll(input, ",", "\n") +
+		"\nTo recover the secret pass any of the blocks to: shamir-cli combine\n"
+	out, err = executeCommand(t, stdin, "combine")
+	if err != nil {
+		t.Fatalf("combine from stdin failed: %v", err)
+	}
+	if out != "Recovered secret: mixed formats\n" {
+		t.Errorf("combine output = %q", out)
+	}
+}
+
+func TestSplitSeedReproducible(t *testing.T) {
+	first, err := executeCommand(t, "", "split", "--seed", "foo", "seeded secret", "5", "3")
+	if err != nil {
+		t.Fatalf("split --seed failed: %v", err)
+	}
+	second, err := executeCommand(t, "", "split", "--seed", "foo", "seeded secret", "5", "3")
+	if err != nil {
+		t.Fatalf("split --seed failed: %v", err)
+	}
+	if first != second {
+		t.Errorf("Splits with the same seed differ:\n%s\n%s", first, second)
+	}
+
+	other, err := executeCommand(t, "", "split", "--seed", "bar", "seeded secret", "5", "3")
+	if err != nil {
+		t.Fatalf("split --seed failed: %v", err)
+	}
+	if other == first {
+		t.Error("Splits with different seeds must differ")
+	}
+
+	var parts []string
+	for _, line := range strings.Split(first, "\n") {
+		if strings.HasPrefix(line, "Part ") {
+			parts = append(parts, strings.Fields(line)[2])
+		}
+	}
+	out, err := executeCommand(t, "", "combine", strings.Join(parts[2:], ","))
+	if err != nil {
+		t.Fatalf("combine failed: %v", err)
+	}
+	if out != "Recovered secret: seeded secret\n" {
+		t.Errorf("combine output = %q", out)
+	}
+}
+
+func TestSeededReader(t *testing.T) {
+	// Reads of any size yield the same stream
+	whole := make([]byte, 100)
+	newSeededReader("foo").Read(whole)
+
+	r := newSeededReader("foo")
+	pieces := make([]byte, 0, 100)
+	for _, size := range []int{1, 31, 33, 35} {
+		buf := make([]byte, size)
+		if n, err := r.Read(buf); n != size || err != nil {
+			t.Fatalf("Read(%d) = %d, %v", size, n, err)
+		}
+		pieces = append(pieces, buf...)
+	}
+
+	if !bytes.Equal(whole, pieces) {
+		t.Errorf("Stream depends on read sizes:\n%x\n%x", whole, pieces)
+	}
+}
+
+func TestSplitPadCommand(t *testing.T) {
+	out, err := executeCommand(t, "", "split", "--pad", "16", "round trip", "3", "2")
+	if err != nil {
+		t.Fatalf("split --pad failed: %v", err)
+	}
+
+	var parts []string
+	for _, line := range strings.Split(out, "\n") {
+		if strings.HasPrefix(line, "Part ") {
+			parts = append(parts, strings.Fields(line)[2])
+		}
+	}
+	if len(parts) != 3 {
+		t.Fatalf("Expected 3 parts in output, got %d:\n%s", len(parts), out)
+	}
+
+	// 16 bytes of padded secret and a checksum byte
+	if want := "v2:1:"; !strings.HasPrefix(parts[0], want) || len(parts[0]) != len(want)+2*17 {
+		t.Errorf("Unexpected padded part %q", parts[0])
+	}
+
+	out, err = executeCommand(t, "", "combine", strings.Join(parts[1:], ","))
+	if err != nil {
+		t.Fatalf("combine failed: %v", err)
+	}
+	if out != "Recovered secret: round trip\n" {
+		t.Errorf("combine output = %q", out)
+	}
+}
+
+func TestCombineLenientCommand(t *testing.T) {
+	shares, err := shamir.Split([]byte("hello world"), 3, 2)
+	if err != nil {
+		t.Fatalf("Split failed: %v", err)
+	}
+	short := shamir.ShareToString(shares[1])
+	short = short[:len(short)-4]
+	input := shamir.ShareToString(shares[0]) + "," + short
+
+	if _, err := executeCommand(t, "", "combine", input); err == nil {
+		t.Fatal("combine should reject parts of different lengths without --lenient")
+	}
+
+	out, err := executeCommand(t, "", "combine", "--lenient", input)
+	var e *cliError
+	if !errors.As(err, &e) || e.Code != codePartialRecovery {
+		t.Fatalf("combine --lenient returned %v, want error code %q", err, codePartialRecovery)
+	}
+	if out != "Recovered secret: hello worl\n" {
+		t.Errorf("combine --lenient output = %q", out)
+	}
+}
+
+func TestTimeoutSlowReader(t *testing.T) {
+	// Nothing is ever written to the pipe, so reading the parts hangs
+	r, w := io.Pipe()
+	t.Cleanup(func() { w.Close() })
+
+	start := time.Now()
+	_, err := executeCommandReader(t, r, "--timeout", "50ms", "combine")
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("combine returned %v, want %v", err, context.DeadlineExceeded)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("combine took %v to time out", elapsed)
+	}
+
+	var e *cliError
+	if !errors.As(timeoutError(err), &e) || e.Code != codeTimeout {
+		t.Errorf("timeoutError(%v) = %v, want error code %q", err, timeoutError(err), codeTimeout)
+	}
+}
+
+func TestClipboard(t *testing.T) {
+	var board string
+	clipboardWrite = func(text string) error { board = text; return nil }
+	clipboardRead = func() (string, error) { return board, nil }
+	t.Cleanup(func() { clipboardWrite, clipboardRead = writeClipboard, readClipboard })
+
+	out, err := executeCommand(t, "", "split", "--clipboard", "copied secret", "3", "2")
+	if err != nil {
+		t.Fatalf("split --clipboard failed: %v", err)
+	}
+	if strings.Contains(out, "Part ") || !strings.Contains(out, "3 parts copied to the clipboard") {
+		t.Errorf("Unexpected split --clipboard output:\n%s", out)
+	}
+	if lines := strings.Split(board, "\n"); len(lines) != 3 {
+		t.Fatalf("Clipboard holds %d lines, want 3:\n%s", len(lines), board)
+	}
+
+	out, err = executeCommand(t, "", "combine", "--clipboard")
+	if err != nil {
+		t.Fatalf("combine --clipboard failed: %v", err)
+	}
+	if out != "Recovered secret: copied secret\n" {
+		t.Errorf("combine --clipboard output = %q", out)
+	}
+}
+
+func TestClipboardUnavailable(t *testing.T) {
+	clipboardWrite = func(string) error { return errNoClipboard }
+	clipboardRead = func() (string, error) { return "", errNoClipboard }
+	t.Cleanup(func() { clipboardWrite, clipboardRead = writeClipboard, readClipboard })
+
+	for _, args := range [][]string{
+		{"split", "--clipboard", "secret", "3", "2"},
+		{"combine", "--clipboard"},
+	} {
+		out, err := executeCommand(t, "", args...)
+		var e *cliError
+		if !errors.As(err, &e) || e.Code != codeIO || !strings.Contains(e.Message, errNoClipboard.Error()) {
+			t.Errorf("%v returned %v, want a clear %q error", args, err, codeIO)
+		}
+		if out != "" {
+			t.Errorf("%v printed %q before failing", args, out)
+		}
+	}
+}
+
+func TestVersionCommand(t *testing.T) {
+	defer func(v, c, d string) { version, commit, buildDate = v, c, d }(version, commit, buildDate)
+	version, commit, buildDate = "v1.2.3", "abc1234", "2024-05-01T10:00:00Z"
+
+	out, err := executeCommand(t, "", "version")
+	if err != nil {
+		t.Fatalf("version failed: %v", err)
+	}
+	for _, want := range []string{"shamir-cli v1.2.3", "abc1234", "2024-05-01T10:00:00Z", "Part format: version 1 (reads versions up to 5)"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("version output lacks %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestCommandErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		code string
+	}{
+		{"threshold too small", []string{"split", "secret", "3", "1"}, codeThresholdTooSmall},
+		{"parts below threshold", []string{"split", "secret", "2", "3"}, codeThresholdTooLarge},
+		{"negative parts", []string{"split", "--", "secret", "-1", "2"}, codeUsage},
+		{"zero parts", []string{"split", "secret", "0", "2"}, codeUsage},
+		{"negative threshold", []string{"split", "--", "secret", "3", "-2"}, codeUsage},
+		{"zero threshold", []string{"split", "secret", "3", "0"}, codeUsage},
+		{"labels count", []string{"split", "--labels", "alice,bob", "secret", "3", "2"}, codeUsage},
+		{"invalid label", []string{"split", "--labels", "alice,b b,carol", "secret", "3", "2"}, codeUsage},
+		{"labels with compact", []string{"split", "--labels", "a,b,c", "--format", "compact", "secret", "3", "2"}, codeUsage},
+		{"parts out of range", []string{"split", "secret", "99999999999999999999", "2"}, codeTooManyShares},
+		{"threshold out of range", []string{"split", "secret", "3", "99999999999999999999"}, codeUsage},
+		{"too many parts", []string{"split", "secret", "256", "2"}, codeTooManyShares},
+		{"invalid pad", []string{"split", "--pad", "256", "secret", "3", "2"}, codeUsage},
+		{"seed with share IDs", []string{"split", "--seed", "foo", "--share-ids", "1,2,3", "secret", "3", "2"}, codeUsage},
+		{"pad with compact", []string{"split", "--pad", "16", "--format", "compact", "secret", "3", "2"}, codeUsage},
+		{"max share size too small", []string{"split", "--max-share-size", "1", "secret", "3", "2"}, codeUsage},
+		{"max share size with armor", []string{"split", "--max-share-size", "8", "--format", "armor", "secret", "3", "2"}, codeUsage},
+		{"pad over gf16", []string{"split", "--pad", "16", "--field", "gf16", "secret", "3", "2"}, codeUsage},
+		{"single part", []string{"combine", "1:ab"}, codeInsufficientShares},
+		{"unparsable part", []string{"combine", "1:ab,2:zz"}, codeParse},
+		{"checksum failure", []string{"combine", "1:ab,2:cd"}, codeChecksum},
+		{"unknown field", []string{"combine", "--field", "gf32", "1:ab,2:cd"}, codeUsage},
+		{"not recoverable", []string{"inspect", "-k", "3", "1:ab,2:cd"}, codeNotRecoverable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := executeCommand(t, "", tt.args...)
+			var e *cliError
+			if !errors.As(err, &e) || e.Code != tt.code {
+				t.Errorf("%v returned %v, want error code %q", tt.args, err, tt.code)
+			}
+		})
+	}
+}
+
+func TestSelfTestCommand(t *testing.T) {
+	out, err := executeCommand(t, "", "selftest")
+	if err != nil {
+		t.Fatalf("selftest failed: %v\n%s", err, out)
+	}
+	if strings.Contains(out, "FAIL") || !strings.Contains(out, "All 5 checks passed") {
+		t.Errorf("Unexpected selftest output:\n%s", out)
+	}
+}
+
+func TestCombineRange(t *testing.T) {
+	shares, err := shamir.Split([]byte("HEADER:payload of the file"), 3, 2)
+	if err != nil {
+		t.Fatalf("Split failed: %v", err)
+	}
+	parts := shamir.ShareToString(shares[0]) + "," + shamir.ShareToString(shares[2])
+
+	tests := []struct {
+		args []string
+		want string
+	}{
+		{[]string{"--length", "6"}, "Recovered secret: HEADER\n"},
+		{[]string{"--offset", "7", "--length", "7"}, "Recovered secret: payload\n"},
+		{[]string{"--offset", "23"}, "Recovered secret: ile\n"},
+		{[]string{"--offset", "26"}, "Recovered secret: \n"},
+		{[]string{"--offset", "7", "--length", "7", "--binary"}, "cGF5bG9hZA==\n"},
+	}
+	for _, tt := range tests {
+		args := append(append([]string{"combine"}, tt.args...), parts)
+		out, err := executeCommand(t, "", args...)
+		if err != nil {
+			t.Errorf("%v failed: %v", tt.args, err)
+		} else if out != tt.want {
+			t.Errorf("%v output = %q, want %q", tt.args, out, tt.want)
+		}
+	}
+
+	for _, args := range [][]string{
+		{"--offset", "27"},
+		{"--offset", "20", "--length", "7"},
+		{"--offset", "-1"},
+		{"--length", "-1"},
+	} {
+		_, err := executeCommand(t, "", append(append([]string{"combine"}, args...), parts)...)
+		var e *cliError
+		if !errors.As(err, &e) || e.Code != codeUsage {
+			t.Errorf("%v returned %v, want error code %q", args, err, codeUsage)
+		}
+	}
+}
+
+func TestCombineRawBytes(t *testing.T) {
+	// Not UTF-8, with a NUL and a trailing newline that must be kept as is
+	secret := []byte{0xff, 0x00, 'k', 'e', 'y', 0xc3, 0x28, '\r', '\n'}
+	shares, err := shamir.Split(secret, 3, 2)
+	if err != nil {
+		t.Fatalf("Split failed: %v", err)
+	}
+	parts := shamir.ShareToString(shares[1]) + "," + shamir.ShareToString(shares[2])
+
+	tests := []struct {
+		args []string
+		want []byte
+	}{
+		{nil, secret},
+		{[]string{"--verify-commit", commitment(secret)}, secret},
+		{[]string{"--offset", "2", "--length", "3"}, []byte("key")},
+	}
+	for _, tt := range tests {
+		args := append(append([]string{"combine", "--format", "raw-bytes"}, tt.args...), parts)
+		out, err := executeCommand(t, "", args...)
+		if err != nil {
+			t.Errorf("%v failed: %v", tt.args, err)
+		} else if !bytes.Equal([]byte(out), tt.want) {
+			t.Errorf("%v output = %q, want %q", tt.args, out, tt.want)
+		}
+	}
+
+	for _, args := range [][]string{
+		{"combine", "--format", "hex", parts},
+		{"combine", "--format", "raw-bytes", "--binary", parts},
+		{"combine", "--format", "raw-bytes", "--out-file", filepath.Join(t.TempDir(), "secret"), parts},
+	} {
+		if _, err := executeCommand(t, "", args...); err == nil {
+			t.Errorf("%v succeeded", args)
+		}
+	}
+}
+
+func TestCombineStrictHex(t *testing.T) {
+	// Fixed coefficients, so the values are sure to contain letters
+	shares, err := shamir.SplitWithReader([]byte("case"), 3, 2, bytes.NewReader(bytes.Repeat([]byte{0xab}, 16)))
+	if err != nil {
+		t.Fatalf("SplitWithReader failed: %v", err)
+	}
+	lower := shamir.ShareToString(shares[0]) + "," + shamir.ShareToString(shares[1])
+	upper := strings.ToUpper(shamir.ShareToString(shares[0])) + "," + shamir.ShareToString(shares[1])
+	if upper == lower {
+		t.Fatalf("Part %s has no hex letters", shamir.ShareToString(shares[0]))
+	}
+
+	// Uppercase is the same share, accepted unless --strict-hex is given
+	for _, args := range [][]string{
+		{"combine", lower},
+		{"combine", upper},
+		{"combine", "--strict-hex", lower},
+		{"combine", "--strict-hex", "--positional", fmt.Sprintf("%x\n%x", shares[0].Value, shares[1].Value)},
+	} {
+		out, err := executeCommand(t, "", args...)
+		if err != nil {
+			t.Errorf("%v failed: %v", args, err)
+		} else if out != "Recovered secret: case\n" {
+			t.Errorf("%v output = %q", args, out)
+		}
+	}
+
+	for _, args := range [][]string{
+		{"combine", "--strict-hex", upper},
+		{"combine", "--strict-hex", "--positional", fmt.Sprintf("%X\n%x", shares[0].Value, shares[1].Value)},
+	} {
+		_, err := executeCommand(t, "", args...)
+		var e *cliError
+		if !errors.As(err, &e) || e.Code != codeParse || !errors.Is(err, shamir.ErrUppercaseHex) {
+			t.Errorf("%v returned %v, want error code %q for uppercase hex", args, err, codeParse)
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+
+	"shamir-cli/shamir"
+)
+
+// manifestName is the file written next to the parts by split --out-dir
+const manifestName = "manifest.json"
+
+// manifest records the parameters of a split and where its parts were
+// written. It deliberately holds no part values, so it can be kept with
+// other records.
+type manifest struct {
+	Parts     int             `json:"n"`
+	Threshold int             `json:"k"`
+	SecretLen int             `json:"secret_len"`
+	Created   time.Time       `json:"created"`
+	Shares    []manifestShare `json:"shares"`
+}
+
+// manifestShare names the file holding one part
+type manifestShare struct {
+	ID   int    `json:"id"`
+	File string `json:"file"`
+}
+
+// writeManifest writes manifest.json to dir for shares written to paths
+// and returns its path
+func writeManifest(dir string, shares []shamir.Share, paths []string, k, secretLen int, created time.Time) (string, error) {
+	m := manifest{
+		Parts:     len(shares),
+		Threshold: k,
+		SecretLen: secretLen,
+		Created:   created.UTC(),
+		Shares:    make([]manifestShare, len(shares)),
+	}
+	for i, share := range shares {
+		m.Shares[i] = manifestShare{ID: int(share.ID), File: filepath.Base(paths[i])}
+	}
+
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return "", err
+	}
+
+	path := filepath.Join(dir, manifestName)
+	if err := os.WriteFile(path, append(data, '\n'), 0600); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+// readManifest reads a manifest written by writeManifest
+func readManifest(path string) (manifest, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return manifest{}, err
+	}
+
+	var m manifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		return manifest{}, err
+	}
+	return m, nil
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"shamir-cli/shamir"
+)
+
+func TestWriteManifest(t *testing.T) {
+	dir := t.TempDir()
+	secret := []byte("manifest secret")
+
+	shares, err := shamir.SplitWithIDs(secret, []byte{4, 8, 15}, 2)
+	if err != nil {
+		t.Fatalf("SplitWithIDs failed: %v", err)
+	}
+	paths, err := writeShareFiles(dir, "share", shares, 2, formatText)
+	if err != nil {
+		t.Fatalf("writeShareFiles failed: %v", err)
+	}
+
+	created := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	path, err := writeManifest(dir, shares, paths, 2, len(secret), created)
+	if err != nil {
+		t.Fatalf("writeManifest failed: %v", err)
+	}
+	if path != filepath.Join(dir, "manifest.json") {
+		t.Errorf("Manifest written to %s", path)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile failed: %v", err)
+	}
+
+	var m manifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		t.Fatalf("Manifest is not valid JSON: %v", err)
+	}
+	if m.Parts != 3 || m.Threshold != 2 || m.SecretLen != len(secret) || !m.Created.Equal(created) {
+		t.Errorf("Unexpected manifest parameters: %+v", m)
+	}
+
+	want := []manifestShare{{4, "share-4.txt"}, {8, "share-8.txt"}, {15, "share-15.txt"}}
+	if len(m.Shares) != len(want) {
+		t.Fatalf("Expected %d shares, got %d", len(want), len(m.Shares))
+	}
+	for i, share := range m.Shares {
+		if share != want[i] {
+			t.Errorf("Share %d = %+v, want %+v", i, share, want[i])
+		}
+	}
+
+	// No part value may end up in the manifest
+	for _, share := range shares {
+		value := strings.SplitN(shamir.ShareToString(share), ":", 2)[1]
+		if strings.Contains(string(data), value) {
+			t.Errorf("Manifest contains the value of part %d", share.ID)
+		}
+	}
+}
@@ -0,0 +1,476 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// lang is the language of user-facing messages, set by the --lang flag
+var lang = defaultLang()
+
+// messages holds the message catalog for each supported language,
+// keyed by message id
+var messages = map[string]map[string]string{
+	"en": {
+		"lang.unsupported":  "Error: unsupported language '%s' (supported: %s)",
+		"log.invalid_level": "Error: unknown log level '%s' (supported: debug, info, warn, error)",
+
+		"split.invalid_parts":          "Error: invalid number of parts '%s'",
+		"split.invalid_threshold":      "Error: invalid threshold '%s'",
+		"split.threshold_too_small":    "Error: minimum number of parts for recovery must be at least 2",
+		"split.parts_below_threshold":  "Error: total number of parts cannot be less than threshold",
+		"split.empty_secret":           "Error: the secret is empty; use --allow-empty to split it anyway",
+		"split.empty_warning":          "Warning: the secret is empty, the parts only carry its checksum",
+		"split.short_warning":          "Warning: the secret is only 1 byte long and can easily be guessed",
+		"split.too_many_parts":         "Error: total number of parts cannot be greater than %d",
+		"split.parts_out_of_range":     "Error: number of parts '%s' is out of range",
+		"split.threshold_out_of_range": "Error: threshold '%s' is out of range",
+		"split.parts_not_positive":     "Error: number of parts must be a positive integer, got %d",
+		"split.threshold_not_positive": "Error: threshold must be a positive integer, got %d",
+		"split.failed":                 "Error during splitting: %v",
+		"split.read_failed":            "Error reading secret: %v",
+		"split.secret_env_unset":       "Error: environment variable %s is not set",
+		"split.write_failed":           "Error writing parts: %v",
+		"split.verify_failed":          "Error: parts failed verification and were not printed: %v",
+		"split.part_written":           "Part %d written to %s",
+		"split.manifest_written":       "Manifest written to %s",
+		"split.invalid_format":         "Error: unknown output format '%s' (supported: text, armor, compact, base32, table, csv, tsv, env)",
+		"split.ids_count":              "Error: %d share IDs given for %d parts",
+		"split.invalid_id":             "Error: share ID %d must be between 1 and %d",
+		"split.ids_gf8_only":           "Error: custom share IDs are only supported with --field gf8",
+		"split.labels_count":           "Error: %d labels given for %d parts",
+		"split.invalid_label":          "Error: %v; labels may only contain letters, digits, '_', '-' and '.'",
+		"split.labels_gf8_only":        "Error: labels are only supported with --field gf8",
+		"split.labels_text":            "Error: --labels is only supported with --format text and without --max-share-size",
+		"split.verbose_gf8_only":       "Error: --verbose is only supported with --field gf8",
+		"split.pad_format":             "Error: --pad cannot be used with --format %s, which does not record the part version",
+		"split.single_output":          "Error: --format %s prints all parts together and cannot be used with --out-dir",
+		"split.seed_gf8_only":          "Error: --seed is only supported with --field gf8",
+		"split.seed_ids":               "Error: --seed cannot be used with --share-ids",
+		"split.kdf_seed":               "Error: --kdf derives the coefficients from --seed, which is missing",
+		"split.invalid_kdf":            "Error: invalid KDF parameters: %v",
+		"split.clipboard_gf8_only":     "Error: --clipboard is only supported with --field gf8",
+		"split.clipboard_copied":       "%d parts copied to the clipboard",
+		"split.pad_gf8_only":           "Error: --pad is only supported with --field gf8",
+		"split.invalid_pad":            "Error: invalid pad block size %d (must be between 1 and %d)",
+		"split.armor_hint":             "To recover the secret pass any of the blocks to: shamir-cli combine",
+		"split.header":                 "Secret split into %d parts, %d parts required for recovery:",
+		"split.summary":                "%d parts created, any %d of them recover the secret.",
+		"split.keep_secret":            "Keep every part secret and give each one to a different holder.",
+		"split.part":                   "Part %d: %s",
+		"split.part_rich":              "Part %d [%s]: %s",
+		"split.rich_note":              "  Note fingerprint %[2]s of part %[1]d; inspect shows it again to confirm you hold this part",
+		"split.part_chunk":             "Part %d, chunk %d/%d: %s",
+		"split.commitment":             "Commitment (SHA-256 of the secret, safe to publish): %s",
+		"split.auth_key":               "Authentication key (keep apart from the parts): %s",
+		"split.auth_tag":               "Authentication tag of the complete set of parts: %s",
+		"split.auth_gf8_only":          "Error: --auth is only supported with --field gf8",
+		"split.rich_format":            "Error: --rich is only supported with --field gf8 and --format text, compact or base32, printed to standard output without chunking",
+		"split.chunk_hint":             "Each holder keeps all chunks of their part. To recover the secret pass all chunks of at least %d parts to: shamir-cli combine",
+		"split.qr_written":             "Part %d, chunk %d of %d written to %s",
+		"split.qr_hint":                "Encode each file as one QR code, e.g. with: qrencode -r alice-1of3.txt -o alice-1of3.png. Each holder keeps all codes of their part. To recover the secret save the strings scanned from the codes of at least %d parts as files in one directory and run: shamir-cli combine --qr-dir DIR",
+		"split.qr_sequence_text":       "Error: --qr-sequence is only supported with --field gf8 and --format text",
+		"split.table_hint":             "After copying the table, check each row with: shamir-cli verify-table",
+		"split.invalid_max_size":       "Error: invalid maximum part size %d (must be at least 2 hex digits)",
+		"split.max_size_gf8_only":      "Error: --max-share-size is only supported with --field gf8",
+		"split.max_size_text":          "Error: --max-share-size is only supported when printing parts with --format text",
+		"split.recover_hint":           "To recover the secret use the command:",
+		"split.example":                "Example: shamir-cli combine \"%s,%s\"",
+
+		"splitkey.hint":                   "To recover the key combine any %d of the parts, e.g.: cat %s | shamir-cli combine --out-file %s",
+		"migrate.parts":                   "Error: give the number of new parts with --parts",
+		"migrate.invalid_format":          "Error: unknown output format '%s' (supported: text, armor, compact, base32)",
+		"migrate.threshold":               "Error: the threshold is not recorded in the parts; give it with --threshold",
+		"migrate.failed":                  "Error: migration failed: %v",
+		"migrate.hint":                    "The new parts use the CRC-32 format and cannot be combined with the old ones. Hand them out and destroy all old parts.",
+		"weighted.header":                 "Secret split for %d holders into %d parts, a weight of %d required for recovery:",
+		"weighted.holder":                 "%s (weight %d):",
+		"weighted.hint":                   "Holders whose weights add up to %d recover the secret together by passing all their parts to: shamir-cli combine",
+		"weighted.invalid_spec":           "Error: invalid holder '%s', expected holder:weight with a weight of at least 1",
+		"weighted.duplicate_holder":       "Error: holder '%s' is given more than once",
+		"weighted.too_many_parts":         "Error: the weights add up to %d parts, maximum %d",
+		"weighted.weight_below_threshold": "Error: the weights add up to %d, less than the threshold %d",
+
+		"combine.min_parts":                "Error: minimum 2 parts required for recovery",
+		"combine.min_valid_parts":          "Error: minimum 2 valid parts required for recovery",
+		"combine.below_threshold":          "Error: %d parts required for recovery, only %d provided",
+		"combine.strict_excess":            "Error: %d parts given, but --strict allows only the threshold of %d",
+		"combine.invalid_require_holders":  "Error: --require-holders must not be negative, got %d",
+		"combine.require_holders_gf8_only": "Error: --require-holders is only supported with --field gf8",
+		"combine.insufficient_holders":     "Error: parts from %d distinct holders required, only %d given (%s)",
+		"combine.no_holders":               "no labels",
+		"combine.strict_threshold":         "Error: --strict needs the threshold; these parts do not record it, so pass it with --threshold",
+		"combine.strict_gf8_only":          "Error: --strict and --threshold are only supported with --field gf8",
+		"combine.positional_gf8_only":      "Error: --positional is only supported with --field gf8",
+		"combine.invalid_threshold":        "Error: --threshold must be at least 2, got %d",
+		"combine.parts_present":            "Parts present: %s",
+		"combine.parts_enough":             "(%d required, enough to recover)",
+		"combine.parts_missing":            "(%d required, %d more needed)",
+		"combine.conflicting_parts":        "Error: parts with the same ID differ: %v",
+		"combine.failed":                   "Error during recovery: %v",
+		"combine.result":                   "Recovered secret: %s",
+		"combine.result_base64":            "Recovered secret (binary, base64): %s",
+		"combine.written":                  "Recovered secret (%d bytes) written to %s",
+		"combine.info_ok":                  "Recovery OK (%d bytes)",
+		"combine.invalid_format":           "Error: unknown output format '%s' (supported: text, raw-bytes)",
+		"combine.commit_ok":                "Commitment verified: the recovered secret matches",
+		"combine.exec_empty":               "Error: --exec requires a command",
+		"combine.exec_failed":              "Error: command '%s' failed: %v",
+		"combine.commit_mismatch":          "Error: the recovered secret does not match the commitment; the parts are consistent but belong to a different secret",
+		"combine.invalid_commit":           "Error: invalid commitment '%s': %v",
+		"combine.invalid_range":            "Error: --offset and --length cannot be negative",
+		"combine.range_out_of_bounds":      "Error: the range %d-%d is outside the recovered secret of %d bytes",
+		"combine.auth_failed":              "Error: the %d parts given do not match the authentication tag; a part was altered, or the set is incomplete (the tag covers all parts created by split)",
+		"combine.invalid_auth_key":         "Error: invalid authentication key: %v",
+		"combine.invalid_auth_tag":         "Error: invalid authentication tag: %v",
+		"combine.auth_gf8_only":            "Error: --auth-key is only supported with --field gf8",
+		"combine.write_failed":             "Error writing secret: %v",
+		"combine.clipboard_args":           "Error: parts cannot be given as an argument with --clipboard",
+		"combine.csv_args":                 "Error: parts cannot be given as an argument with --csv",
+		"combine.qr_dir_args":              "Error: parts cannot be given as an argument with --qr-dir",
+		"combine.share_url_args":           "Error: parts cannot be given as an argument with --share-url",
+		"combine.share_url_gf8_only":       "Error: --share-url is only supported with --field gf8",
+		"combine.invalid_share_url":        "Error: invalid --share-url %q: %v",
+		"combine.share_url_failed":         "Error fetching the part from %s: %v",
+		"combine.csv_gf8_only":             "Error: --csv is only supported with --field gf8",
+		"combine.env_args":                 "Error: parts cannot be given as an argument with --from-env",
+		"combine.env_gf8_only":             "Error: --from-env is only supported with --field gf8",
+		"combine.env_none":                 "Error: no %s<n> environment variables are set",
+		"combine.partial":                  "Error: only %d bytes could be recovered and they are not verified by the checksum",
+
+		"field.unsupported":    "Error: unsupported field '%s' (supported: gf8, gf16)",
+		"field.gf16_text_only": "Error: parts over gf16 can only be printed in text format",
+
+		"verbose.warning": "WARNING: --verbose prints the random coefficients of the polynomials. Anyone who sees them can recover the secret. Use it for demonstrations only!",
+		"seed.warning":    "WARNING: --seed makes the parts reproducible. Anyone who knows the seed can recover the secret from a single part. Never use it for real secrets!",
+		"seed.kdf":        "Coefficients derived from --seed with %s; keep these parameters to reproduce the parts",
+
+		"verbose.polynomial": "Polynomial of degree %d for byte 0: f(x) = %s",
+
+		"progress.split":   "Splitting",
+		"progress.combine": "Combining",
+
+		"prompt.enter":    "Enter secret: ",
+		"prompt.confirm":  "Confirm secret: ",
+		"prompt.mismatch": "secrets do not match",
+
+		"parse.part":                "Error parsing part %d ('%s'): %v",
+		"parse.positional_length":   "Error: line %d has %d hex digits, but line %d has %d; all values must have the same length",
+		"parse.positional_too_many": "Error: %d lines given, but parts are numbered up to %d",
+		"parse.table":               "Error reading the table: %v",
+		"parse.no_parts":            "Error: no parts provided",
+		"parse.armor":               "Error parsing armored parts: %v",
+		"parse.chunks":              "Error joining chunked parts: %v",
+		"parse.csv":                 "Error parsing parts from %s: %v",
+		"parse.env":                 "Error parsing parts from the environment: %v",
+		"parse.share_url":           "Error parsing the part fetched from %s: %v",
+		"parse.read_failed":         "Error reading parts: %v",
+
+		"inspect.header":             "CHECK\tRESULT",
+		"inspect.parts":              "Parts provided\t%d",
+		"inspect.ids":                "Distinct IDs\t%d (%s)",
+		"inspect.fingerprints":       "Fingerprints\t%s",
+		"inspect.duplicates":         "Duplicate parts\t%d",
+		"inspect.lengths_match":      "Lengths match\tyes (%d bytes)",
+		"inspect.lengths_mismatch":   "Lengths match\tno (%d different lengths)",
+		"inspect.threshold_met":      "Threshold\t%d (met)",
+		"inspect.threshold_missing":  "Threshold\t%d (missing %d parts)",
+		"inspect.recovery_failed":    "Reconstruction\tFAILED (%v)",
+		"inspect.recovery_ok":        "Reconstruction\tOK (%d bytes, secret masked)",
+		"inspect.recovery_skipped":   "Reconstruction\tskipped",
+		"inspect.threshold_inferred": "Threshold\t%d (inferred from %d parts)",
+		"inspect.recoverable_yes":    "Recoverable\tyes",
+		"inspect.recoverable_no":     "Recoverable\tno",
+		"inspect.not_recoverable":    "Error: the parts cannot recover a secret",
+		"fingerprint.part":           "Part %d: %s",
+		"fingerprint.part_labeled":   "Part %d (%s): %s",
+
+		"check.header":          "GROUP\tPART LENGTH\tPARTS\tIDS\tTHRESHOLD\tSTATUS",
+		"check.status_ok":       "recoverable",
+		"check.status_missing":  "parts missing: %d",
+		"check.status_unknown":  "threshold unknown",
+		"check.skipped":         "Skipped %s: %v",
+		"check.scan_failed":     "Error scanning directory: %v",
+		"check.no_parts":        "Error: no parts found in %s",
+		"check.not_recoverable": "Error: groups without enough parts to recover the secret: %d",
+
+		"selftest.pass":   "PASS  %s",
+		"selftest.fail":   "FAIL  %s: %v",
+		"selftest.passed": "All %d checks passed",
+		"selftest.failed": "Error: %d of %d checks failed",
+
+		"doctor.threshold":   "Error: the threshold is unknown; pass it with --threshold",
+		"doctor.failed":      "Error: %v",
+		"doctor.summary":     "Tried %d subsets of %d parts, %d of them recover the same secret.",
+		"doctor.none":        "Error: no subset of %d parts recovers the secret; either too many parts are corrupt or the threshold is wrong",
+		"doctor.header":      "Part\tConsistent subsets\tVerdict",
+		"doctor.row":         "%d\t%d of %d\t%s",
+		"doctor.ok":          "ok",
+		"doctor.suspect":     "likely corrupt",
+		"doctor.no_suspects": "No corrupt part found.",
+		"doctor.suspects":    "Likely corrupt parts: %s. Combine the secret without them.",
+
+		"verify_table.ok":       "Line %d (part %d): OK",
+		"verify_table.mismatch": "Line %d (part %d): CRC mismatch, written %02x but the row gives %02x",
+		"verify_table.passed":   "All %d rows verified",
+		"verify_table.failed":   "Error: %d of %d rows have a wrong CRC; compare them with the original",
+
+		"version.version": "shamir-cli %s",
+		"version.commit":  "Commit:      %s",
+		"version.date":    "Built:       %s",
+		"version.format":  "Part format: version %d (reads versions up to %d)",
+
+		"timeout.expired": "Error: the operation did not finish within %s",
+
+		"clipboard.warning":      "WARNING: other applications can read the clipboard. Clear it once you no longer need its contents.",
+		"clipboard.write_failed": "Error copying to the clipboard: %v",
+		"clipboard.read_failed":  "Error reading the clipboard: %v",
+
+		"completion.failed": "Error generating completion: %v",
+		"man.mkdir_failed":  "Error creating output directory: %v",
+		"man.failed":        "Error generating man pages: %v",
+		"man.written":       "Man pages written to %s",
+	},
+	"ru": {
+		"lang.unsupported":  "Ошибка: неподдерживаемый язык '%s' (поддерживаются: %s)",
+		"log.invalid_level": "Ошибка: неизвестный уровень журнала '%s' (поддерживаются: debug, info, warn, error)",
+
+		"split.invalid_parts":          "Ошибка: некорректное количество частей '%s'",
+		"split.invalid_threshold":      "Ошибка: некорректный порог '%s'",
+		"split.threshold_too_small":    "Ошибка: минимальное количество частей для восстановления должно быть не меньше 2",
+		"split.parts_below_threshold":  "Ошибка: общее количество частей не может быть меньше порога",
+		"split.empty_secret":           "Ошибка: секрет пуст; чтобы всё равно разделить его, укажите --allow-empty",
+		"split.empty_warning":          "Внимание: секрет пуст, части содержат только его контрольную сумму",
+		"split.short_warning":          "Внимание: длина секрета всего 1 байт, его легко угадать",
+		"split.too_many_parts":         "Ошибка: общее количество частей не может быть больше %d",
+		"split.parts_out_of_range":     "Ошибка: количество частей '%s' вне допустимого диапазона",
+		"split.threshold_out_of_range": "Ошибка: порог '%s' вне допустимого диапазона",
+		"split.parts_not_positive":     "Ошибка: количество частей должно быть положительным целым числом, получено %d",
+		"split.threshold_not_positive": "Ошибка: порог должен быть положительным целым числом, получено %d",
+		"split.failed":                 "Ошибка при разделении: %v",
+		"split.read_failed":            "Ошибка чтения секрета: %v",
+		"split.secret_env_unset":       "Ошибка: переменная окружения %s не задана",
+		"split.write_failed":           "Ошибка записи частей: %v",
+		"split.verify_failed":          "Ошибка: части не прошли проверку и не были выведены: %v",
+		"split.part_written":           "Часть %d записана в %s",
+		"split.manifest_written":       "Манифест записан в %s",
+		"split.invalid_format":         "Ошибка: неизвестный формат вывода '%s' (поддерживаются: text, armor, compact, base32, table, csv, tsv, env)",
+		"split.ids_count":              "Ошибка: указано %d ID для %d частей",
+		"split.invalid_id":             "Ошибка: ID части %d должен быть от 1 до %d",
+		"split.ids_gf8_only":           "Ошибка: собственные ID частей поддерживаются только с --field gf8",
+		"split.labels_count":           "Ошибка: указано %d меток для %d частей",
+		"split.invalid_label":          "Ошибка: %v; метки могут содержать только буквы, цифры, '_', '-' и '.'",
+		"split.labels_gf8_only":        "Ошибка: метки поддерживаются только с --field gf8",
+		"split.labels_text":            "Ошибка: --labels поддерживается только с --format text и без --max-share-size",
+		"split.verbose_gf8_only":       "Ошибка: --verbose поддерживается только с --field gf8",
+		"split.pad_format":             "Ошибка: --pad нельзя использовать с --format %s, который не сохраняет версию части",
+		"split.single_output":          "Ошибка: --format %s выводит все части вместе и не может использоваться с --out-dir",
+		"split.seed_gf8_only":          "Ошибка: --seed поддерживается только с --field gf8",
+		"split.seed_ids":               "Ошибка: --seed нельзя использовать вместе с --share-ids",
+		"split.kdf_seed":               "Ошибка: --kdf выводит коэффициенты из --seed, который не задан",
+		"split.invalid_kdf":            "Ошибка: некорректные параметры KDF: %v",
+		"split.clipboard_gf8_only":     "Ошибка: --clipboard поддерживается только с --field gf8",
+		"split.clipboard_copied":       "Частей скопировано в буфер обмена: %d",
+		"split.pad_gf8_only":           "Ошибка: --pad поддерживается только с --field gf8",
+		"split.invalid_pad":            "Ошибка: некорректный размер блока дополнения %d (должен быть от 1 до %d)",
+		"split.armor_hint":             "Для восстановления секрета передайте блоки команде: shamir-cli combine",
+		"split.header":                 "Секрет разделён на %d частей, для восстановления требуется %d:",
+		"split.summary":                "Создано частей: %d, любые %d из них восстанавливают секрет.",
+		"split.keep_secret":            "Храните каждую часть в тайне и передайте их разным владельцам.",
+		"split.part":                   "Часть %d: %s",
+		"split.part_rich":              "Часть %d [%s]: %s",
+		"split.rich_note":              "  Запишите отпечаток %[2]s части %[1]d; inspect покажет его снова, чтобы убедиться, что у вас эта часть",
+		"split.part_chunk":             "Часть %d, фрагмент %d/%d: %s",
+		"split.commitment":             "Обязательство (SHA-256 секрета, можно публиковать): %s",
+		"split.auth_key":               "Ключ аутентификации (храните отдельно от частей): %s",
+		"split.auth_tag":               "Тег аутентификации полного набора частей: %s",
+		"split.auth_gf8_only":          "Ошибка: --auth поддерживается только с --field gf8",
+		"split.rich_format":            "Ошибка: --rich поддерживается только с --field gf8 и --format text, compact или base32 при выводе в стандартный вывод без разбиения на фрагменты",
+		"split.chunk_hint":             "Каждый владелец хранит все фрагменты своей части. Для восстановления секрета передайте все фрагменты не менее %d частей команде: shamir-cli combine",
+		"split.qr_written":             "Часть %d, фрагмент %d из %d записан в %s",
+		"split.qr_hint":                "Закодируйте каждый файл одним QR-кодом, например: qrencode -r alice-1of3.txt -o alice-1of3.png. Каждый владелец хранит все коды своей части. Для восстановления секрета сохраните строки, отсканированные с кодов не менее %d частей, в файлы одного каталога и выполните: shamir-cli combine --qr-dir DIR",
+		"split.qr_sequence_text":       "Ошибка: --qr-sequence поддерживается только с --field gf8 и --format text",
+		"split.table_hint":             "Переписав таблицу, проверьте каждую строку командой: shamir-cli verify-table",
+		"split.invalid_max_size":       "Ошибка: некорректный максимальный размер части %d (должен быть не меньше 2 шестнадцатеричных цифр)",
+		"split.max_size_gf8_only":      "Ошибка: --max-share-size поддерживается только с --field gf8",
+		"split.max_size_text":          "Ошибка: --max-share-size поддерживается только при выводе частей с --format text",
+		"split.recover_hint":           "Для восстановления секрета используйте команду:",
+		"split.example":                "Пример: shamir-cli combine \"%s,%s\"",
+
+		"splitkey.hint":                   "Для восстановления ключа объедините любые %d частей, например: cat %s | shamir-cli combine --out-file %s",
+		"migrate.parts":                   "Ошибка: укажите количество новых частей с --parts",
+		"migrate.invalid_format":          "Ошибка: неизвестный формат вывода '%s' (поддерживаются: text, armor, compact, base32)",
+		"migrate.threshold":               "Ошибка: порог не записан в частях; укажите его с --threshold",
+		"migrate.failed":                  "Ошибка: миграция не удалась: %v",
+		"migrate.hint":                    "Новые части используют формат CRC-32 и не объединяются со старыми. Раздайте их и уничтожьте все старые части.",
+		"weighted.header":                 "Секрет разделён между владельцами (%d) на %d частей, для восстановления нужен вес %d:",
+		"weighted.holder":                 "%s (вес %d):",
+		"weighted.hint":                   "Владельцы, чей суммарный вес не меньше %d, восстанавливают секрет вместе, передав все свои части команде: shamir-cli combine",
+		"weighted.invalid_spec":           "Ошибка: некорректный владелец '%s', ожидается holder:weight с весом не меньше 1",
+		"weighted.duplicate_holder":       "Ошибка: владелец '%s' указан несколько раз",
+		"weighted.too_many_parts":         "Ошибка: сумма весов даёт %d частей, максимум %d",
+		"weighted.weight_below_threshold": "Ошибка: сумма весов %d меньше порога %d",
+
+		"combine.min_parts":                "Ошибка: для восстановления требуется минимум 2 части",
+		"combine.min_valid_parts":          "Ошибка: для восстановления требуется минимум 2 корректные части",
+		"combine.below_threshold":          "Ошибка: для восстановления требуется частей: %d, передано только %d",
+		"combine.strict_excess":            "Ошибка: передано частей: %d, но --strict допускает только пороговое число %d",
+		"combine.invalid_require_holders":  "Ошибка: --require-holders не может быть отрицательным, получено %d",
+		"combine.require_holders_gf8_only": "Ошибка: --require-holders поддерживается только с --field gf8",
+		"combine.insufficient_holders":     "Ошибка: нужны части от %d разных владельцев, получено от %d (%s)",
+		"combine.no_holders":               "без меток",
+		"combine.strict_threshold":         "Ошибка: для --strict нужен порог; эти части его не содержат, укажите его через --threshold",
+		"combine.strict_gf8_only":          "Ошибка: --strict и --threshold поддерживаются только с --field gf8",
+		"combine.positional_gf8_only":      "Ошибка: --positional поддерживается только с --field gf8",
+		"combine.invalid_threshold":        "Ошибка: --threshold должен быть не меньше 2, указано %d",
+		"combine.parts_present":            "Переданы части: %s",
+		"combine.parts_enough":             "(требуется %d, достаточно для восстановления)",
+		"combine.parts_missing":            "(требуется %d, не хватает %d)",
+		"combine.conflicting_parts":        "Ошибка: части с одинаковым ID различаются: %v",
+		"combine.failed":                   "Ошибка при восстановлении: %v",
+		"combine.result":                   "Восстановленный секрет: %s",
+		"combine.result_base64":            "Восстановленный секрет (двоичный, base64): %s",
+		"combine.written":                  "Восстановленный секрет (%d байт) записан в %s",
+		"combine.info_ok":                  "Восстановление успешно (%d байт)",
+		"combine.invalid_format":           "Ошибка: неизвестный формат вывода '%s' (поддерживаются: text, raw-bytes)",
+		"combine.commit_ok":                "Обязательство подтверждено: восстановленный секрет совпадает",
+		"combine.exec_empty":               "Ошибка: для --exec требуется команда",
+		"combine.exec_failed":              "Ошибка: команда '%s' завершилась с ошибкой: %v",
+		"combine.commit_mismatch":          "Ошибка: восстановленный секрет не соответствует обязательству; части согласованы, но относятся к другому секрету",
+		"combine.invalid_commit":           "Ошибка: некорректное обязательство '%s': %v",
+		"combine.invalid_range":            "Ошибка: --offset и --length не могут быть отрицательными",
+		"combine.range_out_of_bounds":      "Ошибка: диапазон %d-%d выходит за пределы восстановленного секрета длиной %d байт",
+		"combine.auth_failed":              "Ошибка: переданные части (%d) не соответствуют тегу аутентификации; часть была изменена или набор неполон (тег охватывает все части, созданные split)",
+		"combine.invalid_auth_key":         "Ошибка: некорректный ключ аутентификации: %v",
+		"combine.invalid_auth_tag":         "Ошибка: некорректный тег аутентификации: %v",
+		"combine.auth_gf8_only":            "Ошибка: --auth-key поддерживается только с --field gf8",
+		"combine.write_failed":             "Ошибка записи секрета: %v",
+		"combine.clipboard_args":           "Ошибка: с --clipboard части нельзя передавать аргументом",
+		"combine.csv_args":                 "Ошибка: с --csv части нельзя передавать аргументом",
+		"combine.qr_dir_args":              "Ошибка: с --qr-dir части нельзя передавать аргументом",
+		"combine.share_url_args":           "Ошибка: с --share-url части нельзя передавать аргументом",
+		"combine.share_url_gf8_only":       "Ошибка: --share-url поддерживается только с --field gf8",
+		"combine.invalid_share_url":        "Ошибка: неверный --share-url %q: %v",
+		"combine.share_url_failed":         "Ошибка получения части с %s: %v",
+		"combine.csv_gf8_only":             "Ошибка: --csv поддерживается только с --field gf8",
+		"combine.env_args":                 "Ошибка: с --from-env части нельзя передавать аргументом",
+		"combine.env_gf8_only":             "Ошибка: --from-env поддерживается только с --field gf8",
+		"combine.env_none":                 "Ошибка: переменные окружения %s<n> не заданы",
+		"combine.partial":                  "Ошибка: удалось восстановить только %d байт, и они не проверены контрольной суммой",
+
+		"field.unsupported":    "Ошибка: неподдерживаемое поле '%s' (поддерживаются: gf8, gf16)",
+		"field.gf16_text_only": "Ошибка: части над gf16 можно вывести только в текстовом формате",
+
+		"verbose.warning": "ВНИМАНИЕ: --verbose выводит случайные коэффициенты многочленов. Любой, кто их увидит, сможет восстановить секрет. Используйте только для демонстрации!",
+		"seed.warning":    "ВНИМАНИЕ: --seed делает части воспроизводимыми. Любой, кто знает seed, сможет восстановить секрет по одной части. Никогда не используйте его для настоящих секретов!",
+		"seed.kdf":        "Коэффициенты выведены из --seed с помощью %s; сохраните эти параметры, чтобы воспроизвести части",
+
+		"verbose.polynomial": "Многочлен степени %d для байта 0: f(x) = %s",
+
+		"progress.split":   "Разделение",
+		"progress.combine": "Восстановление",
+
+		"prompt.enter":    "Введите секрет: ",
+		"prompt.confirm":  "Повторите секрет: ",
+		"prompt.mismatch": "секреты не совпадают",
+
+		"parse.part":                "Ошибка разбора части %d ('%s'): %v",
+		"parse.positional_length":   "Ошибка: в строке %d шестнадцатеричных цифр: %d, а в строке %d: %d; все значения должны быть одной длины",
+		"parse.positional_too_many": "Ошибка: передано строк: %d, но номера частей не превышают %d",
+		"parse.table":               "Ошибка чтения таблицы: %v",
+		"parse.no_parts":            "Ошибка: части не указаны",
+		"parse.armor":               "Ошибка разбора бронированных частей: %v",
+		"parse.chunks":              "Ошибка сборки фрагментов частей: %v",
+		"parse.csv":                 "Ошибка разбора частей из %s: %v",
+		"parse.env":                 "Ошибка разбора частей из окружения: %v",
+		"parse.share_url":           "Ошибка разбора части, полученной с %s: %v",
+		"parse.read_failed":         "Ошибка чтения частей: %v",
+
+		"inspect.header":             "ПРОВЕРКА\tРЕЗУЛЬТАТ",
+		"inspect.parts":              "Передано частей\t%d",
+		"inspect.ids":                "Различных ID\t%d (%s)",
+		"inspect.fingerprints":       "Отпечатки\t%s",
+		"inspect.duplicates":         "Повторяющихся частей\t%d",
+		"inspect.lengths_match":      "Длины совпадают\tда (%d байт)",
+		"inspect.lengths_mismatch":   "Длины совпадают\tнет (%d разных длин)",
+		"inspect.threshold_met":      "Порог\t%d (достигнут)",
+		"inspect.threshold_missing":  "Порог\t%d (не хватает частей: %d)",
+		"inspect.recovery_failed":    "Восстановление\tОШИБКА (%v)",
+		"inspect.recovery_ok":        "Восстановление\tOK (%d байт, секрет скрыт)",
+		"inspect.recovery_skipped":   "Восстановление\tпропущено",
+		"inspect.threshold_inferred": "Порог\t%d (определён по %d частям)",
+		"inspect.recoverable_yes":    "Восстановимо\tда",
+		"inspect.recoverable_no":     "Восстановимо\tнет",
+		"inspect.not_recoverable":    "Ошибка: по этим частям секрет восстановить нельзя",
+		"fingerprint.part":           "Часть %d: %s",
+		"fingerprint.part_labeled":   "Часть %d (%s): %s",
+
+		"check.header":          "ГРУППА\tДЛИНА ЧАСТИ\tЧАСТЕЙ\tID\tПОРОГ\tСТАТУС",
+		"check.status_ok":       "восстановим",
+		"check.status_missing":  "не хватает частей: %d",
+		"check.status_unknown":  "порог неизвестен",
+		"check.skipped":         "Пропущен %s: %v",
+		"check.scan_failed":     "Ошибка обхода каталога: %v",
+		"check.no_parts":        "Ошибка: в %s не найдено частей",
+		"check.not_recoverable": "Ошибка: групп, в которых не хватает частей для восстановления секрета: %d",
+
+		"selftest.pass":   "OK      %s",
+		"selftest.fail":   "ОШИБКА  %s: %v",
+		"selftest.passed": "Все проверки пройдены: %d",
+		"selftest.failed": "Ошибка: не пройдено проверок: %d из %d",
+
+		"doctor.threshold":   "Ошибка: порог неизвестен; укажите его через --threshold",
+		"doctor.failed":      "Ошибка: %v",
+		"doctor.summary":     "Проверено подмножеств из %[2]d частей: %[1]d; одинаковый секрет восстанавливают: %[3]d.",
+		"doctor.none":        "Ошибка: ни одно подмножество из %d частей не восстанавливает секрет; повреждено слишком много частей или порог указан неверно",
+		"doctor.header":      "Часть\tСогласованных подмножеств\tВывод",
+		"doctor.row":         "%d\t%d из %d\t%s",
+		"doctor.ok":          "в порядке",
+		"doctor.suspect":     "вероятно повреждена",
+		"doctor.no_suspects": "Повреждённых частей не найдено.",
+		"doctor.suspects":    "Вероятно повреждённые части: %s. Восстановите секрет без них.",
+
+		"verify_table.ok":       "Строка %d (часть %d): OK",
+		"verify_table.mismatch": "Строка %d (часть %d): CRC не совпадает, записано %02x, а по строке выходит %02x",
+		"verify_table.passed":   "Все строки проверены: %d",
+		"verify_table.failed":   "Ошибка: неверный CRC в строках: %d из %d; сверьте их с оригиналом",
+
+		"version.version": "shamir-cli %s",
+		"version.commit":  "Коммит:        %s",
+		"version.date":    "Сборка:        %s",
+		"version.format":  "Формат частей: версия %d (читает версии до %d)",
+
+		"timeout.expired": "Ошибка: операция не завершилась за %s",
+
+		"clipboard.warning":      "ВНИМАНИЕ: буфер обмена доступен другим приложениям. Очистите его, когда его содержимое станет не нужно.",
+		"clipboard.write_failed": "Ошибка копирования в буфер обмена: %v",
+		"clipboard.read_failed":  "Ошибка чтения буфера обмена: %v",
+
+		"completion.failed": "Ошибка генерации автодополнения: %v",
+		"man.mkdir_failed":  "Ошибка создания каталога: %v",
+		"man.failed":        "Ошибка генерации man-страниц: %v",
+		"man.written":       "Man-страницы записаны в %s",
+	},
+}
+
+// tr returns the message with the given id in the selected language,
+// falling back to English when no translation exists
+func tr(id string, args ...interface{}) string {
+	format, ok := messages[lang][id]
+	if !ok {
+		format = messages["en"][id]
+	}
+	return fmt.Sprintf(format, args...)
+}
+
+// defaultLang derives the message language from $LANG (e.g. "ru_RU.UTF-8")
+func defaultLang() string {
+	code := strings.ToLower(os.Getenv("LANG"))
+	if len(code) >= 2 {
+		if _, ok := messages[code[:2]]; ok {
+			return code[:2]
+		}
+	}
+	return "en"
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"fmt"
+
+	"shamir-cli/shamir"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	migrateParts     int
+	migrateThreshold int
+	migrateFormat    string
+)
+
+var migrateCmd = &cobra.Command{
+	Use:   "migrate [parts_separated_by_commas]",
+	Short: "Re-split version 1 parts into parts with a CRC-32 checksum",
+	Long: `Recovers the secret from parts of format version 1, whose secret is only
+protected by a one-byte XOR checksum, and splits it again into parts with
+a CRC-32 checksum (format version 4), which detects wrong parts far more
+reliably. n new parts are created, where n is given with --parts, with
+the same threshold, taken from armored parts or given with --threshold.
+They keep the IDs of the old parts given, so every holder gets a part
+with the same ID back; missing IDs are filled with the lowest free ones.
+At least threshold old parts are needed. The secret is not printed.
+
+Old and new parts cannot be combined together. Hand out the new parts and
+destroy all old ones: any threshold of them still recover the secret.
+
+Padded and header parts cannot be migrated without losing their padding
+or header, and are refused. Parts are read from standard input when no
+argument is given.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		out := cmd.OutOrStdout()
+		ctx, cancel := commandContext(cmd)
+		defer cancel()
+
+		if migrateParts == 0 {
+			return newError(codeUsage, "migrate.parts")
+		}
+		switch migrateFormat {
+		case formatText, formatArmor, formatCompact, formatBase32:
+		default:
+			return newError(codeUsage, "migrate.invalid_format", migrateFormat)
+		}
+
+		input, err := readShareInput(ctx, cmd.InOrStdin(), args)
+		if err != nil {
+			return newError(codeIO, "parse.read_failed", err)
+		}
+		shares, threshold, err := parseShareInput(input)
+		if err != nil {
+			return err
+		}
+		if migrateThreshold > 0 {
+			threshold = migrateThreshold
+		}
+		if threshold == 0 {
+			return newError(codeUsage, "migrate.threshold")
+		}
+		if shares, err = shamir.DedupShares(shares); err != nil {
+			return newError(codeParse, "combine.conflicting_parts", err)
+		}
+		if len(shares) < threshold {
+			return newError(codeInsufficientShares, "combine.below_threshold", threshold, len(shares))
+		}
+
+		migrated, err := shamir.MigrateCRC32(shares, migrateParts, threshold)
+		if err != nil {
+			return newError(errorCode(err, codeCombine), "migrate.failed", err)
+		}
+
+		fmt.Fprintf(out, "%s\n\n", colorize(out, colorBold, tr("split.header", migrateParts, threshold)))
+		for i, share := range migrated {
+			if migrateFormat == formatArmor {
+				fmt.Fprintln(out, encodeShare(share, threshold, migrateFormat))
+				continue
+			}
+			fmt.Fprintln(out, tr("split.part", i+1, encodeShare(share, threshold, migrateFormat)))
+		}
+		printSplitSummary(out, migrateParts, threshold)
+		fmt.Fprintf(out, "\n%s\n", tr("migrate.hint"))
+		return nil
+	},
+}
+
+func init() {
+	migrateCmd.Flags().IntVarP(&migrateParts, "parts", "n", 0, "number of new parts to create")
+	migrateCmd.Flags().IntVarP(&migrateThreshold, "threshold", "k", 0, "number of parts required for recovery, for parts that do not record it")
+	migrateCmd.Flags().StringVar(&migrateFormat, "format", formatText, "output format of the new parts: text, armor, compact or base32")
+}
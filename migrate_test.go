@@ -0,0 +1,95 @@
+package main
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"shamir-cli/shamir"
+)
+
+func TestMigrate(t *testing.T) {
+	old, err := shamir.Split([]byte("old format"), 4, 3)
+	if err != nil {
+		t.Fatalf("Split failed: %v", err)
+	}
+	given := shamir.ShareToString(old[3]) + "," + shamir.ShareToString(old[0]) + "," + shamir.ShareToString(old[2])
+
+	out, err := executeCommand(t, "", "migrate", "-n", "4", "-k", "3", given)
+	if err != nil {
+		t.Fatalf("migrate failed: %v\n%s", err, out)
+	}
+	var parts []string
+	for _, line := range strings.Split(out, "\n") {
+		if _, part, ok := strings.Cut(line, "Part "); ok {
+			_, part, _ = strings.Cut(part, ": ")
+			parts = append(parts, part)
+		}
+	}
+	if len(parts) != 4 || !strings.HasPrefix(parts[0], "v4:1:") || strings.Contains(out, "old format") {
+		t.Fatalf("Unexpected migrate output:\n%s", out)
+	}
+
+	out, err = executeCommand(t, "", "combine", strings.Join(parts[1:], ","))
+	if err != nil {
+		t.Fatalf("combine of migrated parts failed: %v", err)
+	}
+	if out != "Recovered secret: old format\n" {
+		t.Errorf("combine output = %q", out)
+	}
+
+	// The IDs of the given parts are kept
+	out, err = executeCommand(t, "", "migrate", "-n", "3", "-k", "3", given)
+	if err != nil {
+		t.Fatalf("migrate failed: %v\n%s", err, out)
+	}
+	for _, id := range []string{"v4:1:", "v4:3:", "v4:4:"} {
+		if !strings.Contains(out, id) {
+			t.Errorf("migrate output lacks part %s\n%s", id, out)
+		}
+	}
+
+	// The threshold recorded in armored parts is kept
+	armored := shamir.ArmorShare(old[0], 3) + shamir.ArmorShare(old[1], 3) + shamir.ArmorShare(old[2], 3)
+	out, err = executeCommand(t, "", "migrate", "-n", "5", "--format", "armor", "--", armored)
+	if err != nil {
+		t.Fatalf("migrate of armored parts failed: %v", err)
+	}
+	if strings.Count(out, "Version: 4\nThreshold: 3\n") != 5 {
+		t.Errorf("Unexpected migrate output:\n%s", out)
+	}
+}
+
+func TestMigrateErrors(t *testing.T) {
+	old, err := shamir.Split([]byte("old format"), 4, 3)
+	if err != nil {
+		t.Fatalf("Split failed: %v", err)
+	}
+	two := shamir.ShareToString(old[0]) + "," + shamir.ShareToString(old[1])
+	padded, err := shamir.SplitPadded([]byte("padded"), 3, 2, 16)
+	if err != nil {
+		t.Fatalf("SplitPadded failed: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		args []string
+		code string
+	}{
+		{"no parts count", []string{"migrate", "-k", "2", two}, codeUsage},
+		{"no threshold", []string{"migrate", "-n", "4", two}, codeUsage},
+		{"below threshold", []string{"migrate", "-n", "4", "-k", "3", two}, codeInsufficientShares},
+		{"n below k", []string{"migrate", "-n", "1", "-k", "2", two}, codeThresholdTooLarge},
+		{"padded", []string{"migrate", "-n", "3", "-k", "2", shamir.ShareToString(padded[0]) + "," + shamir.ShareToString(padded[1])}, codeCombine},
+		{"format", []string{"migrate", "-n", "4", "-k", "2", "--format", "csv", two}, codeUsage},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := executeCommand(t, "", tt.args...)
+			var e *cliError
+			if !errors.As(err, &e) || e.Code != tt.code {
+				t.Errorf("%v returned %v, want error code %q", tt.args, err, tt.code)
+			}
+		})
+	}
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"shamir-cli/shamir"
+
+	"golang.org/x/term"
+)
+
+// progressWidth is the number of characters in the rendered progress bar
+const progressWidth = 30
+
+// newProgressBar returns a ProgressFunc that renders a progress bar to w,
+// redrawing only when the percentage changes
+func newProgressBar(w io.Writer, label string) shamir.ProgressFunc {
+	last := -1
+	return func(done, total int) {
+		filled, percent := progressWidth, 100
+		if total > 0 {
+			filled = done * progressWidth / total
+			percent = done * 100 / total
+		}
+		if percent == last {
+			return
+		}
+		last = percent
+
+		fmt.Fprintf(w, "\r%s [%s%s] %3d%% (%d/%d)", label,
+			strings.Repeat("#", filled), strings.Repeat("-", progressWidth-filled),
+			percent, done, total)
+		if done == total {
+			fmt.Fprintln(w)
+		}
+	}
+}
+
+// stdoutIsTerminal reports whether standard output is an interactive
+// terminal; tests replace it to exercise terminal-only output
+var stdoutIsTerminal = func() bool {
+	return term.IsTerminal(int(os.Stdout.Fd()))
+}
@@ -0,0 +1,24 @@
+package main
+
+import (
+	"testing"
+
+	"shamir-cli/shamir"
+)
+
+func TestCombineProgress(t *testing.T) {
+	shares, err := shamir.Split([]byte("progress secret"), 3, 2)
+	if err != nil {
+		t.Fatalf("Split failed: %v", err)
+	}
+	parts := shamir.ShareToString(shares[0]) + "," + shamir.ShareToString(shares[2])
+
+	// The progress bar goes to stderr and leaves the output clean
+	out, err := executeCommand(t, "", "combine", "--progress", parts)
+	if err != nil {
+		t.Fatalf("combine --progress failed: %v", err)
+	}
+	if out != "Recovered secret: progress secret\n" {
+		t.Errorf("combine --progress output = %q", out)
+	}
+}
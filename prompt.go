@@ -0,0 +1,58 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"shamir-cli/shamir"
+
+	"golang.org/x/term"
+)
+
+// passwordReader returns a function reading one line without echo when in
+// is a terminal, or nil otherwise; tests replace it to simulate a terminal
+var passwordReader = func(in io.Reader) func() ([]byte, error) {
+	f, ok := in.(*os.File)
+	if !ok || !term.IsTerminal(int(f.Fd())) {
+		return nil
+	}
+	return func() ([]byte, error) { return term.ReadPassword(int(f.Fd())) }
+}
+
+// promptSecret reads a secret from the terminal with echo disabled, asking
+// twice to confirm it, and writes the prompts to w. When in is not a
+// terminal the secret is read from it as is, without the trailing newline.
+func promptSecret(in io.Reader, w io.Writer) ([]byte, error) {
+	readPassword := passwordReader(in)
+	if readPassword == nil {
+		data, err := io.ReadAll(in)
+		if err != nil {
+			return nil, err
+		}
+		data = bytes.TrimSuffix(data, []byte("\n"))
+		data = bytes.TrimSuffix(data, []byte("\r"))
+		return data, nil
+	}
+
+	fmt.Fprint(w, tr("prompt.enter"))
+	secret, err := readPassword()
+	fmt.Fprintln(w)
+	if err != nil {
+		return nil, err
+	}
+
+	fmt.Fprint(w, tr("prompt.confirm"))
+	confirm, err := readPassword()
+	fmt.Fprintln(w)
+	if err != nil {
+		return nil, err
+	}
+
+	if !shamir.SecretsEqual(secret, confirm) {
+		return nil, errors.New(tr("prompt.mismatch"))
+	}
+	return secret, nil
+}
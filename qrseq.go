@@ -0,0 +1,89 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"shamir-cli/shamir"
+)
+
+// qrSequenceDigits is the default number of hex digits per chunk written
+// by split --qr-sequence, small enough for QR codes that phones scan
+// reliably
+const qrSequenceDigits = 256
+
+// qrSequenceHolder returns the name of the holder of a share used in the
+// file names written by split --qr-sequence: its label, or part<ID>
+func qrSequenceHolder(share shamir.Share) string {
+	if share.Label != "" {
+		return share.Label
+	}
+	return fmt.Sprintf("part%d", share.ID)
+}
+
+// qrSequenceName returns the file name of chunk seq of total of a holder,
+// e.g. alice-1of3.txt
+func qrSequenceName(holder string, seq, total int) string {
+	return fmt.Sprintf("%s-%dof%d.txt", holder, seq, total)
+}
+
+// writeQRSequence writes every chunk of every share to its own file in dir,
+// holding the chunk string to encode as one QR code, and returns the paths
+// grouped by share. chunked holds the chunk strings of each share, as
+// returned by chunkShares.
+func writeQRSequence(dir string, shares []shamir.Share, chunked [][]string) ([][]string, error) {
+	names := make(map[string]bool)
+	for _, share := range shares {
+		holder := qrSequenceHolder(share)
+		if names[holder] {
+			return nil, fmt.Errorf("two parts have the holder name %q", holder)
+		}
+		names[holder] = true
+	}
+
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, err
+	}
+	paths := make([][]string, len(shares))
+	for i, share := range shares {
+		for j, chunk := range chunked[i] {
+			path := filepath.Join(dir, qrSequenceName(qrSequenceHolder(share), j+1, len(chunked[i])))
+			if err := os.WriteFile(path, []byte(chunk+"\n"), 0600); err != nil {
+				return nil, err
+			}
+			paths[i] = append(paths[i], path)
+		}
+	}
+	return paths, nil
+}
+
+// readQRDir reads the strings scanned from QR codes into the files of dir,
+// one or more per file, for combine --qr-dir. Subdirectories and hidden
+// files are skipped. The file names do not matter: chunks are reassembled
+// by the sequence and part ID they carry.
+func readQRDir(ctx context.Context, dir string) (string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return "", err
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Name() < entries[j].Name() })
+
+	var scanned []string
+	for _, entry := range entries {
+		if !entry.Type().IsRegular() || strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+		data, err := readFileContext(ctx, filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return "", err
+		}
+		if s := strings.TrimSpace(string(data)); s != "" {
+			scanned = append(scanned, s)
+		}
+	}
+	return strings.Join(scanned, "\n"), nil
+}
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"shamir-cli/shamir"
+)
+
+func TestQRSequenceName(t *testing.T) {
+	tests := []struct {
+		share shamir.Share
+		seq   int
+		total int
+		want  string
+	}{
+		{shamir.Share{ID: 1, Label: "alice"}, 1, 3, "alice-1of3.txt"},
+		{shamir.Share{ID: 12}, 2, 2, "part12-2of2.txt"},
+		{shamir.Share{ID: 255, Label: "bob"}, 1, 1, "bob-1of1.txt"},
+	}
+	for _, tt := range tests {
+		if got := qrSequenceName(qrSequenceHolder(tt.share), tt.seq, tt.total); got != tt.want {
+			t.Errorf("qrSequenceName(%+v, %d, %d) = %q, want %q", tt.share, tt.seq, tt.total, got, tt.want)
+		}
+	}
+}
+
+func TestWriteQRSequence(t *testing.T) {
+	shares, err := shamir.Split([]byte(strings.Repeat("q", 40)), 2, 2)
+	if err != nil {
+		t.Fatalf("Split failed: %v", err)
+	}
+	shares[0].Label = "alice"
+	chunked, err := chunkShares(shares, 30)
+	if err != nil {
+		t.Fatalf("chunkShares failed: %v", err)
+	}
+
+	dir := t.TempDir()
+	paths, err := writeQRSequence(dir, shares, chunked)
+	if err != nil {
+		t.Fatalf("writeQRSequence failed: %v", err)
+	}
+	want := [][]string{
+		{"alice-1of3.txt", "alice-2of3.txt", "alice-3of3.txt"},
+		{"part2-1of3.txt", "part2-2of3.txt", "part2-3of3.txt"},
+	}
+	for i := range want {
+		for j, name := range want[i] {
+			if paths[i][j] != filepath.Join(dir, name) {
+				t.Errorf("path of part %d chunk %d = %s, want %s", i+1, j+1, paths[i][j], name)
+			}
+			data, err := os.ReadFile(paths[i][j])
+			if err != nil || string(data) != chunked[i][j]+"\n" {
+				t.Errorf("%s = %q, %v, want %q", name, data, err, chunked[i][j])
+			}
+		}
+	}
+
+	shares[1].Label = "alice"
+	if _, err := writeQRSequence(t.TempDir(), shares, chunked); err == nil {
+		t.Error("writeQRSequence accepted two holders with the same name")
+	}
+}
+
+func TestQRSequenceRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	secret := strings.Repeat("offline vault ", 30)
+	out, err := executeCommand(t, "", "split", "--labels", "alice,bob,carol", "--qr-sequence", dir, secret, "3", "2")
+	if err != nil {
+		t.Fatalf("split --qr-sequence failed: %v\n%s", err, out)
+	}
+	if !strings.Contains(out, "Part 1, chunk 1 of 4 written to "+filepath.Join(dir, "alice-1of4.txt")) {
+		t.Fatalf("Unexpected split output:\n%s", out)
+	}
+
+	// Scanned strings are saved under other names, some in one file, and
+	// the codes of one holder are lost
+	scanned := t.TempDir()
+	read := func(name string) string {
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(data)
+	}
+	files := map[string]string{
+		"scan-01.txt": read("carol-3of4.txt"),
+		"scan-02.txt": read("alice-2of4.txt") + read("carol-1of4.txt"),
+		"scan-03.txt": read("alice-4of4.txt"),
+		"scan-04.txt": read("carol-2of4.txt") + read("alice-1of4.txt"),
+		"scan-05.txt": read("alice-3of4.txt") + read("carol-4of4.txt"),
+		".hidden":     "not a chunk",
+	}
+	for name, data := range files {
+		if err := os.WriteFile(filepath.Join(scanned, name), []byte(data), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	out, err = executeCommand(t, "", "combine", "--qr-dir", scanned)
+	if err != nil {
+		t.Fatalf("combine --qr-dir failed: %v\n%s", err, out)
+	}
+	if out != "Recovered secret: "+secret+"\n" {
+		t.Errorf("combine output = %q", out)
+	}
+
+	// A missing chunk is reported
+	if err := os.Remove(filepath.Join(scanned, "scan-03.txt")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := executeCommand(t, "", "combine", "--qr-dir", scanned); err == nil {
+		t.Error("combine --qr-dir succeeded with a missing chunk")
+	}
+}
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"shamir-cli/shamir"
+
+	"github.com/spf13/cobra"
+)
+
+// shareURLTimeout bounds fetching one part with --share-url
+const shareURLTimeout = 10 * time.Second
+
+// maxShareURLBody is the largest response body accepted as a part
+const maxShareURLBody = 1 << 20
+
+// shareHTTPClient fetches parts given with --share-url. The default
+// transport verifies the server certificate; tests replace the client to
+// trust their own server.
+var shareHTTPClient = &http.Client{Timeout: shareURLTimeout, CheckRedirect: checkShareRedirect}
+
+// checkShareRedirect refuses redirects away from https, which would send
+// the part unencrypted, and otherwise applies the default limit of 10
+// redirects
+func checkShareRedirect(req *http.Request, via []*http.Request) error {
+	if req.URL.Scheme != "https" {
+		return fmt.Errorf("refusing redirect to %s URL", req.URL.Scheme)
+	}
+	if len(via) >= 10 {
+		return errors.New("stopped after 10 redirects")
+	}
+	return nil
+}
+
+// parseShareURL checks that a part URL is an absolute https URL, so parts
+// never travel unencrypted
+func parseShareURL(raw string) (*url.URL, error) {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return nil, err
+	}
+	if u.Scheme != "https" || u.Host == "" {
+		return nil, errors.New("only https URLs are supported")
+	}
+	return u, nil
+}
+
+// fetchShare returns the body served at u with surrounding whitespace
+// removed, which should be a single part in the form ID:hex
+func fetchShare(ctx context.Context, client *http.Client, u *url.URL) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return "", err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("server returned %s", resp.Status)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxShareURLBody+1))
+	if err != nil {
+		return "", err
+	}
+	if len(body) > maxShareURLBody {
+		return "", fmt.Errorf("response is larger than %d bytes", maxShareURLBody)
+	}
+	return strings.TrimSpace(string(body)), nil
+}
+
+// combineURLs recovers the secret from the parts fetched from the given
+// URLs, one part per URL
+func combineURLs(ctx context.Context, cmd *cobra.Command, urls []string, args []string) error {
+	if len(args) > 0 {
+		return newError(codeUsage, "combine.share_url_args")
+	}
+	if combineField != fieldGF8 {
+		return newError(codeUsage, "combine.share_url_gf8_only")
+	}
+
+	parsed := make([]*url.URL, len(urls))
+	for i, raw := range urls {
+		u, err := parseShareURL(raw)
+		if err != nil {
+			return newError(codeUsage, "combine.invalid_share_url", raw, err)
+		}
+		parsed[i] = u
+	}
+
+	shares := make([]shamir.Share, 0, len(parsed))
+	for _, u := range parsed {
+		// The URL may carry credentials, which are not repeated in errors
+		body, err := fetchShare(ctx, shareHTTPClient, u)
+		if err != nil {
+			return newError(codeIO, "combine.share_url_failed", u.Redacted(), err)
+		}
+		share, err := shamir.StringToShare(body)
+		if err != nil {
+			return newError(codeParse, "parse.share_url", u.Redacted(), err)
+		}
+		shares = append(shares, share)
+	}
+	return combineShares(ctx, cmd, shares, 0)
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"shamir-cli/shamir"
+)
+
+func TestCombineShareURL(t *testing.T) {
+	shares, err := shamir.Split([]byte("fetched secret"), 3, 2)
+	if err != nil {
+		t.Fatalf("Split failed: %v", err)
+	}
+
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/alice":
+			fmt.Fprintln(w, shamir.ShareToString(shares[0]))
+		case "/carol":
+			fmt.Fprint(w, shamir.ShareToString(shares[2]))
+		case "/garbage":
+			fmt.Fprint(w, "not a part")
+		case "/moved":
+			http.Redirect(w, r, "https://"+r.Host+"/carol", http.StatusFound)
+		case "/downgrade":
+			http.Redirect(w, r, "http://"+r.Host+"/carol", http.StatusFound)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	saved := shareHTTPClient
+	client := srv.Client()
+	client.CheckRedirect = checkShareRedirect
+	shareHTTPClient = client
+	t.Cleanup(func() { shareHTTPClient = saved })
+
+	out, err := executeCommand(t, "", "combine", "--share-url", srv.URL+"/alice", "--share-url", srv.URL+"/carol")
+	if err != nil {
+		t.Fatalf("combine --share-url failed: %v", err)
+	}
+	if out != "Recovered secret: fetched secret\n" {
+		t.Errorf("combine output = %q", out)
+	}
+
+	tests := []struct {
+		name string
+		args []string
+		code string
+	}{
+		{"not found", []string{"combine", "--share-url", srv.URL + "/alice", "--share-url", srv.URL + "/bob"}, codeIO},
+		{"invalid body", []string{"combine", "--share-url", srv.URL + "/alice", "--share-url", srv.URL + "/garbage"}, codeParse},
+		{"plain http", []string{"combine", "--share-url", "http://example.com/alice"}, codeUsage},
+		{"with argument", []string{"combine", "--share-url", srv.URL + "/alice", "1:ab,2:cd"}, codeUsage},
+		{"one part", []string{"combine", "--share-url", srv.URL + "/alice"}, codeInsufficientShares},
+		{"https redirect", []string{"combine", "--share-url", srv.URL + "/alice", "--share-url", srv.URL + "/moved"}, ""},
+		{"http redirect", []string{"combine", "--share-url", srv.URL + "/alice", "--share-url", srv.URL + "/downgrade"}, codeIO},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := executeCommand(t, "", tt.args...)
+			if tt.code == "" {
+				if err != nil {
+					t.Errorf("%v failed: %v", tt.args, err)
+				}
+				return
+			}
+			var e *cliError
+			if !errors.As(err, &e) || e.Code != tt.code {
+				t.Errorf("%v returned %v, want error code %q", tt.args, err, tt.code)
+			}
+		})
+	}
+
+	// The default client rejects the self-signed certificate of the test
+	// server
+	shareHTTPClient = saved
+	_, err = executeCommand(t, "", "combine", "--share-url", srv.URL+"/alice", "--share-url", srv.URL+"/carol")
+	var e *cliError
+	if !errors.As(err, &e) || e.Code != codeIO || !strings.Contains(err.Error(), "certificate") {
+		t.Errorf("combine with an untrusted certificate returned %v, want a certificate error", err)
+	}
+}
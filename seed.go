@@ -0,0 +1,46 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"io"
+)
+
+// seededReader is a deterministic random stream derived from a seed: the
+// SHA-256 of the seed hashed together with a block counter. Anyone who
+// knows the seed can reproduce the stream, so it must never be used to
+// split real secrets.
+type seededReader struct {
+	key     [sha256.Size]byte
+	counter uint64
+	block   []byte
+}
+
+// newSeededReader returns a deterministic random stream for seed
+func newSeededReader(seed string) io.Reader {
+	return newKeyedReader(sha256.Sum256([]byte(seed)))
+}
+
+// newKeyedReader returns the deterministic random stream for a key derived
+// from the seed, e.g. by a memory-hard KDF
+func newKeyedReader(key [sha256.Size]byte) io.Reader {
+	return &seededReader{key: key}
+}
+
+func (r *seededReader) Read(p []byte) (int, error) {
+	n := 0
+	for n < len(p) {
+		if len(r.block) == 0 {
+			var input [sha256.Size + 8]byte
+			copy(input[:], r.key[:])
+			binary.BigEndian.PutUint64(input[sha256.Size:], r.counter)
+			block := sha256.Sum256(input[:])
+			r.block = block[:]
+			r.counter++
+		}
+		copied := copy(p[n:], r.block)
+		r.block = r.block[copied:]
+		n += copied
+	}
+	return n, nil
+}
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"fmt"
+
+	"shamir-cli/shamir"
+
+	"github.com/spf13/cobra"
+)
+
+var selfTestCmd = &cobra.Command{
+	Use:   "selftest",
+	Short: "Check that this binary computes the field correctly",
+	Long: `Runs a self-test: checks the GF(2^8) multiplication table and that
+a*inv(a)=1 for every nonzero element, splits and combines random secrets for
+many (n, k) combinations including k=2, k=n and n=255, and checks empty
+secrets and parameter validation. Exits with an error if any check fails.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		out := cmd.OutOrStdout()
+
+		results := shamir.SelfTest()
+		failed := 0
+		for _, r := range results {
+			if r.Err != nil {
+				failed++
+				fmt.Fprintln(out, tr("selftest.fail", r.Name, r.Err))
+			} else {
+				fmt.Fprintln(out, tr("selftest.pass", r.Name))
+			}
+		}
+
+		fmt.Fprintln(out)
+		if failed > 0 {
+			return newError(codeSelfTest, "selftest.failed", failed, len(results))
+		}
+		fmt.Fprintln(out, tr("selftest.passed", len(results)))
+		return nil
+	},
+}
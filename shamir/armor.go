@@ -0,0 +1,178 @@
+package shamir
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const (
+	armorBegin     = "-----BEGIN SHAMIR SHARE-----"
+	armorEnd       = "-----END SHAMIR SHARE-----"
+	armorThreshold = "Threshold"
+	armorVersion   = "Version"
+	armorKDF       = "KDF"
+	armorLineWidth = 64
+)
+
+// ArmorShare wraps a share in a PEM-style armored block containing the
+// base64 of ID||Value. A positive threshold and a version above 1 are
+// recorded in header lines.
+func ArmorShare(share Share, threshold int) string {
+	return ArmorShareKDF(share, threshold, "")
+}
+
+// ArmorShareKDF is like ArmorShare but also records in a KDF header line
+// how the random coefficients were derived from a passphrase, e.g.
+// "argon2id m=65536 t=3 p=4", so that the split can be reproduced. An empty
+// kdf writes no header. The header plays no part in combining.
+func ArmorShareKDF(share Share, threshold int, kdf string) string {
+	data := make([]byte, 0, 1+len(share.Value))
+	data = append(data, share.ID)
+	data = append(data, share.Value...)
+	encoded := base64.StdEncoding.EncodeToString(data)
+
+	var b strings.Builder
+	b.WriteString(armorBegin + "\n")
+	if share.Version > 1 {
+		fmt.Fprintf(&b, "%s: %d\n", armorVersion, share.Version)
+	}
+	if threshold > 0 {
+		fmt.Fprintf(&b, "%s: %d\n", armorThreshold, threshold)
+	}
+	if kdf != "" {
+		fmt.Fprintf(&b, "%s: %s\n", armorKDF, kdf)
+	}
+	if share.Version > 1 || threshold > 0 || kdf != "" {
+		b.WriteString("\n")
+	}
+	for len(encoded) > armorLineWidth {
+		b.WriteString(encoded[:armorLineWidth] + "\n")
+		encoded = encoded[armorLineWidth:]
+	}
+	b.WriteString(encoded + "\n")
+	b.WriteString(armorEnd + "\n")
+	return b.String()
+}
+
+// IsArmored reports whether s contains an armored share block
+func IsArmored(s string) bool {
+	return strings.Contains(s, armorBegin)
+}
+
+// CutArmor returns the text of s outside its armored share blocks, e.g.
+// other parts pasted along with them
+func CutArmor(s string) string {
+	var outside strings.Builder
+	rest := s
+	for {
+		start := strings.Index(rest, armorBegin)
+		if start < 0 {
+			break
+		}
+		end := strings.Index(rest[start:], armorEnd)
+		if end < 0 {
+			break
+		}
+		outside.WriteString(rest[:start] + "\n")
+		rest = rest[start+end+len(armorEnd):]
+	}
+	outside.WriteString(rest)
+	return outside.String()
+}
+
+// ParseArmor parses one or more concatenated armored share blocks. It returns
+// the shares and the threshold recorded in their headers, or 0 if none is.
+func ParseArmor(s string) ([]Share, int, error) {
+	var shares []Share
+	threshold := 0
+
+	rest := s
+	for {
+		start := strings.Index(rest, armorBegin)
+		if start < 0 {
+			break
+		}
+		rest = rest[start+len(armorBegin):]
+
+		end := strings.Index(rest, armorEnd)
+		if end < 0 {
+			return nil, 0, fmt.Errorf("armored block %d is not terminated", len(shares)+1)
+		}
+		block := rest[:end]
+		rest = rest[end+len(armorEnd):]
+
+		share, blockThreshold, err := parseArmorBlock(block)
+		if err != nil {
+			return nil, 0, fmt.Errorf("armored block %d: %v", len(shares)+1, err)
+		}
+
+		if blockThreshold > 0 {
+			if threshold > 0 && blockThreshold != threshold {
+				return nil, 0, fmt.Errorf("armored block %d: threshold %d differs from %d", len(shares)+1, blockThreshold, threshold)
+			}
+			threshold = blockThreshold
+		}
+		shares = append(shares, share)
+	}
+
+	if len(shares) == 0 {
+		return nil, 0, errors.New("no armored share blocks found")
+	}
+	return shares, threshold, nil
+}
+
+// parseArmorBlock decodes the content between the armor markers
+func parseArmorBlock(block string) (Share, int, error) {
+	threshold := 0
+	var version byte
+	var encoded strings.Builder
+
+	for _, line := range strings.Split(block, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+
+		if key, value, found := strings.Cut(line, ":"); found {
+			value = strings.TrimSpace(value)
+			switch strings.TrimSpace(key) {
+			case armorThreshold:
+				k, err := strconv.Atoi(value)
+				if err != nil || k < 2 {
+					return Share{}, 0, fmt.Errorf("invalid threshold %q", value)
+				}
+				threshold = k
+			case armorVersion:
+				v, err := strconv.ParseUint(value, 10, 8)
+				if err != nil || v == 0 {
+					return Share{}, 0, fmt.Errorf("invalid share version %q", value)
+				}
+				if v > CurrentVersion {
+					return Share{}, 0, fmt.Errorf("unsupported share version %d", v)
+				}
+				if v > 1 {
+					version = byte(v)
+				}
+			case armorKDF:
+				// Only needed to reproduce the split
+			default:
+				return Share{}, 0, fmt.Errorf("unknown header %q", key)
+			}
+			continue
+		}
+		encoded.WriteString(line)
+	}
+
+	data, err := base64.StdEncoding.DecodeString(encoded.String())
+	if err != nil {
+		return Share{}, 0, errors.New("invalid base64 data")
+	}
+	if len(data) < 2 {
+		return Share{}, 0, errors.New("share data is too short")
+	}
+
+	return Share{Version: version, ID: data[0], Value: data[1:]}, threshold, nil
+}
@@ -0,0 +1,124 @@
+package shamir
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestArmorRoundTrip(t *testing.T) {
+	secret := []byte(strings.Repeat("armored secret ", 10))
+	shares, err := Split(secret, 5, 3)
+	if err != nil {
+		t.Fatalf("Split failed: %v", err)
+	}
+
+	for _, share := range shares {
+		armored := ArmorShare(share, 3)
+		if !strings.HasPrefix(armored, armorBegin+"\n") || !strings.HasSuffix(armored, armorEnd+"\n") {
+			t.Fatalf("Unexpected armor framing:\n%s", armored)
+		}
+
+		parsed, threshold, err := ParseArmor(armored)
+		if err != nil {
+			t.Fatalf("ParseArmor failed: %v", err)
+		}
+		if threshold != 3 {
+			t.Errorf("Threshold = %d, want 3", threshold)
+		}
+		if len(parsed) != 1 || !parsed[0].Equal(share) {
+			t.Errorf("Round trip changed share %d", share.ID)
+		}
+	}
+}
+
+func TestParseArmorConcatenated(t *testing.T) {
+	secret := []byte("several blocks")
+	shares, err := Split(secret, 4, 2)
+	if err != nil {
+		t.Fatalf("Split failed: %v", err)
+	}
+
+	text := "Shares follow:\n" + ArmorShare(shares[3], 2) + "\n" + ArmorShare(shares[1], 0)
+	parsed, threshold, err := ParseArmor(text)
+	if err != nil {
+		t.Fatalf("ParseArmor failed: %v", err)
+	}
+	if threshold != 2 {
+		t.Errorf("Threshold = %d, want 2", threshold)
+	}
+	if len(parsed) != 2 || !parsed[0].Equal(shares[3]) || !parsed[1].Equal(shares[1]) {
+		t.Fatalf("Unexpected shares parsed: %v", parsed)
+	}
+
+	recovered, err := Combine(parsed)
+	if err != nil {
+		t.Fatalf("Combine failed: %v", err)
+	}
+	if string(recovered) != string(secret) {
+		t.Errorf("Recovery failed: got %q, want %q", recovered, secret)
+	}
+}
+
+func TestArmorVersion(t *testing.T) {
+	shares, err := SplitPadded([]byte("padded"), 3, 2, 16)
+	if err != nil {
+		t.Fatalf("SplitPadded failed: %v", err)
+	}
+
+	armored := ArmorShare(shares[0], 2)
+	if !strings.Contains(armored, "Version: 2\n") {
+		t.Errorf("Armored share does not record its version:\n%s", armored)
+	}
+
+	parsed, _, err := ParseArmor(armored)
+	if err != nil {
+		t.Fatalf("ParseArmor failed: %v", err)
+	}
+	if !parsed[0].Equal(shares[0]) {
+		t.Errorf("Round trip changed share: got %+v, want %+v", parsed[0], shares[0])
+	}
+}
+
+func TestArmorKDF(t *testing.T) {
+	share := Share{ID: 3, Value: []byte{0xab, 0xcd}}
+	armored := ArmorShareKDF(share, 2, "argon2id m=1024 t=1 p=4")
+	if !strings.Contains(armored, "Threshold: 2\nKDF: argon2id m=1024 t=1 p=4\n\n") {
+		t.Errorf("Armored share does not record the KDF:\n%s", armored)
+	}
+
+	parsed, threshold, err := ParseArmor(armored)
+	if err != nil {
+		t.Fatalf("ParseArmor failed: %v", err)
+	}
+	if threshold != 2 || !parsed[0].Equal(share) {
+		t.Errorf("Round trip changed share: got %+v and %d, want %+v and 2", parsed[0], threshold, share)
+	}
+}
+
+func TestParseArmorErrors(t *testing.T) {
+	share := Share{ID: 1, Value: []byte{0x12, 0x34}}
+	valid := ArmorShare(share, 2)
+
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{"No blocks", "1:1234"},
+		{"Unterminated", strings.TrimSuffix(valid, armorEnd+"\n")},
+		{"Bad base64", armorBegin + "\n!!!!\n" + armorEnd},
+		{"Too short", armorBegin + "\nAQ==\n" + armorEnd},
+		{"Unknown header", armorBegin + "\nComment: hi\n\nARI0\n" + armorEnd},
+		{"Bad threshold", armorBegin + "\nThreshold: 1\n\nARI0\n" + armorEnd},
+		{"Bad version", armorBegin + "\nVersion: x\n\nARI0\n" + armorEnd},
+		{"Unsupported version", armorBegin + "\nVersion: 9\n\nARI0\n" + armorEnd},
+		{"Conflicting thresholds", valid + ArmorShare(share, 3)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, _, err := ParseArmor(tt.input); err == nil {
+				t.Errorf("ParseArmor(%q) should fail", tt.input)
+			}
+		})
+	}
+}
@@ -0,0 +1,50 @@
+package shamir
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"sort"
+)
+
+// AuthKeySize is the size in bytes of the keys made by NewAuthKey
+const AuthKeySize = 32
+
+// NewAuthKey returns a random key for AuthTag
+func NewAuthKey() ([]byte, error) {
+	key := make([]byte, AuthKeySize)
+	if _, err := rand.Read(key); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrRandomSource, err)
+	}
+	return key, nil
+}
+
+// AuthTag returns an HMAC-SHA256 tag authenticating a set of shares as a
+// unit: changing, adding or removing any share changes the tag. Unlike the
+// checksum it can be verified without combining the shares, but only by
+// holders of the key and only for the complete set. The order of the
+// shares and their labels do not matter.
+func AuthTag(key []byte, shares []Share) []byte {
+	sorted := make([]Share, len(shares))
+	copy(sorted, shares)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].ID < sorted[j].ID })
+
+	// Each share is length-prefixed so that values cannot be shifted
+	// between neighbours without changing the tag
+	mac := hmac.New(sha256.New, key)
+	var buf [6]byte
+	for _, share := range sorted {
+		buf[0], buf[1] = share.Version, share.ID
+		binary.BigEndian.PutUint32(buf[2:], uint32(len(share.Value)))
+		mac.Write(buf[:])
+		mac.Write(share.Value)
+	}
+	return mac.Sum(nil)
+}
+
+// VerifyAuthTag reports whether tag is the AuthTag of the shares under key
+func VerifyAuthTag(key, tag []byte, shares []Share) bool {
+	return hmac.Equal(AuthTag(key, shares), tag)
+}
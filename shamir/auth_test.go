@@ -0,0 +1,63 @@
+package shamir
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestAuthTag(t *testing.T) {
+	shares, err := Split([]byte("authenticated set"), 4, 2)
+	if err != nil {
+		t.Fatalf("Split failed: %v", err)
+	}
+	key, err := NewAuthKey()
+	if err != nil {
+		t.Fatalf("NewAuthKey failed: %v", err)
+	}
+	if len(key) != AuthKeySize {
+		t.Fatalf("Key size = %d, want %d", len(key), AuthKeySize)
+	}
+
+	tag := AuthTag(key, shares)
+	if !VerifyAuthTag(key, tag, shares) {
+		t.Error("VerifyAuthTag rejected the untouched set")
+	}
+
+	// Order and labels do not matter
+	reordered := []Share{shares[2], shares[0], shares[3], shares[1]}
+	reordered[0].Label = "carol"
+	if !VerifyAuthTag(key, tag, reordered) {
+		t.Error("VerifyAuthTag rejected the reordered set")
+	}
+
+	tampered := make([]Share, len(shares))
+	copy(tampered, shares)
+	tampered[1].Value = bytes.Clone(shares[1].Value)
+	tampered[1].Value[0] ^= 1
+
+	moved := make([]Share, len(shares))
+	copy(moved, shares)
+	moved[0].Value = append(bytes.Clone(shares[0].Value), shares[1].Value[0])
+	moved[1].Value = shares[1].Value[1:]
+
+	otherKey, _ := NewAuthKey()
+	for name, check := range map[string]bool{
+		"tampered value": VerifyAuthTag(key, tag, tampered),
+		"moved byte":     VerifyAuthTag(key, tag, moved),
+		"missing share":  VerifyAuthTag(key, tag, shares[:3]),
+		"other version":  VerifyAuthTag(key, tag, paddedCopy(shares)),
+		"other key":      VerifyAuthTag(otherKey, tag, shares),
+	} {
+		if check {
+			t.Errorf("VerifyAuthTag accepted the set with a %s", name)
+		}
+	}
+}
+
+// paddedCopy returns a copy of shares marked as padded
+func paddedCopy(shares []Share) []Share {
+	marked := make([]Share, len(shares))
+	copy(marked, shares)
+	MarkPadded(marked)
+	return marked
+}
@@ -0,0 +1,68 @@
+package shamir
+
+import (
+	"encoding/base32"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// base32Encoding is RFC 4648 base32 without padding
+var base32Encoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// ShareToBase32 converts a Share to its base32 representation "ID-VALUE",
+// e.g. "7-32W353Y", where VALUE is the unpadded RFC 4648 base32 of the value.
+// Like the text format, a version above 1 is recorded with a prefix, as in
+// "V3-7-32W353Y". The string only uses uppercase letters, digits and dashes,
+// which fit the alphanumeric mode of QR codes and are easy to read aloud.
+func ShareToBase32(share Share) string {
+	s := fmt.Sprintf("%d-%s", share.ID, base32Encoding.EncodeToString(share.Value))
+	if share.Version > 1 {
+		return fmt.Sprintf("V%d-%s", share.Version, s)
+	}
+	return s
+}
+
+// Base32ToShare converts the base32 representation produced by
+// ShareToBase32 to a Share. Parsing is case-insensitive.
+func Base32ToShare(s string) (Share, error) {
+	s = strings.ToUpper(s)
+
+	var version byte
+	if strings.HasPrefix(s, "V") {
+		token, rest, ok := strings.Cut(s, "-")
+		if !ok {
+			return Share{}, errors.New("invalid base32 part format")
+		}
+		v, err := strconv.ParseUint(token[1:], 10, 8)
+		if err != nil || v == 0 {
+			return Share{}, fmt.Errorf("invalid share version %q", token)
+		}
+		if v > CurrentVersion {
+			return Share{}, fmt.Errorf("unsupported share version %d", v)
+		}
+		if v > 1 {
+			version = byte(v)
+		}
+		s = rest
+	}
+
+	idPart, valuePart, ok := strings.Cut(s, "-")
+	if !ok {
+		return Share{}, errors.New("invalid base32 part format")
+	}
+	id, err := strconv.ParseUint(idPart, 10, 8)
+	if err != nil {
+		return Share{}, fmt.Errorf("invalid part ID %q", idPart)
+	}
+	if valuePart == "" {
+		return Share{}, errors.New("empty base32 value")
+	}
+	value, err := base32Encoding.DecodeString(valuePart)
+	if err != nil {
+		return Share{}, fmt.Errorf("invalid base32 value: %w", err)
+	}
+
+	return Share{Version: version, ID: byte(id), Value: value}, nil
+}
@@ -0,0 +1,69 @@
+package shamir
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestBase32Conversion(t *testing.T) {
+	tests := []struct {
+		share Share
+		want  string
+	}{
+		{Share{ID: 7, Value: []byte{0xde, 0xad, 0xbe, 0xef}}, "7-32W353Y"},
+		{Share{ID: 255, Value: []byte{0x00}}, "255-AA"},
+		{Share{Version: VersionHeader, ID: 12, Value: []byte("hello")}, "V3-12-NBSWY3DP"},
+	}
+
+	for _, tt := range tests {
+		encoded := ShareToBase32(tt.share)
+		if encoded != tt.want {
+			t.Errorf("ShareToBase32(%+v) = %q, want %q", tt.share, encoded, tt.want)
+		}
+
+		for _, input := range []string{encoded, strings.ToLower(encoded)} {
+			parsed, err := Base32ToShare(input)
+			if err != nil {
+				t.Fatalf("Base32ToShare(%q) failed: %v", input, err)
+			}
+			if !parsed.Equal(tt.share) || parsed.Version != tt.share.Version {
+				t.Errorf("Base32ToShare(%q) = %+v, want %+v", input, parsed, tt.share)
+			}
+		}
+	}
+
+	// Version 1 may be given explicitly and is read back as unversioned
+	parsed, err := Base32ToShare("v1-7-32w353y")
+	if err != nil {
+		t.Fatalf("Base32ToShare failed: %v", err)
+	}
+	if parsed.Version != 0 || parsed.ID != 7 {
+		t.Errorf("Base32ToShare(%q) = %+v", "v1-7-32w353y", parsed)
+	}
+
+	// Round trip of real shares through the generic parser
+	shares, err := Split([]byte("read it aloud"), 3, 2)
+	if err != nil {
+		t.Fatalf("Split failed: %v", err)
+	}
+	parsedShares := make([]Share, 2)
+	for i, share := range shares[1:] {
+		if parsedShares[i], err = ParseShare(ShareToBase32(share)); err != nil {
+			t.Fatalf("ParseShare failed: %v", err)
+		}
+	}
+	secret, err := Combine(parsedShares)
+	if err != nil {
+		t.Fatalf("Combine failed: %v", err)
+	}
+	if !bytes.Equal(secret, []byte("read it aloud")) {
+		t.Errorf("Recovery failed: got %q", secret)
+	}
+
+	for _, input := range []string{"", "7", "7-", "-32W353Y", "256-AA", "X-AA", "7-32W353Y=", "7-0189", "V0-7-AA", "V9-7-AA", "V3"} {
+		if _, err := Base32ToShare(input); err == nil {
+			t.Errorf("Base32ToShare(%q) should fail", input)
+		}
+	}
+}
@@ -0,0 +1,57 @@
+package shamir
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+)
+
+// MarshalBinary encodes the share as [ID][2-byte big-endian length][Value],
+// for storage in binary blobs or with encoding/gob. A version above 1 is
+// recorded with the prefix [0][version]; the prefix cannot be mistaken for
+// an ID, since IDs start at 1, and shares with ID 0 are refused. Labels
+// are not encoded. Values longer than 65535 bytes cannot be encoded.
+func (s Share) MarshalBinary() ([]byte, error) {
+	if s.ID == 0 {
+		return nil, errors.New("share ID cannot be 0")
+	}
+	if len(s.Value) > math.MaxUint16 {
+		return nil, fmt.Errorf("part value of %d bytes is too long for the binary format, maximum %d", len(s.Value), math.MaxUint16)
+	}
+	data := make([]byte, 0, 5+len(s.Value))
+	if s.Version > 1 {
+		data = append(data, 0, s.Version)
+	}
+	data = append(data, s.ID)
+	data = binary.BigEndian.AppendUint16(data, uint16(len(s.Value)))
+	return append(data, s.Value...), nil
+}
+
+// UnmarshalBinary decodes a share encoded by MarshalBinary. The value is
+// copied, so data may be reused afterwards.
+func (s *Share) UnmarshalBinary(data []byte) error {
+	var version byte
+	if len(data) > 0 && data[0] == 0 {
+		if len(data) < 2 {
+			return errors.New("binary part is too short")
+		}
+		version = data[1]
+		if version <= 1 || version > CurrentVersion {
+			return fmt.Errorf("unsupported share version %d", version)
+		}
+		data = data[2:]
+	}
+	if len(data) < 3 {
+		return errors.New("binary part is too short")
+	}
+	if data[0] == 0 {
+		return errors.New("invalid part ID 0")
+	}
+	n := int(binary.BigEndian.Uint16(data[1:3]))
+	if len(data)-3 != n {
+		return fmt.Errorf("binary part length %d does not match its %d value bytes", n, len(data)-3)
+	}
+	*s = Share{Version: version, ID: data[0], Value: append([]byte(nil), data[3:]...)}
+	return nil
+}
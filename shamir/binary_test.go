@@ -0,0 +1,83 @@
+package shamir
+
+import (
+	"bytes"
+	"encoding/gob"
+	"testing"
+)
+
+func TestShareBinaryRoundTrip(t *testing.T) {
+	long := bytes.Repeat([]byte{0xa5}, 300)
+	tests := []struct {
+		name  string
+		share Share
+		want  []byte
+	}{
+		{"short", Share{ID: 1, Value: []byte{0xab, 0xcd}}, []byte{1, 0, 2, 0xab, 0xcd}},
+		{"ID 255", Share{ID: 255, Value: []byte{0x01}}, []byte{255, 0, 1, 0x01}},
+		{"multi-byte length", Share{ID: 7, Value: long}, append([]byte{7, 0x01, 0x2c}, long...)},
+		{"versioned", Share{Version: VersionCRC32, ID: 3, Value: []byte{0xff}}, []byte{0, VersionCRC32, 3, 0, 1, 0xff}},
+		{"empty value", Share{ID: 9}, []byte{9, 0, 0}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := tt.share.MarshalBinary()
+			if err != nil {
+				t.Fatalf("MarshalBinary failed: %v", err)
+			}
+			if !bytes.Equal(data, tt.want) {
+				t.Errorf("MarshalBinary = %x, want %x", data, tt.want)
+			}
+			var got Share
+			if err := got.UnmarshalBinary(data); err != nil {
+				t.Fatalf("UnmarshalBinary failed: %v", err)
+			}
+			if !got.Equal(tt.share) {
+				t.Errorf("UnmarshalBinary = %+v, want %+v", got, tt.share)
+			}
+		})
+	}
+}
+
+func TestShareBinaryGob(t *testing.T) {
+	shares, err := SplitPadded([]byte("gob secret"), 3, 2, 16)
+	if err != nil {
+		t.Fatalf("SplitPadded failed: %v", err)
+	}
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(shares); err != nil {
+		t.Fatalf("Encode failed: %v", err)
+	}
+	var decoded []Share
+	if err := gob.NewDecoder(&buf).Decode(&decoded); err != nil {
+		t.Fatalf("Decode failed: %v", err)
+	}
+	secret, err := Combine(decoded[1:])
+	if err != nil || string(secret) != "gob secret" {
+		t.Errorf("Combine = %q, %v", secret, err)
+	}
+}
+
+func TestShareBinaryErrors(t *testing.T) {
+	if _, err := (Share{ID: 1, Value: make([]byte, 65536)}).MarshalBinary(); err == nil {
+		t.Error("MarshalBinary accepted a value of 65536 bytes")
+	}
+	if _, err := (Share{ID: 0, Value: []byte{1}}).MarshalBinary(); err == nil {
+		t.Error("MarshalBinary accepted share ID 0")
+	}
+	for _, data := range [][]byte{
+		nil,
+		{1, 0},
+		{0},
+		{0, 1, 1, 0, 0},
+		{0, CurrentVersion + 1, 1, 0, 0},
+		{0, 0, 0},
+		{1, 0, 2, 0xab},
+		{1, 0, 1, 0xab, 0xcd},
+	} {
+		var s Share
+		if err := s.UnmarshalBinary(data); err == nil {
+			t.Errorf("UnmarshalBinary(%x) succeeded: %+v", data, s)
+		}
+	}
+}
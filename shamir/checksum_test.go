@@ -0,0 +1,122 @@
+package shamir
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+// sumChecksum is a 2-byte checksum standing in for a caller's own
+// implementation
+type sumChecksum struct{}
+
+func (sumChecksum) Size() int { return 2 }
+
+func (sumChecksum) Tag(data []byte) []byte {
+	var sum uint16
+	for _, b := range data {
+		sum += uint16(b)
+	}
+	return []byte{byte(sum >> 8), byte(sum)}
+}
+
+func (c sumChecksum) Verify(data, tag []byte) bool {
+	return bytes.Equal(c.Tag(data), tag)
+}
+
+func TestChecksumTags(t *testing.T) {
+	data := []byte("123456789")
+
+	if got := (XORChecksum{}).Tag(data); !bytes.Equal(got, []byte{calculateChecksum(data)}) {
+		t.Errorf("XORChecksum.Tag = %x", got)
+	}
+	// The standard CRC-32 check value
+	if got := (CRC32Checksum{}).Tag(data); !bytes.Equal(got, []byte{0xcb, 0xf4, 0x39, 0x26}) {
+		t.Errorf("CRC32Checksum.Tag = %x, want cbf43926", got)
+	}
+
+	for _, c := range []Checksum{XORChecksum{}, CRC32Checksum{}, sumChecksum{}} {
+		tag := c.Tag(data)
+		if len(tag) != c.Size() || !c.Verify(data, tag) {
+			t.Errorf("%T: tag %x does not verify", c, tag)
+		}
+		if c.Verify(data, tag[:len(tag)-1]) {
+			t.Errorf("%T: truncated tag verifies", c)
+		}
+		tag[0] ^= 1
+		if c.Verify(data, tag) {
+			t.Errorf("%T: altered tag verifies", c)
+		}
+	}
+}
+
+func TestSplitWithChecksum(t *testing.T) {
+	secret := []byte("checksummed secret")
+
+	tests := []struct {
+		name     string
+		checksum Checksum
+		version  byte
+	}{
+		{"default", nil, 0},
+		{"xor", XORChecksum{}, 0},
+		{"crc32", CRC32Checksum{}, VersionCRC32},
+		{"custom", sumChecksum{}, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			shares, err := SplitWithChecksum(secret, 5, 3, tt.checksum)
+			if err != nil {
+				t.Fatalf("SplitWithChecksum failed: %v", err)
+			}
+			size := 1
+			if tt.checksum != nil {
+				size = tt.checksum.Size()
+			}
+			if shares[0].Version != tt.version || len(shares[0].Value) != len(secret)+size {
+				t.Errorf("share version %d, length %d", shares[0].Version, len(shares[0].Value))
+			}
+
+			recovered, err := CombineWithChecksum(shares[:3], tt.checksum)
+			if err != nil {
+				t.Fatalf("CombineWithChecksum failed: %v", err)
+			}
+			if !bytes.Equal(recovered, secret) {
+				t.Errorf("recovered %q, want %q", recovered, secret)
+			}
+		})
+	}
+}
+
+func TestCombineDetectsCRC32(t *testing.T) {
+	secret := []byte("detected from the version")
+	shares, err := SplitWithChecksum(secret, 3, 2, CRC32Checksum{})
+	if err != nil {
+		t.Fatalf("SplitWithChecksum failed: %v", err)
+	}
+
+	// The version survives the text form, so Combine picks the checksum
+	parsed, err := StringToShare(ShareToString(shares[2]))
+	if err != nil {
+		t.Fatalf("StringToShare failed: %v", err)
+	}
+	recovered, err := Combine([]Share{shares[0], parsed})
+	if err != nil {
+		t.Fatalf("Combine failed: %v", err)
+	}
+	if !bytes.Equal(recovered, secret) {
+		t.Errorf("recovered %q, want %q", recovered, secret)
+	}
+
+	// A corrupted byte is caught by the CRC
+	shares[0].Value[3] ^= 0x40
+	if _, err := Combine(shares[:2]); !errors.Is(err, ErrChecksumMismatch) {
+		t.Errorf("Combine error = %v, want %v", err, ErrChecksumMismatch)
+	}
+
+	// Verifying with the wrong checksum fails
+	if _, err := CombineWithChecksum(shares[1:], XORChecksum{}); err == nil {
+		t.Error("CombineWithChecksum with XOR accepted CRC-32 shares")
+	}
+}
@@ -0,0 +1,144 @@
+package shamir
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Chunk is one piece of a share that was cut into sequenced pieces to keep
+// each piece below a size limit, e.g. for QR codes. Share holds the version
+// and ID of the whole share and a consecutive part of its value.
+//
+// Shamir's scheme works on every byte of the secret independently, so a
+// piece of each share is a share of the same piece of the secret. The
+// checksum is only in the last piece and is verified once the shares have
+// been joined again.
+type Chunk struct {
+	Seq   int
+	Total int
+	Share Share
+}
+
+// ChunkShare cuts the value of a share into chunks of at most size bytes.
+// A share that fits is returned as a single chunk 1 of 1.
+func ChunkShare(share Share, size int) ([]Chunk, error) {
+	if size < 1 {
+		return nil, fmt.Errorf("invalid chunk size %d", size)
+	}
+
+	total := (len(share.Value) + size - 1) / size
+	chunks := make([]Chunk, 0, total)
+	for seq := 1; seq <= total; seq++ {
+		start := (seq - 1) * size
+		end := min(start+size, len(share.Value))
+		piece := Share{Version: share.Version, ID: share.ID, Value: share.Value[start:end]}
+		chunks = append(chunks, Chunk{Seq: seq, Total: total, Share: piece})
+	}
+	return chunks, nil
+}
+
+// JoinChunks reassembles the shares from their chunks, which may be given
+// in any order. Every share must be complete; chunks given more than once
+// must be identical. Shares are returned in the order their first chunk
+// appears.
+func JoinChunks(chunks []Chunk) ([]Share, error) {
+	// Pieces are collected by sequence number and only checked against the
+	// total once all chunks are read, so memory never depends on a total
+	// taken from the input
+	type pieces struct {
+		total int
+		seqs  map[int]Share
+	}
+	var ids []byte
+	byID := make(map[byte]*pieces)
+	for _, c := range chunks {
+		if c.Total < 1 || c.Seq < 1 || c.Seq > c.Total {
+			return nil, fmt.Errorf("invalid chunk %d of %d", c.Seq, c.Total)
+		}
+
+		id := c.Share.ID
+		p, ok := byID[id]
+		if !ok {
+			p = &pieces{total: c.Total, seqs: make(map[int]Share)}
+			byID[id] = p
+			ids = append(ids, id)
+		}
+		if p.total != c.Total {
+			return nil, fmt.Errorf("share %d has chunks of %d and %d pieces", id, p.total, c.Total)
+		}
+
+		if prev, ok := p.seqs[c.Seq]; ok {
+			if !prev.Equal(c.Share) {
+				return nil, fmt.Errorf("share %d has conflicting copies of chunk %d", id, c.Seq)
+			}
+			continue
+		}
+		p.seqs[c.Seq] = c.Share
+	}
+
+	shares := make([]Share, 0, len(ids))
+	for _, id := range ids {
+		p := byID[id]
+		// A missing chunk is among the first len(seqs)+1 sequence numbers
+		if len(p.seqs) < p.total {
+			for seq := 1; ; seq++ {
+				if _, ok := p.seqs[seq]; !ok {
+					return nil, fmt.Errorf("share %d is missing chunk %d of %d", id, seq, p.total)
+				}
+			}
+		}
+
+		var value bytes.Buffer
+		first := p.seqs[1]
+		for seq := 1; seq <= p.total; seq++ {
+			piece := p.seqs[seq]
+			if piece.Version != first.Version {
+				return nil, fmt.Errorf("share %d has chunks of different versions", id)
+			}
+			value.Write(piece.Value)
+		}
+		shares = append(shares, Share{Version: first.Version, ID: id, Value: value.Bytes()})
+	}
+	return shares, nil
+}
+
+// ChunkToString converts a Chunk to its string representation: the share
+// string of the piece prefixed with "c<seq>/<total>:", e.g. "c1/2:3:a1b2"
+// for the first of two chunks of share 3
+func ChunkToString(c Chunk) string {
+	return fmt.Sprintf("c%d/%d:%s", c.Seq, c.Total, ShareToString(c.Share))
+}
+
+// IsChunk reports whether s looks like a chunk written by ChunkToString
+// rather than a share. Share strings start with a digit or a version, and
+// compact or base64 shares have no colon.
+func IsChunk(s string) bool {
+	return strings.HasPrefix(s, "c") && strings.Contains(s, ":")
+}
+
+// StringToChunk converts the string representation produced by
+// ChunkToString to a Chunk
+func StringToChunk(s string) (Chunk, error) {
+	token, rest, ok := strings.Cut(s, ":")
+	if !ok || !strings.HasPrefix(token, "c") {
+		return Chunk{}, errors.New("invalid chunk format")
+	}
+	seqStr, totalStr, ok := strings.Cut(token[1:], "/")
+	if !ok {
+		return Chunk{}, errors.New("invalid chunk format")
+	}
+	seq, err1 := strconv.Atoi(seqStr)
+	total, err2 := strconv.Atoi(totalStr)
+	if err1 != nil || err2 != nil || total < 1 || seq < 1 || seq > total {
+		return Chunk{}, fmt.Errorf("invalid chunk sequence %q", token)
+	}
+
+	share, err := StringToShare(rest)
+	if err != nil {
+		return Chunk{}, err
+	}
+	return Chunk{Seq: seq, Total: total, Share: share}, nil
+}
@@ -0,0 +1,114 @@
+package shamir
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestChunkRoundTrip(t *testing.T) {
+	secret := []byte("a secret too long for one chunk")
+	shares, err := Split(secret, 5, 3)
+	if err != nil {
+		t.Fatalf("Split failed: %v", err)
+	}
+
+	// Holders 5, 2 and 4 hand in their chunks as strings, in any order
+	var chunks []Chunk
+	for _, share := range []Share{shares[4], shares[1], shares[3]} {
+		pieces, err := ChunkShare(share, 10)
+		if err != nil {
+			t.Fatalf("ChunkShare failed: %v", err)
+		}
+		if len(pieces) != 4 {
+			t.Fatalf("got %d chunks of a %d-byte share, want 4", len(pieces), len(share.Value))
+		}
+		for i := len(pieces) - 1; i >= 0; i-- {
+			c, err := StringToChunk(ChunkToString(pieces[i]))
+			if err != nil {
+				t.Fatalf("StringToChunk failed: %v", err)
+			}
+			chunks = append(chunks, c)
+		}
+	}
+	// A repeated chunk is ignored
+	chunks = append(chunks, chunks[0])
+
+	joined, err := JoinChunks(chunks)
+	if err != nil {
+		t.Fatalf("JoinChunks failed: %v", err)
+	}
+	if len(joined) != 3 || !joined[0].Equal(shares[4]) || !joined[1].Equal(shares[1]) {
+		t.Fatalf("JoinChunks = %+v", joined)
+	}
+
+	recovered, err := Combine(joined)
+	if err != nil {
+		t.Fatalf("Combine failed: %v", err)
+	}
+	if !bytes.Equal(recovered, secret) {
+		t.Errorf("recovered %q, want %q", recovered, secret)
+	}
+}
+
+func TestChunkToString(t *testing.T) {
+	c := Chunk{Seq: 1, Total: 2, Share: Share{ID: 3, Value: []byte{0xa1, 0xb2}}}
+	if got := ChunkToString(c); got != "c1/2:3:a1b2" {
+		t.Errorf("ChunkToString = %q, want %q", got, "c1/2:3:a1b2")
+	}
+
+	c.Share.Version = VersionPadded
+	if got := ChunkToString(c); got != "c1/2:v2:3:a1b2" {
+		t.Errorf("ChunkToString = %q, want %q", got, "c1/2:v2:3:a1b2")
+	}
+
+	for _, s := range []string{"c1/2:3:a1b2", "c2/2:v2:3:ff"} {
+		if !IsChunk(s) {
+			t.Errorf("IsChunk(%q) = false", s)
+		}
+	}
+	for _, s := range []string{"3:a1b2", "v2:3:a1b2", "c1a2b3", "cGFydA=="} {
+		if IsChunk(s) {
+			t.Errorf("IsChunk(%q) = true", s)
+		}
+	}
+}
+
+func TestChunkErrors(t *testing.T) {
+	for _, s := range []string{"c1:3:ab", "c0/2:3:ab", "c3/2:3:ab", "cx/2:3:ab", "c1/2:3:zz", "1/2:3:ab"} {
+		if _, err := StringToChunk(s); err == nil {
+			t.Errorf("StringToChunk(%q) succeeded", s)
+		}
+	}
+
+	chunks, err := ParseShares([]string{"c1/1000000000000:1:ab", "c1/1000000000000:2:cd"})
+	if err == nil || !strings.Contains(err.Error(), "missing chunk 2 of 1000000000000") {
+		t.Errorf("ParseShares of chunks with a huge total = %v, %v", chunks, err)
+	}
+
+	if _, err := ChunkShare(Share{ID: 1, Value: []byte{1}}, 0); err == nil {
+		t.Error("ChunkShare accepted size 0")
+	}
+
+	piece := func(seq, total int, id byte, value ...byte) Chunk {
+		return Chunk{Seq: seq, Total: total, Share: Share{ID: id, Value: value}}
+	}
+	tests := []struct {
+		name   string
+		chunks []Chunk
+	}{
+		{"missing chunk", []Chunk{piece(1, 2, 1, 0xaa)}},
+		{"different totals", []Chunk{piece(1, 2, 1, 0xaa), piece(2, 3, 1, 0xbb)}},
+		{"conflicting copies", []Chunk{piece(1, 1, 1, 0xaa), piece(1, 1, 1, 0xbb)}},
+		{"invalid sequence", []Chunk{piece(2, 1, 1, 0xaa)}},
+		// The total is taken from the input; it must not size any allocation
+		{"huge total", []Chunk{piece(1, 1_000_000_000_000, 1, 0xaa), piece(1, 1_000_000_000_000, 2, 0xbb)}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := JoinChunks(tt.chunks); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
@@ -0,0 +1,50 @@
+package shamir
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Combiner collects shares one at a time, e.g. as they arrive from
+// different holders, and recovers the secret once enough are present.
+// The zero value is ready to use.
+type Combiner struct {
+	shares []Share
+	seen   [256]bool
+}
+
+// Add adds a share to the set. It rejects shares with an empty value, the
+// ID 0 or an ID that was already added, or a length or version different
+// from earlier shares.
+func (c *Combiner) Add(share Share) error {
+	if len(share.Value) == 0 {
+		return errors.New("share has empty value")
+	}
+	if share.ID == 0 {
+		return errors.New("share ID cannot be 0")
+	}
+	if c.seen[share.ID] {
+		return fmt.Errorf("duplicate share ID %d", share.ID)
+	}
+	if len(c.shares) > 0 && len(share.Value) != len(c.shares[0].Value) {
+		return errors.New("all parts must have the same length")
+	}
+	if len(c.shares) > 0 && share.Version != c.shares[0].Version {
+		return errors.New("all parts must have the same version")
+	}
+
+	c.seen[share.ID] = true
+	c.shares = append(c.shares, share)
+	return nil
+}
+
+// Len returns the number of shares added so far
+func (c *Combiner) Len() int {
+	return len(c.shares)
+}
+
+// Done recovers the secret from the shares added so far. It fails like
+// Combine when there are too few shares or the checksum does not match.
+func (c *Combiner) Done() ([]byte, error) {
+	return Combine(c.shares)
+}
@@ -0,0 +1,93 @@
+package shamir
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestCombiner(t *testing.T) {
+	secret := []byte("collected one by one")
+	shares, err := Split(secret, 5, 3)
+	if err != nil {
+		t.Fatalf("Split failed: %v", err)
+	}
+
+	var c Combiner
+	if _, err := c.Done(); err == nil {
+		t.Error("Expected error with no shares added")
+	}
+
+	for i, share := range []Share{shares[3], shares[0], shares[4]} {
+		if err := c.Add(share); err != nil {
+			t.Fatalf("Add of share %d failed: %v", share.ID, err)
+		}
+		if c.Len() != i+1 {
+			t.Errorf("Expected Len %d, got %d", i+1, c.Len())
+		}
+	}
+
+	recovered, err := c.Done()
+	if err != nil {
+		t.Fatalf("Done failed: %v", err)
+	}
+	if !bytes.Equal(recovered, secret) {
+		t.Errorf("Expected %q, got %q", secret, recovered)
+	}
+}
+
+func TestCombinerBelowThreshold(t *testing.T) {
+	shares, err := Split([]byte("secret"), 5, 3)
+	if err != nil {
+		t.Fatalf("Split failed: %v", err)
+	}
+
+	var c Combiner
+	for _, share := range shares[:2] {
+		if err := c.Add(share); err != nil {
+			t.Fatalf("Add failed: %v", err)
+		}
+	}
+	if _, err := c.Done(); err == nil {
+		t.Error("Expected error with fewer shares than the threshold")
+	}
+
+	// Adding the missing share makes recovery succeed
+	if err := c.Add(shares[2]); err != nil {
+		t.Fatalf("Add failed: %v", err)
+	}
+	if _, err := c.Done(); err != nil {
+		t.Errorf("Done failed: %v", err)
+	}
+}
+
+func TestCombinerAddValidation(t *testing.T) {
+	var c Combiner
+	if err := c.Add(Share{ID: 1, Value: []byte{1, 2, 3}}); err != nil {
+		t.Fatalf("Add failed: %v", err)
+	}
+
+	tests := []struct {
+		name  string
+		share Share
+		err   string
+	}{
+		{"duplicate ID", Share{ID: 1, Value: []byte{4, 5, 6}}, "duplicate share ID 1"},
+		{"zero ID", Share{ID: 0, Value: []byte{4, 5, 6}}, "share ID cannot be 0"},
+		{"length mismatch", Share{ID: 2, Value: []byte{4, 5}}, "all parts must have the same length"},
+		{"empty value", Share{ID: 3}, "share has empty value"},
+		{"version mismatch", Share{Version: VersionPadded, ID: 4, Value: []byte{4, 5, 6}}, "all parts must have the same version"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := c.Add(tt.share)
+			if err == nil || err.Error() != tt.err {
+				t.Errorf("Expected error %q, got %v", tt.err, err)
+			}
+		})
+	}
+
+	if c.Len() != 1 {
+		t.Errorf("Rejected shares must not be added, Len is %d", c.Len())
+	}
+}
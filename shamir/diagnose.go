@@ -0,0 +1,151 @@
+package shamir
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"sort"
+)
+
+// ShareDiagnosis tells how one share fared in the subsets tried by Diagnose
+type ShareDiagnosis struct {
+	ID byte
+	// Subsets is the number of tried subsets containing the share
+	Subsets int
+	// Consistent is the number of those subsets that recovered the secret
+	// most subsets agree on
+	Consistent int
+}
+
+// Diagnosis is the result of Diagnose
+type Diagnosis struct {
+	// Tried is the number of k-subsets combined
+	Tried int
+	// Consistent is the number of subsets that recovered the secret most
+	// subsets agree on; 0 when no subset passed checksum verification
+	Consistent int
+	// Shares holds one entry per share, the most suspect first: ordered by
+	// the fraction of their subsets that were consistent, then by ID
+	Shares []ShareDiagnosis
+}
+
+// Suspects returns the IDs of the shares that were never part of a
+// consistent subset although other subsets were. Such shares are most
+// likely corrupt, since every subset of k intact shares recovers the
+// secret.
+func (d Diagnosis) Suspects() []byte {
+	if d.Consistent == 0 {
+		return nil
+	}
+	var ids []byte
+	for _, s := range d.Shares {
+		if s.Subsets > 0 && s.Consistent == 0 {
+			ids = append(ids, s.ID)
+		}
+	}
+	return ids
+}
+
+// Diagnose finds the likely corrupt shares among more than k shares by
+// combining their subsets of exactly k shares, at most MaxCombineSubsets of
+// them. Subsets that pass checksum verification vote for the secret they
+// recover; the secret with the most votes is taken as the right one, so a
+// corrupt subset passing the one-byte checksum by chance does not count.
+// The secret itself is not returned.
+func Diagnose(shares []Share, k int) (Diagnosis, error) {
+	if k < 2 {
+		return Diagnosis{}, ErrThresholdTooSmall
+	}
+	if len(shares) <= k {
+		return Diagnosis{}, fmt.Errorf("%d parts given for a threshold of %d; at least %d are needed to tell corrupt parts apart", len(shares), k, k+1)
+	}
+	var seen [256]bool
+	for _, share := range shares {
+		if seen[share.ID] {
+			return Diagnosis{}, fmt.Errorf("duplicate share ID %d", share.ID)
+		}
+		seen[share.ID] = true
+	}
+
+	subsets := MinimalSubsets(shares, k, MaxCombineSubsets)
+	// Subsets vote with a digest of the secret they recover, so the votes
+	// hold no plaintext; each recovered secret is zeroed once hashed
+	recovered := make([][sha256.Size]byte, len(subsets))
+	passed := make([]bool, len(subsets))
+	votes := make(map[[sha256.Size]byte]int)
+	var majority [sha256.Size]byte
+	majorityVotes := 0
+	for i, subset := range subsets {
+		secret, err := Combine(subset)
+		if err != nil {
+			continue
+		}
+		recovered[i], passed[i] = sha256.Sum256(secret), true
+		clear(secret)
+		votes[recovered[i]]++
+		if votes[recovered[i]] > majorityVotes {
+			majority, majorityVotes = recovered[i], votes[recovered[i]]
+		}
+	}
+
+	d := Diagnosis{Tried: len(subsets), Consistent: majorityVotes}
+	byID := make(map[byte]*ShareDiagnosis, len(shares))
+	d.Shares = make([]ShareDiagnosis, len(shares))
+	for i, share := range shares {
+		d.Shares[i].ID = share.ID
+		byID[share.ID] = &d.Shares[i]
+	}
+	for i, subset := range subsets {
+		consistent := passed[i] && recovered[i] == majority
+		for _, share := range subset {
+			byID[share.ID].Subsets++
+			if consistent {
+				byID[share.ID].Consistent++
+			}
+		}
+	}
+
+	// Compare Consistent/Subsets without division. Shares in no tried
+	// subset, when the subsets were capped, tell nothing and go last.
+	sort.Slice(d.Shares, func(i, j int) bool {
+		a, b := d.Shares[i], d.Shares[j]
+		if (a.Subsets == 0) != (b.Subsets == 0) {
+			return b.Subsets == 0
+		}
+		if ra, rb := a.Consistent*b.Subsets, b.Consistent*a.Subsets; ra != rb {
+			return ra < rb
+		}
+		return a.ID < b.ID
+	})
+	return d, nil
+}
+
+// InferThreshold returns the threshold of a set of distinct shares that
+// combine to a secret: the smallest number of them, taken in the given
+// order, that recover the same secret as all of them. Fewer shares than the
+// threshold interpolate an unrelated value, so the result is exact, except
+// that it equals len(shares) when no more shares than the threshold are
+// given. It returns the error of Combine when the set does not recover a
+// secret.
+func InferThreshold(shares []Share) (int, error) {
+	if len(shares) < 2 {
+		return 0, ErrTooFewShares
+	}
+	var seen [256]bool
+	for _, share := range shares {
+		if seen[share.ID] {
+			return 0, fmt.Errorf("duplicate share ID %d", share.ID)
+		}
+		seen[share.ID] = true
+	}
+
+	secret, err := Combine(shares)
+	if err != nil {
+		return 0, err
+	}
+	for k := 2; k < len(shares); k++ {
+		if recovered, err := Combine(shares[:k]); err == nil && SecretsEqual(recovered, secret) {
+			return k, nil
+		}
+	}
+	return len(shares), nil
+}
@@ -0,0 +1,70 @@
+package shamir
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestDiagnose(t *testing.T) {
+	shares, err := Split([]byte("find the bad one"), 6, 3)
+	if err != nil {
+		t.Fatalf("Split failed: %v", err)
+	}
+	shares[3].Value = bytes.Clone(shares[3].Value)
+	shares[3].Value[2] ^= 0x40
+
+	d, err := Diagnose(shares, 3)
+	if err != nil {
+		t.Fatalf("Diagnose failed: %v", err)
+	}
+	// C(6,3) subsets, of which the C(5,3) without part 4 are consistent
+	if d.Tried != 20 || d.Consistent != 10 {
+		t.Errorf("Tried %d and consistent %d subsets, want 20 and 10", d.Tried, d.Consistent)
+	}
+	if suspects := d.Suspects(); !bytes.Equal(suspects, []byte{4}) {
+		t.Errorf("Suspects = %v, want [4]", suspects)
+	}
+	if first := d.Shares[0]; first.ID != 4 || first.Subsets != 10 || first.Consistent != 0 {
+		t.Errorf("Most suspect share = %+v, want ID 4 in 10 subsets, none consistent", first)
+	}
+	for _, s := range d.Shares[1:] {
+		if s.Consistent != 6 || s.Subsets != 10 {
+			t.Errorf("Intact share %d: %d of %d consistent subsets, want 6 of 10", s.ID, s.Consistent, s.Subsets)
+		}
+	}
+
+	// Intact shares have no suspects
+	shares, _ = Split([]byte("all good"), 4, 2)
+	if d, err := Diagnose(shares, 2); err != nil || len(d.Suspects()) != 0 || d.Consistent != d.Tried {
+		t.Errorf("Diagnose of intact shares = %+v, %v", d, err)
+	}
+
+	if _, err := Diagnose(shares[:2], 2); err == nil {
+		t.Error("Diagnose should need more than k shares")
+	}
+	if _, err := Diagnose(shares, 1); !errors.Is(err, ErrThresholdTooSmall) {
+		t.Errorf("Diagnose with k=1 returned %v, want %v", err, ErrThresholdTooSmall)
+	}
+}
+
+func TestInferThreshold(t *testing.T) {
+	// The CRC-32 keeps too few shares from passing the checksum by chance
+	shares, err := SplitWithChecksum([]byte("infer me"), 6, 3, CRC32Checksum{})
+	if err != nil {
+		t.Fatalf("SplitWithChecksum failed: %v", err)
+	}
+	for _, n := range []int{3, 4, 6} {
+		k, err := InferThreshold(shares[:n])
+		if err != nil || k != 3 {
+			t.Errorf("InferThreshold of %d shares = %d, %v, want 3", n, k, err)
+		}
+	}
+
+	if _, err := InferThreshold(shares[:2]); err == nil {
+		t.Error("InferThreshold succeeded with fewer shares than the threshold")
+	}
+	if _, err := InferThreshold([]Share{shares[0], shares[1], shares[0]}); err == nil {
+		t.Error("InferThreshold accepted a duplicate ID")
+	}
+}
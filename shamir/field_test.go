@@ -0,0 +1,76 @@
+package shamir
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestNewFieldAlternatePolynomial(t *testing.T) {
+	f, err := NewField(0x1D) // x^8 + x^4 + x^3 + x^2 + 1
+	if err != nil {
+		t.Fatalf("NewField(0x1D) failed: %v", err)
+	}
+	if f.Poly() != 0x1D {
+		t.Errorf("Poly() = 0x%02x, want 0x1d", f.Poly())
+	}
+
+	for a := 1; a < 256; a++ {
+		inv := f.inv[a]
+		if product := f.mul[a][inv]; product != 1 {
+			t.Fatalf("a * inv(a) != 1: %d * %d = %d", a, inv, product)
+		}
+	}
+
+	// Multiplication is commutative and distributes over addition
+	for a := 0; a < 256; a += 7 {
+		for b := 0; b < 256; b += 5 {
+			if f.mul[a][b] != f.mul[b][a] {
+				t.Fatalf("%d * %d is not commutative", a, b)
+			}
+			for c := 0; c < 256; c += 11 {
+				left := f.mul[a][b^c]
+				right := f.mul[a][b] ^ f.mul[a][c]
+				if left != right {
+					t.Fatalf("%d * (%d + %d) = %d, want %d", a, b, c, left, right)
+				}
+			}
+		}
+	}
+
+	// The fields differ, e.g. x^8 reduces to the polynomial itself
+	if f.mul[0x80][2] != 0x1D || defaultField.mul[0x80][2] != 0x1B {
+		t.Errorf("x^8 = 0x%02x in 0x1d field, 0x%02x in default field", f.mul[0x80][2], defaultField.mul[0x80][2])
+	}
+}
+
+func TestNewFieldReducible(t *testing.T) {
+	// x^8 + 1 = (x + 1)^8 is reducible
+	if _, err := NewField(0x01); err == nil {
+		t.Error("Expected error for a reducible polynomial")
+	}
+}
+
+func TestFieldSplitCombine(t *testing.T) {
+	f, err := NewField(0x1D)
+	if err != nil {
+		t.Fatalf("NewField failed: %v", err)
+	}
+
+	secret := []byte("interop with another field")
+	shares, err := f.Split(secret, 5, 3)
+	if err != nil {
+		t.Fatalf("Split failed: %v", err)
+	}
+
+	recovered, err := f.Combine([]Share{shares[0], shares[2], shares[4]})
+	if err != nil {
+		t.Fatalf("Combine failed: %v", err)
+	}
+	if !bytes.Equal(recovered, secret) {
+		t.Errorf("Recovered %q, want %q", recovered, secret)
+	}
+
+	if _, err := f.Split(secret, 2, 3); err != ErrThresholdTooLarge {
+		t.Errorf("Expected ErrThresholdTooLarge, got %v", err)
+	}
+}
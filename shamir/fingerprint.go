@@ -0,0 +1,20 @@
+package shamir
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+)
+
+// FingerprintDigits is the number of hex digits returned by ShareFingerprint
+const FingerprintDigits = 8
+
+// ShareFingerprint returns a short, non-secret hash of a share: the first
+// FingerprintDigits hex digits of the SHA-256 of ID||Value. Holders can read
+// it out, e.g. over the phone, to confirm they hold an intact copy of the
+// right share without exposing it. The version and label play no part.
+func ShareFingerprint(share Share) string {
+	h := sha256.New()
+	h.Write([]byte{share.ID})
+	h.Write(share.Value)
+	return hex.EncodeToString(h.Sum(nil))[:FingerprintDigits]
+}
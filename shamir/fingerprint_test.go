@@ -0,0 +1,40 @@
+package shamir
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+)
+
+func TestShareFingerprint(t *testing.T) {
+	share := Share{ID: 3, Value: []byte{0x12, 0x34, 0xab}}
+	got := ShareFingerprint(share)
+	sum := sha256.Sum256([]byte{3, 0x12, 0x34, 0xab})
+	if want := hex.EncodeToString(sum[:4]); got != want {
+		t.Fatalf("ShareFingerprint() = %q, want %q", got, want)
+	}
+
+	// Identical shares match regardless of version and label
+	same := []Share{
+		{ID: 3, Value: []byte{0x12, 0x34, 0xab}},
+		{Version: VersionPadded, ID: 3, Value: []byte{0x12, 0x34, 0xab}},
+		{ID: 3, Value: []byte{0x12, 0x34, 0xab}, Label: "alice"},
+	}
+	for _, s := range same {
+		if fp := ShareFingerprint(s); fp != got {
+			t.Errorf("ShareFingerprint(%+v) = %q, want %q", s, fp, got)
+		}
+	}
+
+	differ := []Share{
+		{ID: 4, Value: []byte{0x12, 0x34, 0xab}},
+		{ID: 3, Value: []byte{0x12, 0x34, 0xac}},
+		{ID: 3, Value: []byte{0x12, 0x34}},
+		{ID: 3, Value: []byte{0x12, 0x34, 0xab, 0}},
+	}
+	for _, s := range differ {
+		if fp := ShareFingerprint(s); fp == got {
+			t.Errorf("ShareFingerprint(%+v) = %q, same as for %+v", s, fp, share)
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package shamir
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+// VersionFramed is the share format version of shares split with
+// SplitFramed, whose secret is prefixed with its length. Combine returns
+// exactly that many bytes, so the length no longer depends on where the
+// checksum ends.
+const VersionFramed = 5
+
+// Layout of the data split by SplitFramed, before the checksum is
+// appended:
+//
+//	offset    size  field
+//	0         4     secret length L, big-endian
+//	4         L     secret
+//	4+L       P     zero bytes, P >= 0
+//
+// The checksum protects the whole frame, including the length and the
+// padding.
+const frameLengthSize = 4
+
+// ErrInvalidFrame is returned when data recovered from VersionFramed shares
+// is not a valid frame
+var ErrInvalidFrame = errors.New("invalid length frame")
+
+// SplitFramed is like Split but prefixes the secret with its length and
+// appends zero bytes until the secret and padding fill at least size
+// bytes, so the shares only reveal that the secret is at most that long.
+// A size of at most len(secret) adds no padding. The shares have version
+// VersionFramed.
+func SplitFramed(secret []byte, n, k, size int) ([]Share, error) {
+	if err := ValidateSplitParams(len(secret), n, k); err != nil {
+		return nil, err
+	}
+	if uint64(len(secret)) > 1<<32-1 {
+		return nil, errors.New("secret is too long for a length frame")
+	}
+
+	frame := make([]byte, frameLengthSize+max(len(secret), size))
+	binary.BigEndian.PutUint32(frame, uint32(len(secret)))
+	copy(frame[frameLengthSize:], secret)
+
+	shares, err := Split(frame, n, k)
+	if err != nil {
+		return nil, err
+	}
+	for i := range shares {
+		shares[i].Version = VersionFramed
+	}
+	return shares, nil
+}
+
+// parseFrame returns the secret of a frame built by SplitFramed
+func parseFrame(data []byte) ([]byte, error) {
+	if len(data) < frameLengthSize {
+		return nil, fmt.Errorf("%w: %d bytes, too short for the length", ErrInvalidFrame, len(data))
+	}
+	length := uint64(binary.BigEndian.Uint32(data))
+	rest := data[frameLengthSize:]
+	if length > uint64(len(rest)) {
+		return nil, fmt.Errorf("%w: secret length %d, only %d bytes recovered", ErrInvalidFrame, length, len(rest))
+	}
+	for _, b := range rest[length:] {
+		if b != 0 {
+			return nil, fmt.Errorf("%w: nonzero padding", ErrInvalidFrame)
+		}
+	}
+	return rest[:length], nil
+}
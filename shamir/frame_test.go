@@ -0,0 +1,101 @@
+package shamir
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestSplitFramedRoundTrip(t *testing.T) {
+	// Secrets whose length could not be told from the data alone: trailing
+	// zeros look like the frame padding, trailing ones like Pad padding
+	secrets := [][]byte{
+		{},
+		{0},
+		[]byte("abc\x00\x00"),
+		[]byte("abc\x01"),
+		bytes.Repeat([]byte{0}, 16),
+		bytes.Repeat([]byte{0x10}, 16),
+	}
+	for _, secret := range secrets {
+		for _, size := range []int{0, 16, 32} {
+			shares, err := SplitFramed(secret, 5, 3, size)
+			if err != nil {
+				t.Fatalf("SplitFramed(%q, size %d) failed: %v", secret, size, err)
+			}
+			if want := frameLengthSize + max(len(secret), size) + 1; len(shares[0].Value) != want {
+				t.Errorf("Share length = %d, want %d", len(shares[0].Value), want)
+			}
+
+			// Round trip through the string format, which records the version
+			parsed := make([]Share, 3)
+			for i, share := range shares[1:4] {
+				if parsed[i], err = StringToShare(ShareToString(share)); err != nil {
+					t.Fatalf("StringToShare failed: %v", err)
+				}
+			}
+			recovered, err := Combine(parsed)
+			if err != nil {
+				t.Fatalf("Combine failed: %v", err)
+			}
+			if !bytes.Equal(recovered, secret) {
+				t.Errorf("SplitFramed(%q, size %d) recovered %q", secret, size, recovered)
+			}
+		}
+	}
+}
+
+func TestFrameLayout(t *testing.T) {
+	shares, err := SplitFramed([]byte("hi"), 3, 2, 4)
+	if err != nil {
+		t.Fatalf("SplitFramed failed: %v", err)
+	}
+	if shares[0].Version != VersionFramed {
+		t.Errorf("Version = %d, want %d", shares[0].Version, VersionFramed)
+	}
+
+	// Combining the shares as plain ones reveals the frame
+	plain := []Share{{ID: shares[0].ID, Value: shares[0].Value}, {ID: shares[1].ID, Value: shares[1].Value}}
+	data, err := Combine(plain)
+	if err != nil {
+		t.Fatalf("Combine failed: %v", err)
+	}
+	want := []byte{0, 0, 0, 2, 'h', 'i', 0, 0}
+	if !bytes.Equal(data, want) {
+		t.Errorf("Split data = %v, want %v", data, want)
+	}
+}
+
+func TestParseFrame(t *testing.T) {
+	tests := []struct {
+		data    []byte
+		want    []byte
+		wantErr bool
+	}{
+		{[]byte{0, 0, 0, 0}, []byte{}, false},
+		{[]byte{0, 0, 0, 1, 0, 0}, []byte{0}, false},
+		{[]byte{0, 0, 0, 2, 'h', 'i'}, []byte("hi"), false},
+		{[]byte{0, 0, 0}, nil, true},
+		{[]byte{0, 0, 0, 3, 'h', 'i'}, nil, true},
+		{[]byte{0xff, 0xff, 0xff, 0xff, 'h'}, nil, true},
+		{[]byte{0, 0, 0, 1, 'h', 'i'}, nil, true},
+	}
+	for _, tt := range tests {
+		got, err := parseFrame(tt.data)
+		if tt.wantErr {
+			if !errors.Is(err, ErrInvalidFrame) {
+				t.Errorf("parseFrame(%v) = %v, want ErrInvalidFrame", tt.data, err)
+			}
+			continue
+		}
+		if err != nil || !bytes.Equal(got, tt.want) {
+			t.Errorf("parseFrame(%v) = %v, %v, want %v", tt.data, got, err, tt.want)
+		}
+	}
+}
+
+func TestSplitFramedErrors(t *testing.T) {
+	if _, err := SplitFramed([]byte("x"), 2, 3, 0); !errors.Is(err, ErrThresholdTooLarge) {
+		t.Errorf("SplitFramed with k > n = %v, want ErrThresholdTooLarge", err)
+	}
+}
@@ -0,0 +1,222 @@
+package shamir
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// MaxShares16 is the maximum number of shares over GF(2^16)
+const MaxShares16 = 65535
+
+// gf16Poly is the primitive polynomial x^16 + x^12 + x^3 + x + 1
+const gf16Poly = 0x1100B
+
+// gf16Pad marks the start of the padding that aligns data to 16-bit symbols
+const gf16Pad = 0x80
+
+// Share16 represents one part of a secret split over GF(2^16).
+// Value holds one 16-bit symbol per two bytes of the padded secret.
+type Share16 struct {
+	ID    uint16   `json:"id"`
+	Value []uint16 `json:"value"`
+}
+
+// Log/exp tables for arithmetic in GF(2^16), built on first use
+var (
+	gf16Once sync.Once
+	gf16Exp  [2 * 65535]uint16
+	gf16Log  [65536]uint16
+)
+
+// initGF16 initializes log/exp tables for arithmetic in GF(2^16)
+func initGF16() {
+	x := 1
+	for i := 0; i < 65535; i++ {
+		gf16Exp[i] = uint16(x)
+		gf16Log[x] = uint16(i)
+		x <<= 1
+		if x&0x10000 != 0 {
+			x ^= gf16Poly
+		}
+	}
+
+	// Duplicate the exp table so gf16Mul needs no modulo
+	for i := 65535; i < len(gf16Exp); i++ {
+		gf16Exp[i] = gf16Exp[i-65535]
+	}
+}
+
+// gf16Mul performs multiplication in GF(2^16) using log/exp tables
+func gf16Mul(a, b uint16) uint16 {
+	if a == 0 || b == 0 {
+		return 0
+	}
+	return gf16Exp[int(gf16Log[a])+int(gf16Log[b])]
+}
+
+// gf16Inv calculates the inverse element in GF(2^16)
+func gf16Inv(a uint16) uint16 {
+	if a == 0 {
+		return 0
+	}
+	return gf16Exp[65535-int(gf16Log[a])]
+}
+
+// Split16 divides a secret into n parts over GF(2^16), where k parts are
+// needed for recovery. It allows up to MaxShares16 parts.
+func Split16(secret []byte, n, k int) ([]Share16, error) {
+	if k < 2 {
+		return nil, ErrThresholdTooSmall
+	}
+	if n < k {
+		return nil, ErrThresholdTooLarge
+	}
+	if n > MaxShares16 {
+		return nil, fmt.Errorf("n cannot be greater than %d", MaxShares16)
+	}
+	gf16Once.Do(initGF16)
+
+	// Append checksum and pad to a whole number of 16-bit symbols
+	data := make([]byte, 0, len(secret)+3)
+	data = append(data, secret...)
+	data = append(data, calculateChecksum(secret), gf16Pad)
+	if len(data)%2 != 0 {
+		data = append(data, 0)
+	}
+	symbols := len(data) / 2
+
+	shares := make([]Share16, n)
+	for i := range shares {
+		shares[i] = Share16{ID: uint16(i + 1), Value: make([]uint16, symbols)}
+	}
+
+	coeffs := make([]uint16, k)
+	randomBytes := make([]byte, 2*(k-1))
+	for s := 0; s < symbols; s++ {
+		coeffs[0] = binary.BigEndian.Uint16(data[2*s:])
+		if err := readRandom(rand.Reader, randomBytes); err != nil {
+			return nil, err
+		}
+		for i := 1; i < k; i++ {
+			coeffs[i] = binary.BigEndian.Uint16(randomBytes[2*(i-1):])
+		}
+
+		for i := range shares {
+			// Horner's rule
+			var y uint16
+			for j := k - 1; j >= 0; j-- {
+				y = gf16Mul(y, shares[i].ID) ^ coeffs[j]
+			}
+			shares[i].Value[s] = y
+		}
+	}
+
+	return shares, nil
+}
+
+// Combine16 recovers a secret from parts produced by Split16
+func Combine16(shares []Share16) ([]byte, error) {
+	if len(shares) < 2 {
+		return nil, ErrTooFewShares
+	}
+	gf16Once.Do(initGF16)
+
+	symbols := len(shares[0].Value)
+	for i, share := range shares {
+		if share.ID == 0 {
+			return nil, errors.New("share ID cannot be 0")
+		}
+		if len(share.Value) == 0 {
+			return nil, fmt.Errorf("share %d has empty value", i+1)
+		}
+		if len(share.Value) != symbols {
+			return nil, errors.New("all parts must have the same length")
+		}
+	}
+
+	// Lagrange basis at x=0 depends only on the IDs
+	basis := make([]uint16, len(shares))
+	for i := range shares {
+		var numerator, denominator uint16 = 1, 1
+		for j := range shares {
+			if i == j {
+				continue
+			}
+			if shares[i].ID == shares[j].ID {
+				return nil, fmt.Errorf("duplicate share ID %d", shares[i].ID)
+			}
+			numerator = gf16Mul(numerator, shares[j].ID)
+			denominator = gf16Mul(denominator, shares[i].ID^shares[j].ID)
+		}
+		basis[i] = gf16Mul(numerator, gf16Inv(denominator))
+	}
+
+	data := make([]byte, 2*symbols)
+	for s := 0; s < symbols; s++ {
+		var y uint16
+		for i, share := range shares {
+			y ^= gf16Mul(share.Value[s], basis[i])
+		}
+		binary.BigEndian.PutUint16(data[2*s:], y)
+	}
+
+	// Strip padding: trailing zero bytes followed by the pad marker
+	end := len(data)
+	for end > 0 && data[end-1] == 0 {
+		end--
+	}
+	if end < 2 || data[end-1] != gf16Pad {
+		return nil, ErrChecksumMismatch
+	}
+	data = data[:end-1]
+
+	secret := data[:len(data)-1]
+	if calculateChecksum(secret) != data[len(data)-1] {
+		return nil, fmt.Errorf("%w (%d bytes recovered)", ErrChecksumMismatch, len(data))
+	}
+
+	return secret, nil
+}
+
+// Share16ToString converts a Share16 to string representation
+func Share16ToString(share Share16) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d:", share.ID)
+	for _, v := range share.Value {
+		fmt.Fprintf(&b, "%04x", v)
+	}
+	return b.String()
+}
+
+// StringToShare16 converts string representation to Share16
+func StringToShare16(s string) (Share16, error) {
+	parts := strings.SplitN(s, ":", 2)
+	if len(parts) != 2 {
+		return Share16{}, errors.New("invalid part format")
+	}
+
+	id, err := strconv.ParseUint(parts[0], 10, 16)
+	if err != nil {
+		return Share16{}, errors.New("invalid part format")
+	}
+
+	raw, err := decodeHex(parts[1], len(parts[0])+1)
+	if err != nil {
+		return Share16{}, err
+	}
+	if len(raw)%2 != 0 {
+		return Share16{}, fmt.Errorf("hex value length must be a multiple of 4 (%d)", 2*len(raw))
+	}
+
+	value := make([]uint16, len(raw)/2)
+	for i := range value {
+		value[i] = binary.BigEndian.Uint16(raw[2*i:])
+	}
+
+	return Share16{ID: uint16(id), Value: value}, nil
+}
@@ -0,0 +1,103 @@
+package shamir
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+)
+
+func TestGF16Inverse(t *testing.T) {
+	gf16Once.Do(initGF16)
+
+	for a := 1; a < 65536; a++ {
+		if product := gf16Mul(uint16(a), gf16Inv(uint16(a))); product != 1 {
+			t.Fatalf("a * inv(a) != 1: %d * %d = %d", a, gf16Inv(uint16(a)), product)
+		}
+	}
+}
+
+func TestSplit16ManyShares(t *testing.T) {
+	n, k := 300, 5
+
+	for _, secret := range [][]byte{[]byte("over two hundred fifty five"), []byte("odd"), {}, {0x80, 0x00}} {
+		t.Run(fmt.Sprintf("%x", secret), func(t *testing.T) {
+			shares, err := Split16(secret, n, k)
+			if err != nil {
+				t.Fatalf("Split16 failed: %v", err)
+			}
+			if len(shares) != n {
+				t.Fatalf("Expected %d shares, got %d", n, len(shares))
+			}
+
+			// Recover from shares spread across the whole ID range
+			subsets := [][]int{{0, 1, 2, 3, 4}, {255, 256, 257, 298, 299}, {10, 100, 200, 250, 299}}
+			for _, subset := range subsets {
+				testShares := make([]Share16, len(subset))
+				for i, idx := range subset {
+					testShares[i] = shares[idx]
+				}
+
+				recovered, err := Combine16(testShares)
+				if err != nil {
+					t.Fatalf("Combine16 failed for %v: %v", subset, err)
+				}
+				if !bytes.Equal(recovered, secret) {
+					t.Errorf("Recovery failed for %v: got %x, want %x", subset, recovered, secret)
+				}
+			}
+		})
+	}
+}
+
+func TestSplit16Validation(t *testing.T) {
+	if _, err := Split16([]byte("x"), 5, 1); err == nil {
+		t.Error("Split16 should fail with k < 2")
+	}
+	if _, err := Split16([]byte("x"), 3, 5); err == nil {
+		t.Error("Split16 should fail with n < k")
+	}
+	if _, err := Split16([]byte("x"), MaxShares16+1, 2); err == nil {
+		t.Error("Split16 should fail with too many shares")
+	}
+	if _, err := Combine16([]Share16{{ID: 1, Value: []uint16{1}}}); err == nil {
+		t.Error("Combine16 should fail with only 1 share")
+	}
+	if _, err := Combine16([]Share16{{ID: 0, Value: []uint16{1}}, {ID: 1, Value: []uint16{2}}}); err == nil {
+		t.Error("Combine16 should fail with share ID 0")
+	}
+}
+
+func TestCombine16Corrupted(t *testing.T) {
+	shares, err := Split16([]byte("corrupt me"), 5, 3)
+	if err != nil {
+		t.Fatalf("Split16 failed: %v", err)
+	}
+
+	shares[0].Value[1] ^= 0x1234
+	if _, err := Combine16(shares[:3]); err == nil {
+		t.Error("Combine16 should fail with a corrupted share")
+	}
+}
+
+func TestShare16StringConversion(t *testing.T) {
+	share := Share16{ID: 300, Value: []uint16{0x1234, 0xabcd}}
+
+	str := Share16ToString(share)
+	if str != "300:1234abcd" {
+		t.Errorf("Share16ToString() = %q, want %q", str, "300:1234abcd")
+	}
+
+	parsed, err := StringToShare16(str)
+	if err != nil {
+		t.Fatalf("StringToShare16 failed: %v", err)
+	}
+	if parsed.ID != share.ID || len(parsed.Value) != 2 || parsed.Value[0] != 0x1234 || parsed.Value[1] != 0xabcd {
+		t.Errorf("Round trip changed share: %v", parsed)
+	}
+
+	for _, bad := range []string{"65536:abcd", "1:abcdef", "1:xyzw", "1:", "abcd"} {
+		if _, err := StringToShare16(bad); err == nil {
+			t.Errorf("StringToShare16(%q) should fail", bad)
+		}
+	}
+}
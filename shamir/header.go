@@ -0,0 +1,145 @@
+package shamir
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+// VersionHeader is the share format version of shares split with
+// SplitWithHeader, whose secret is prefixed with a header describing the
+// share set. Combine validates and removes the header.
+const VersionHeader = 3
+
+// Layout of the header prepended to the secret by SplitWithHeader:
+//
+//	offset  size  field
+//	0       2     magic "SH"
+//	2       1     header version, currently 1
+//	3       1     threshold k
+//	4       4     secret length, big-endian
+//
+// The header is split along with the secret, so each share is headerSize
+// bytes longer than with Split.
+const (
+	headerSize    = 8
+	headerVersion = 1
+)
+
+// headerMagic marks the start of a header
+var headerMagic = [2]byte{'S', 'H'}
+
+// ErrInvalidHeader is returned when a secret recovered from VersionHeader
+// shares does not start with a valid header
+var ErrInvalidHeader = errors.New("invalid share set header")
+
+// Header describes a share set split with SplitWithHeader
+type Header struct {
+	Threshold int `json:"threshold"`
+	SecretLen int `json:"secret_len"`
+}
+
+// SplitWithHeader is like Split but prefixes the secret with a header
+// recording the threshold and the secret length before splitting, so the
+// share set describes itself. The shares have version VersionHeader.
+func SplitWithHeader(secret []byte, n, k int) ([]Share, error) {
+	if err := ValidateSplitParams(len(secret), n, k); err != nil {
+		return nil, err
+	}
+	if uint64(len(secret)) > 1<<32-1 {
+		return nil, errors.New("secret is too long for a header")
+	}
+
+	data := make([]byte, headerSize, headerSize+len(secret))
+	copy(data, headerMagic[:])
+	data[2] = headerVersion
+	data[3] = byte(k)
+	binary.BigEndian.PutUint32(data[4:], uint32(len(secret)))
+	data = append(data, secret...)
+
+	shares, err := Split(data, n, k)
+	if err != nil {
+		return nil, err
+	}
+	for i := range shares {
+		shares[i].Version = VersionHeader
+	}
+	return shares, nil
+}
+
+// CombineWithHeader is like Combine for shares split with SplitWithHeader
+// but also returns the header
+func CombineWithHeader(shares []Share) ([]byte, Header, error) {
+	if len(shares) > 0 && shares[0].Version != VersionHeader {
+		return nil, Header{}, fmt.Errorf("share version %d has no header", max(shares[0].Version, 1))
+	}
+
+	// Combine the shares as plain ones to get at the header
+	plain := make([]Share, len(shares))
+	for i, share := range shares {
+		if share.Version != VersionHeader {
+			return nil, Header{}, errors.New("all parts must have the same version")
+		}
+		plain[i] = Share{ID: share.ID, Value: share.Value}
+	}
+
+	data, err := Combine(plain)
+	if err != nil {
+		return nil, Header{}, err
+	}
+	return parseHeader(data, len(shares))
+}
+
+// parseHeader validates the header at the start of data, recovered from
+// the given number of shares, and returns the secret following it
+func parseHeader(data []byte, shares int) ([]byte, Header, error) {
+	if len(data) < headerSize || data[0] != headerMagic[0] || data[1] != headerMagic[1] {
+		return nil, Header{}, ErrInvalidHeader
+	}
+	if data[2] != headerVersion {
+		return nil, Header{}, fmt.Errorf("%w: unsupported header version %d", ErrInvalidHeader, data[2])
+	}
+
+	header := Header{
+		Threshold: int(data[3]),
+		SecretLen: int(binary.BigEndian.Uint32(data[4:])),
+	}
+	if header.SecretLen != len(data)-headerSize {
+		return nil, Header{}, fmt.Errorf("%w: secret length %d, recovered %d bytes", ErrInvalidHeader, header.SecretLen, len(data)-headerSize)
+	}
+	if shares < header.Threshold {
+		return nil, Header{}, fmt.Errorf("%w: %d parts required, only %d provided", ErrInvalidHeader, header.Threshold, shares)
+	}
+	return data[headerSize:], header, nil
+}
+
+// SplitMeta describes a recovered share set
+type SplitMeta struct {
+	// Length is the length of the recovered secret
+	Length int `json:"length"`
+	// Threshold is the number of parts required for recovery, or 0 when
+	// the shares do not record it
+	Threshold int `json:"threshold,omitempty"`
+	// Version is the share format version, 1 for the original format
+	Version int `json:"version"`
+}
+
+// CombineWithMeta is like Combine but also describes the share set. The
+// length and threshold are taken from the header of VersionHeader shares;
+// for older versions the length is that of the recovered secret and the
+// threshold is unknown.
+func CombineWithMeta(shares []Share) ([]byte, SplitMeta, error) {
+	if len(shares) > 0 && shares[0].Version == VersionHeader {
+		secret, header, err := CombineWithHeader(shares)
+		if err != nil {
+			return nil, SplitMeta{}, err
+		}
+		return secret, SplitMeta{Length: header.SecretLen, Threshold: header.Threshold, Version: VersionHeader}, nil
+	}
+
+	secret, err := Combine(shares)
+	if err != nil {
+		return nil, SplitMeta{}, err
+	}
+	return secret, SplitMeta{Length: len(secret), Version: int(max(shares[0].Version, 1))}, nil
+}
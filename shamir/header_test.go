@@ -0,0 +1,144 @@
+package shamir
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestSplitWithHeaderRoundTrip(t *testing.T) {
+	for _, secret := range [][]byte{{}, []byte("hello"), bytes.Repeat([]byte{0xab}, 1000)} {
+		shares, err := SplitWithHeader(secret, 5, 3)
+		if err != nil {
+			t.Fatalf("SplitWithHeader failed: %v", err)
+		}
+		if len(shares[0].Value) != headerSize+len(secret)+1 {
+			t.Errorf("Share length = %d, want %d", len(shares[0].Value), headerSize+len(secret)+1)
+		}
+
+		// Round trip through the string format, which records the version
+		parsed := make([]Share, 3)
+		for i, share := range shares[2:] {
+			if parsed[i], err = StringToShare(ShareToString(share)); err != nil {
+				t.Fatalf("StringToShare failed: %v", err)
+			}
+		}
+
+		recovered, header, err := CombineWithHeader(parsed)
+		if err != nil {
+			t.Fatalf("CombineWithHeader failed: %v", err)
+		}
+		if !bytes.Equal(recovered, secret) {
+			t.Errorf("Recovery failed: got %q, want %q", recovered, secret)
+		}
+		if want := (Header{Threshold: 3, SecretLen: len(secret)}); header != want {
+			t.Errorf("Header = %+v, want %+v", header, want)
+		}
+
+		// Combine strips the header as well
+		recovered, err = Combine(parsed)
+		if err != nil {
+			t.Fatalf("Combine failed: %v", err)
+		}
+		if !bytes.Equal(recovered, secret) {
+			t.Errorf("Combine() = %q, want %q", recovered, secret)
+		}
+	}
+}
+
+func TestHeaderLayout(t *testing.T) {
+	shares, err := SplitWithHeader([]byte("hello"), 3, 2)
+	if err != nil {
+		t.Fatalf("SplitWithHeader failed: %v", err)
+	}
+
+	// Combining the shares as plain ones reveals the prefixed header
+	plain := []Share{{ID: shares[0].ID, Value: shares[0].Value}, {ID: shares[1].ID, Value: shares[1].Value}}
+	data, err := Combine(plain)
+	if err != nil {
+		t.Fatalf("Combine failed: %v", err)
+	}
+
+	want := []byte{'S', 'H', 1, 2, 0, 0, 0, 5, 'h', 'e', 'l', 'l', 'o'}
+	if !bytes.Equal(data, want) {
+		t.Errorf("Split data = %v, want %v", data, want)
+	}
+}
+
+func TestCombineWithHeaderErrors(t *testing.T) {
+	plain, err := Split([]byte("no header here"), 3, 2)
+	if err != nil {
+		t.Fatalf("Split failed: %v", err)
+	}
+	if _, _, err := CombineWithHeader(plain); err == nil {
+		t.Error("CombineWithHeader should reject shares without a header")
+	}
+
+	// Shares claiming a header that is not there
+	for i := range plain {
+		plain[i].Version = VersionHeader
+	}
+	if _, err := Combine(plain); !errors.Is(err, ErrInvalidHeader) {
+		t.Errorf("Combine() error = %v, want %v", err, ErrInvalidHeader)
+	}
+
+	if _, err := SplitWithHeader([]byte("x"), 3, 1); !errors.Is(err, ErrThresholdTooSmall) {
+		t.Errorf("SplitWithHeader() error = %v, want %v", err, ErrThresholdTooSmall)
+	}
+}
+
+func TestParseHeaderBelowThreshold(t *testing.T) {
+	data := []byte{'S', 'H', 1, 3, 0, 0, 0, 1, 'x'}
+	if _, _, err := parseHeader(data, 2); !errors.Is(err, ErrInvalidHeader) {
+		t.Errorf("parseHeader() error = %v, want %v", err, ErrInvalidHeader)
+	}
+	if secret, _, err := parseHeader(data, 3); err != nil || string(secret) != "x" {
+		t.Errorf("parseHeader() = %q, %v, want %q", secret, err, "x")
+	}
+}
+
+func TestCombineWithMeta(t *testing.T) {
+	secret := []byte("meta secret")
+
+	headered, err := SplitWithHeader(secret, 5, 3)
+	if err != nil {
+		t.Fatalf("SplitWithHeader failed: %v", err)
+	}
+	plain, err := Split(secret, 5, 3)
+	if err != nil {
+		t.Fatalf("Split failed: %v", err)
+	}
+	padded, err := SplitPadded(secret, 5, 3, 16)
+	if err != nil {
+		t.Fatalf("SplitPadded failed: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		shares []Share
+		want   SplitMeta
+	}{
+		{"header", headered[1:4], SplitMeta{Length: len(secret), Threshold: 3, Version: VersionHeader}},
+		{"plain", plain[:3], SplitMeta{Length: len(secret), Version: 1}},
+		{"padded", padded[2:], SplitMeta{Length: len(secret), Version: VersionPadded}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, meta, err := CombineWithMeta(tt.shares)
+			if err != nil {
+				t.Fatalf("CombineWithMeta failed: %v", err)
+			}
+			if !bytes.Equal(got, secret) {
+				t.Errorf("secret = %q, want %q", got, secret)
+			}
+			if meta != tt.want {
+				t.Errorf("meta = %+v, want %+v", meta, tt.want)
+			}
+		})
+	}
+
+	if _, _, err := CombineWithMeta(headered[:2]); err == nil {
+		t.Error("expected error below threshold")
+	}
+}
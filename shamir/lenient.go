@@ -0,0 +1,74 @@
+package shamir
+
+import (
+	"errors"
+	"fmt"
+)
+
+// CombineLenient is a best-effort salvage mode for parts that were damaged,
+// e.g. truncated while being copied. Unlike Combine it does not fail on
+// parts of different lengths but trims all of them to the shortest one and
+// recovers that prefix of the secret.
+//
+// When the parts have the same length and the checksum matches, the result
+// is the same as Combine's and verified is true. Otherwise the recovered
+// bytes are returned unverified: they may be incomplete or, if the parts are
+// corrupted beyond truncation, wrong. Padding added by Pad is only removed
+// from verified secrets.
+//
+// VersionHeader and VersionFramed shares are refused: their secret is
+// preceded by a header or length that a partial recovery cannot check.
+func CombineLenient(shares []Share) (data []byte, verified bool, err error) {
+	if len(shares) < 2 {
+		return nil, false, ErrTooFewShares
+	}
+
+	if err := checkShareIDs(shares); err != nil {
+		return nil, false, err
+	}
+	for _, share := range shares[1:] {
+		if share.Version != shares[0].Version {
+			return nil, false, errors.New("all parts must have the same version")
+		}
+	}
+	if v := shares[0].Version; v == VersionHeader || v == VersionFramed {
+		return nil, false, fmt.Errorf("lenient recovery does not support version %d shares", v)
+	}
+
+	minLen, maxLen := len(shares[0].Value), len(shares[0].Value)
+	xs := make([]byte, len(shares))
+	for i, share := range shares {
+		if len(share.Value) == 0 {
+			return nil, false, fmt.Errorf("share %d has empty value", i+1)
+		}
+		xs[i] = share.ID
+		minLen = min(minLen, len(share.Value))
+		maxLen = max(maxLen, len(share.Value))
+	}
+
+	if minLen == maxLen {
+		secret, err := Combine(shares)
+		if err == nil {
+			return secret, true, nil
+		}
+		if !errors.Is(err, ErrChecksumMismatch) && !errors.Is(err, ErrInvalidPadding) {
+			return nil, false, err
+		}
+	}
+
+	basis := defaultField.lagrangeCoefficients(xs, 0)
+	data = make([]byte, minLen)
+	for byteIndex := range data {
+		var result byte
+		for i, share := range shares {
+			result = gfAdd(result, gfMul(share.Value[byteIndex], basis[i]))
+		}
+		data[byteIndex] = result
+	}
+
+	// Without truncation the checksum is at the end, not part of the secret
+	if minLen == maxLen {
+		data = data[:max(minLen-versionChecksum(shares[0].Version).Size(), 0)]
+	}
+	return data, false, nil
+}
@@ -0,0 +1,110 @@
+package shamir
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestCombineLenientShortShare(t *testing.T) {
+	secret := []byte("a secret that gets truncated")
+	shares, err := Split(secret, 5, 3)
+	if err != nil {
+		t.Fatalf("Split failed: %v", err)
+	}
+
+	// One share lost its last 10 bytes while being copied
+	damaged := []Share{shares[0], {ID: shares[1].ID, Value: shares[1].Value[:len(shares[1].Value)-10]}, shares[2]}
+
+	if _, err := Combine(damaged); err == nil {
+		t.Fatal("Combine should reject parts of different lengths")
+	}
+
+	data, verified, err := CombineLenient(damaged)
+	if err != nil {
+		t.Fatalf("CombineLenient failed: %v", err)
+	}
+	if verified {
+		t.Error("Truncated recovery must not be reported as verified")
+	}
+	if want := secret[:len(secret)+1-10]; !bytes.Equal(data, want) {
+		t.Errorf("CombineLenient() = %q, want prefix %q", data, want)
+	}
+}
+
+func TestCombineLenientIntact(t *testing.T) {
+	secret := []byte("intact")
+	shares, err := SplitPadded(secret, 3, 2, 16)
+	if err != nil {
+		t.Fatalf("SplitPadded failed: %v", err)
+	}
+
+	data, verified, err := CombineLenient(shares[:2])
+	if err != nil {
+		t.Fatalf("CombineLenient failed: %v", err)
+	}
+	if !verified || !bytes.Equal(data, secret) {
+		t.Errorf("CombineLenient() = %q, %v, want %q, true", data, verified, secret)
+	}
+}
+
+func TestCombineLenientChecksumMismatch(t *testing.T) {
+	secret := []byte("corrupted")
+	shares, err := Split(secret, 3, 2)
+	if err != nil {
+		t.Fatalf("Split failed: %v", err)
+	}
+	shares[0].Value[0] ^= 0x01
+
+	data, verified, err := CombineLenient(shares[:2])
+	if err != nil {
+		t.Fatalf("CombineLenient failed: %v", err)
+	}
+	if verified {
+		t.Error("Recovery with a checksum mismatch must not be reported as verified")
+	}
+	if len(data) != len(secret) || !bytes.Equal(data[1:], secret[1:]) {
+		t.Errorf("CombineLenient() = %q, want all but the first byte of %q", data, secret)
+	}
+}
+
+func TestCombineLenientCRC32(t *testing.T) {
+	secret := []byte("corrupted")
+	shares, err := SplitWithChecksum(secret, 3, 2, CRC32Checksum{})
+	if err != nil {
+		t.Fatalf("SplitWithChecksum failed: %v", err)
+	}
+	shares[0].Value[0] ^= 0x01
+
+	data, verified, err := CombineLenient(shares[:2])
+	if err != nil {
+		t.Fatalf("CombineLenient failed: %v", err)
+	}
+	if verified {
+		t.Error("Recovery with a checksum mismatch must not be reported as verified")
+	}
+	if len(data) != len(secret) || !bytes.Equal(data[1:], secret[1:]) {
+		t.Errorf("CombineLenient() = %q, want all but the first byte of %q", data, secret)
+	}
+}
+
+func TestCombineLenientErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		shares []Share
+	}{
+		{"Too few", []Share{{ID: 1, Value: []byte{1}}}},
+		{"Empty value", []Share{{ID: 1, Value: []byte{1}}, {ID: 2}}},
+		{"Duplicate ID", []Share{{ID: 1, Value: []byte{1}}, {ID: 1, Value: []byte{2}}}},
+		{"Header", []Share{{ID: 1, Value: []byte{1}, Version: VersionHeader}, {ID: 2, Value: []byte{2}, Version: VersionHeader}}},
+		{"Framed", []Share{{ID: 1, Value: []byte{1}, Version: VersionFramed}, {ID: 2, Value: []byte{2}, Version: VersionFramed}}},
+		{"Framed second", []Share{{ID: 1, Value: []byte{1, 2}}, {ID: 2, Value: []byte{2}, Version: VersionFramed}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, _, err := CombineLenient(tt.shares); err == nil {
+				t.Error("CombineLenient should fail")
+			}
+		})
+	}
+}
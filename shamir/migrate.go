@@ -0,0 +1,62 @@
+package shamir
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"sort"
+)
+
+// MigrateCRC32 recovers the secret from version 1 shares, protected by the
+// one-byte XOR checksum, and splits it again into n shares of version
+// VersionCRC32 and threshold k. At least k of the old shares must be given.
+// The new shares keep the IDs of the old ones, so every holder gets a share
+// with the same ID back; when n is larger, the lowest unused IDs are added.
+// Shares of other versions are refused: padded and header shares would
+// lose their padding or header, and CRC-32 shares need no migration.
+func MigrateCRC32(shares []Share, n, k int) ([]Share, error) {
+	for _, share := range shares {
+		if share.Version > 1 {
+			return nil, fmt.Errorf("share %d has version %d, only version 1 shares can be migrated", share.ID, share.Version)
+		}
+	}
+	if err := ValidateSplitParams(0, n, k); err != nil {
+		return nil, err
+	}
+	if len(shares) < k {
+		return nil, fmt.Errorf("%w: %d parts required, only %d provided", ErrTooFewShares, k, len(shares))
+	}
+
+	secret, err := Combine(shares)
+	if err != nil {
+		return nil, err
+	}
+	migrated, err := splitData(context.Background(), defaultField, withChecksum(secret, CRC32Checksum{}), migrateIDs(shares, n), k, rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	for i := range migrated {
+		migrated[i].Version = VersionCRC32
+	}
+	return migrated, nil
+}
+
+// migrateIDs returns n distinct IDs in ascending order: those of the first
+// n shares, completed with the lowest IDs not taken by any share
+func migrateIDs(shares []Share, n int) []byte {
+	var taken [256]bool
+	ids := make([]byte, 0, n)
+	for _, share := range shares {
+		if len(ids) < n {
+			ids = append(ids, share.ID)
+		}
+		taken[share.ID] = true
+	}
+	for id := 1; len(ids) < n; id++ {
+		if !taken[id] {
+			ids = append(ids, byte(id))
+		}
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids
+}
@@ -0,0 +1,75 @@
+package shamir
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestMigrateCRC32(t *testing.T) {
+	secret := []byte("migrated secret")
+	old, err := Split(secret, 5, 3)
+	if err != nil {
+		t.Fatalf("Split failed: %v", err)
+	}
+
+	// The new shares keep IDs 5, 2 and 3 and get the lowest free ID
+	migrated, err := MigrateCRC32([]Share{old[4], old[1], old[2]}, 4, 3)
+	if err != nil {
+		t.Fatalf("MigrateCRC32 failed: %v", err)
+	}
+	wantIDs := []byte{1, 2, 3, 5}
+	if len(migrated) != len(wantIDs) {
+		t.Fatalf("MigrateCRC32 returned %d shares, want %d", len(migrated), len(wantIDs))
+	}
+	for i, share := range migrated {
+		if share.Version != VersionCRC32 || share.ID != wantIDs[i] || len(share.Value) != len(old[i].Value)+3 {
+			t.Errorf("Migrated share %d = version %d, ID %d, %d bytes", i+1, share.Version, share.ID, len(share.Value))
+		}
+	}
+
+	got, err := Combine([]Share{migrated[0], migrated[2], migrated[3]})
+	if err != nil || string(got) != string(secret) {
+		t.Errorf("Combine of migrated shares = %q, %v", got, err)
+	}
+	if _, err := Combine([]Share{migrated[0], old[1], old[2]}); err == nil {
+		t.Error("Combine accepted old and migrated shares together")
+	}
+
+	// With fewer new shares than old ones, the first old IDs are kept
+	migrated, err = MigrateCRC32([]Share{old[4], old[1], old[2], old[0]}, 3, 3)
+	if err != nil {
+		t.Fatalf("MigrateCRC32 failed: %v", err)
+	}
+	for i, id := range []byte{2, 3, 5} {
+		if migrated[i].ID != id {
+			t.Errorf("Migrated share %d has ID %d, want %d", i+1, migrated[i].ID, id)
+		}
+	}
+}
+
+func TestMigrateCRC32Errors(t *testing.T) {
+	old, err := Split([]byte("secret"), 3, 2)
+	if err != nil {
+		t.Fatalf("Split failed: %v", err)
+	}
+	if _, err := MigrateCRC32(old[:1], 3, 2); !errors.Is(err, ErrTooFewShares) {
+		t.Errorf("MigrateCRC32 of 1 share = %v, want ErrTooFewShares", err)
+	}
+	if _, err := MigrateCRC32(old, 2, 3); !errors.Is(err, ErrThresholdTooLarge) {
+		t.Errorf("MigrateCRC32 with n < k = %v, want ErrThresholdTooLarge", err)
+	}
+
+	crc, err := SplitWithChecksum([]byte("secret"), 3, 2, CRC32Checksum{})
+	if err != nil {
+		t.Fatalf("SplitWithChecksum failed: %v", err)
+	}
+	padded, err := SplitPadded([]byte("secret"), 3, 2, 16)
+	if err != nil {
+		t.Fatalf("SplitPadded failed: %v", err)
+	}
+	for name, shares := range map[string][]Share{"CRC-32": crc, "padded": padded} {
+		if _, err := MigrateCRC32(shares, 3, 2); err == nil {
+			t.Errorf("MigrateCRC32 accepted %s shares", name)
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package shamir
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+// multiLengthSize is the size of the big-endian length prefix of each
+// secret packed by SplitMulti
+const multiLengthSize = 4
+
+// SplitMulti packs several independent secrets into one set of n shares,
+// where k shares are needed to recover all of them. Each secret is stored
+// as a 4-byte big-endian length followed by its bytes.
+func SplitMulti(secrets [][]byte, n, k int) ([]Share, error) {
+	if len(secrets) == 0 {
+		return nil, errors.New("at least one secret is required")
+	}
+
+	size := 0
+	for i, secret := range secrets {
+		if uint64(len(secret)) > 1<<32-1 {
+			return nil, fmt.Errorf("secret %d is too long for a length prefix", i+1)
+		}
+		size += multiLengthSize + len(secret)
+	}
+
+	packed := make([]byte, 0, size)
+	for _, secret := range secrets {
+		packed = binary.BigEndian.AppendUint32(packed, uint32(len(secret)))
+		packed = append(packed, secret...)
+	}
+
+	return Split(packed, n, k)
+}
+
+// CombineMulti recovers the secrets packed by SplitMulti
+func CombineMulti(shares []Share) ([][]byte, error) {
+	packed, err := Combine(shares)
+	if err != nil {
+		return nil, err
+	}
+
+	var secrets [][]byte
+	for len(packed) > 0 {
+		if len(packed) < multiLengthSize {
+			return nil, errors.New("invalid multi-secret framing: truncated length")
+		}
+		length := binary.BigEndian.Uint32(packed)
+		packed = packed[multiLengthSize:]
+
+		if uint64(length) > uint64(len(packed)) {
+			return nil, errors.New("invalid multi-secret framing: length exceeds data")
+		}
+		secrets = append(secrets, packed[:length])
+		packed = packed[length:]
+	}
+
+	if len(secrets) == 0 {
+		return nil, errors.New("invalid multi-secret framing: no secrets")
+	}
+	return secrets, nil
+}
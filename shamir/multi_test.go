@@ -0,0 +1,55 @@
+package shamir
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestSplitMultiAndCombineMulti(t *testing.T) {
+	secrets := [][]byte{
+		[]byte("first secret"),
+		{},
+		{0x00, 0xFF, 0x10},
+	}
+
+	shares, err := SplitMulti(secrets, 5, 3)
+	if err != nil {
+		t.Fatalf("SplitMulti failed: %v", err)
+	}
+
+	recovered, err := CombineMulti(shares[2:])
+	if err != nil {
+		t.Fatalf("CombineMulti failed: %v", err)
+	}
+
+	if len(recovered) != len(secrets) {
+		t.Fatalf("Recovered %d secrets, want %d", len(recovered), len(secrets))
+	}
+	for i := range secrets {
+		if !bytes.Equal(recovered[i], secrets[i]) {
+			t.Errorf("Secret %d: got %x, want %x", i, recovered[i], secrets[i])
+		}
+	}
+}
+
+func TestSplitMultiValidation(t *testing.T) {
+	if _, err := SplitMulti(nil, 5, 3); err == nil {
+		t.Error("SplitMulti should fail without secrets")
+	}
+
+	if _, err := SplitMulti([][]byte{[]byte("a")}, 2, 3); err == nil {
+		t.Error("SplitMulti should fail when n is less than k")
+	}
+}
+
+func TestCombineMultiInvalidFraming(t *testing.T) {
+	// A plain secret is not valid multi-secret framing
+	shares, err := Split([]byte("plain"), 3, 2)
+	if err != nil {
+		t.Fatalf("Split failed: %v", err)
+	}
+
+	if _, err := CombineMulti(shares[:2]); err == nil {
+		t.Error("CombineMulti should fail on data without framing")
+	}
+}
@@ -0,0 +1,76 @@
+package shamir
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+)
+
+// VersionPadded is the share format version of shares whose secret was
+// padded with Pad before splitting. Combine removes the padding again.
+const VersionPadded = 2
+
+// MaxPadBlock is the largest block size supported by Pad
+const MaxPadBlock = 255
+
+// ErrInvalidPadding is returned when a recovered padded secret does not end
+// with valid padding
+var ErrInvalidPadding = errors.New("invalid padding")
+
+// Pad returns a copy of data padded PKCS#7-style to a multiple of block
+// bytes: n bytes of value n are appended, 1 <= n <= block. Data that is
+// already a multiple of block gets a full block, so the padding can always
+// be removed unambiguously.
+func Pad(data []byte, block int) ([]byte, error) {
+	if block < 1 || block > MaxPadBlock {
+		return nil, fmt.Errorf("pad block size must be between 1 and %d", MaxPadBlock)
+	}
+
+	n := block - len(data)%block
+	padded := make([]byte, len(data), len(data)+n)
+	copy(padded, data)
+	return append(padded, bytes.Repeat([]byte{byte(n)}, n)...), nil
+}
+
+// Unpad removes the padding added by Pad
+func Unpad(data []byte) ([]byte, error) {
+	if len(data) == 0 {
+		return nil, ErrInvalidPadding
+	}
+
+	n := int(data[len(data)-1])
+	if n == 0 || n > len(data) {
+		return nil, ErrInvalidPadding
+	}
+	for _, b := range data[len(data)-n:] {
+		if int(b) != n {
+			return nil, ErrInvalidPadding
+		}
+	}
+	return data[:len(data)-n], nil
+}
+
+// SplitPadded is like Split but pads the secret to a multiple of block bytes
+// first, so the length of the shares only reveals the secret length rounded
+// up to the block size. The shares have version VersionPadded.
+func SplitPadded(secret []byte, n, k, block int) ([]Share, error) {
+	padded, err := Pad(secret, block)
+	if err != nil {
+		return nil, err
+	}
+
+	shares, err := Split(padded, n, k)
+	if err != nil {
+		return nil, err
+	}
+	MarkPadded(shares)
+	return shares, nil
+}
+
+// MarkPadded sets the version of shares split from a secret padded with Pad
+// to VersionPadded, so Combine removes the padding
+func MarkPadded(shares []Share) {
+	for i := range shares {
+		shares[i].Version = VersionPadded
+	}
+}
@@ -0,0 +1,117 @@
+package shamir
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestPadUnpad(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    []byte
+		block   int
+		wantLen int
+	}{
+		{"Empty", []byte{}, 16, 16},
+		{"Shorter than block", []byte("secret"), 16, 16},
+		{"Exact multiple", []byte("0123456789abcdef"), 16, 32},
+		{"Longer than block", []byte("0123456789abcdef!"), 16, 32},
+		{"Block of one", []byte("abc"), 1, 4},
+		{"Largest block", []byte("abc"), MaxPadBlock, MaxPadBlock},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			padded, err := Pad(tt.data, tt.block)
+			if err != nil {
+				t.Fatalf("Pad failed: %v", err)
+			}
+			if len(padded) != tt.wantLen {
+				t.Errorf("Padded length = %d, want %d", len(padded), tt.wantLen)
+			}
+
+			unpadded, err := Unpad(padded)
+			if err != nil {
+				t.Fatalf("Unpad failed: %v", err)
+			}
+			if !bytes.Equal(unpadded, tt.data) {
+				t.Errorf("Unpad() = %q, want %q", unpadded, tt.data)
+			}
+		})
+	}
+}
+
+func TestPadInvalidBlock(t *testing.T) {
+	for _, block := range []int{0, -1, MaxPadBlock + 1} {
+		if _, err := Pad([]byte("x"), block); err == nil {
+			t.Errorf("Pad with block %d should fail", block)
+		}
+	}
+}
+
+func TestUnpadInvalid(t *testing.T) {
+	for _, data := range [][]byte{
+		{},
+		{'a', 0},
+		{'a', 3, 3},
+		{'a', 2, 3, 3},
+	} {
+		if _, err := Unpad(data); !errors.Is(err, ErrInvalidPadding) {
+			t.Errorf("Unpad(%v) error = %v, want %v", data, err, ErrInvalidPadding)
+		}
+	}
+}
+
+func TestSplitPaddedRoundTrip(t *testing.T) {
+	secrets := [][]byte{
+		{},
+		[]byte("short"),
+		[]byte("exactly 16 bytes"),
+		bytes.Repeat([]byte{0x10}, 40),
+	}
+
+	for _, secret := range secrets {
+		shares, err := SplitPadded(secret, 5, 3, 16)
+		if err != nil {
+			t.Fatalf("SplitPadded failed: %v", err)
+		}
+
+		for _, share := range shares {
+			if share.Version != VersionPadded {
+				t.Fatalf("Share version = %d, want %d", share.Version, VersionPadded)
+			}
+			if (len(share.Value)-1)%16 != 0 {
+				t.Errorf("Share length %d does not hide the secret length", len(share.Value))
+			}
+		}
+
+		// Round trip through the string format, which records the version
+		parsed := make([]Share, 3)
+		for i, share := range shares[1:4] {
+			if parsed[i], err = StringToShare(ShareToString(share)); err != nil {
+				t.Fatalf("StringToShare failed: %v", err)
+			}
+		}
+
+		recovered, err := Combine(parsed)
+		if err != nil {
+			t.Fatalf("Combine failed: %v", err)
+		}
+		if !bytes.Equal(recovered, secret) {
+			t.Errorf("Recovery failed: got %q, want %q", recovered, secret)
+		}
+	}
+}
+
+func TestCombineMixedVersions(t *testing.T) {
+	shares, err := SplitPadded([]byte("secret"), 3, 2, 8)
+	if err != nil {
+		t.Fatalf("SplitPadded failed: %v", err)
+	}
+	shares[1].Version = 0
+
+	if _, err := Combine(shares[:2]); err == nil {
+		t.Error("Combine should reject parts with different versions")
+	}
+}
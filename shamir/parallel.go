@@ -0,0 +1,122 @@
+package shamir
+
+import (
+	"context"
+	"crypto/rand"
+	"runtime"
+	"sync"
+	"sync/atomic"
+)
+
+// parallelMinSize is the secret length from which Split spreads the work
+// across all CPUs
+const parallelMinSize = 64 * 1024
+
+// parallelChunkSize is the number of secret bytes a worker takes at a time
+const parallelChunkSize = ctxCheckInterval
+
+// SplitParallel is like SplitContext but splits the secret using the given
+// number of worker goroutines. A value of workers below 1 means
+// runtime.GOMAXPROCS(0).
+func SplitParallel(ctx context.Context, secret []byte, n, k, workers int) ([]Share, error) {
+	if err := ValidateSplitParams(len(secret), n, k); err != nil {
+		return nil, err
+	}
+	if workers < 1 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+
+	return splitParallel(ctx, defaultField, withChecksum(secret, XORChecksum{}), defaultIDs(n), k, workers)
+}
+
+// splitParallel evaluates the polynomials for data, which already carries
+// the checksum, in chunks handed out to workers. Each worker draws its own
+// random coefficients and writes only the bytes of its chunk, so the share
+// values are filled without locking.
+func splitParallel(ctx context.Context, f *Field, data []byte, ids []byte, k, workers int) ([]Share, error) {
+	shares := make([]Share, len(ids))
+	for i, id := range ids {
+		shares[i] = Share{ID: id, Value: make([]byte, len(data))}
+	}
+
+	if err := checkpoint(ctx, 0, len(data)); err != nil {
+		return nil, err
+	}
+
+	chunks := (len(data) + parallelChunkSize - 1) / parallelChunkSize
+	if workers > chunks {
+		workers = chunks
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		next     int64 = -1
+		mu       sync.Mutex
+		done     int
+		firstErr error
+		wg       sync.WaitGroup
+	)
+
+	// finish records the progress of a chunk, or the first error seen
+	finish := func(n int, err error) bool {
+		mu.Lock()
+		defer mu.Unlock()
+		if err == nil && firstErr == nil {
+			done += n
+			err = checkpoint(ctx, done, len(data))
+		}
+		if err != nil && firstErr == nil {
+			firstErr = err
+			cancel()
+		}
+		return firstErr == nil
+	}
+
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			coeffs := make([]byte, k)
+			random := make([]byte, (k-1)*parallelChunkSize)
+
+			for {
+				c := int(atomic.AddInt64(&next, 1))
+				if c >= chunks {
+					return
+				}
+				if err := ctx.Err(); err != nil {
+					finish(0, err)
+					return
+				}
+				start := c * parallelChunkSize
+				end := min(start+parallelChunkSize, len(data))
+
+				buf := random[:(k-1)*(end-start)]
+				if err := readRandom(rand.Reader, buf); err != nil {
+					finish(0, err)
+					return
+				}
+
+				for b := start; b < end; b++ {
+					coeffs[0] = data[b]
+					copy(coeffs[1:], buf[(b-start)*(k-1):])
+					for i := range shares {
+						shares[i].Value[b] = f.evaluatePolynomial(coeffs, shares[i].ID)
+					}
+				}
+
+				if !finish(end-start, nil) {
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	return shares, nil
+}
@@ -0,0 +1,117 @@
+package shamir
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestSplitParallel(t *testing.T) {
+	secret := bytes.Repeat([]byte("parallel secret "), 1000) // spans several chunks
+
+	for _, workers := range []int{0, 1, 3, 64} {
+		t.Run(fmt.Sprintf("workers=%d", workers), func(t *testing.T) {
+			shares, err := SplitParallel(context.Background(), secret, 5, 3, workers)
+			if err != nil {
+				t.Fatalf("SplitParallel failed: %v", err)
+			}
+			if len(shares) != 5 {
+				t.Fatalf("Expected 5 shares, got %d", len(shares))
+			}
+
+			recovered, err := Combine([]Share{shares[4], shares[0], shares[2]})
+			if err != nil {
+				t.Fatalf("Combine failed: %v", err)
+			}
+			if !bytes.Equal(recovered, secret) {
+				t.Errorf("Recovered secret does not match original")
+			}
+		})
+	}
+
+	if _, err := SplitParallel(context.Background(), secret, 2, 3, 0); err != ErrThresholdTooLarge {
+		t.Errorf("Expected ErrThresholdTooLarge, got %v", err)
+	}
+}
+
+func TestSplitLargeSecret(t *testing.T) {
+	secret := make([]byte, parallelMinSize+123)
+	for i := range secret {
+		secret[i] = byte(i * 7)
+	}
+
+	shares, err := Split(secret, 4, 2)
+	if err != nil {
+		t.Fatalf("Split failed: %v", err)
+	}
+
+	recovered, err := Combine(shares[2:])
+	if err != nil {
+		t.Fatalf("Combine failed: %v", err)
+	}
+	if !bytes.Equal(recovered, secret) {
+		t.Errorf("Recovered secret does not match original")
+	}
+}
+
+func TestSplitParallelProgress(t *testing.T) {
+	secret := make([]byte, 10*parallelChunkSize)
+
+	last := -1
+	ctx := WithProgress(context.Background(), func(done, total int) {
+		if done < last {
+			t.Errorf("Progress went backwards: %d after %d", done, last)
+		}
+		if total != len(secret)+1 {
+			t.Errorf("Expected total %d, got %d", len(secret)+1, total)
+		}
+		last = done
+	})
+
+	if _, err := SplitParallel(ctx, secret, 3, 2, 4); err != nil {
+		t.Fatalf("SplitParallel failed: %v", err)
+	}
+	if last != len(secret)+1 {
+		t.Errorf("Expected final progress %d, got %d", len(secret)+1, last)
+	}
+}
+
+func TestSplitParallelCancellation(t *testing.T) {
+	secret := make([]byte, 10*parallelChunkSize)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := SplitParallel(ctx, secret, 3, 2, 4); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+
+	// Cancel from the progress callback while workers are running
+	ctx, cancel = context.WithCancel(context.Background())
+	defer cancel()
+	ctx = WithProgress(ctx, func(done, total int) {
+		if done > 0 {
+			cancel()
+		}
+	})
+	if _, err := SplitParallel(ctx, secret, 3, 2, 4); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+}
+
+func benchmarkSplit1MB(b *testing.B, workers int) {
+	secret := make([]byte, 1<<20)
+
+	b.SetBytes(int64(len(secret)))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := SplitParallel(context.Background(), secret, 10, 5, workers); err != nil {
+			b.Fatalf("SplitParallel failed: %v", err)
+		}
+	}
+}
+
+func BenchmarkSplit1MBSerial(b *testing.B) { benchmarkSplit1MB(b, 1) }
+
+func BenchmarkSplit1MBParallel(b *testing.B) { benchmarkSplit1MB(b, 0) }
@@ -0,0 +1,157 @@
+package shamir
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ParseError is returned by ParseShares for a share that could not be parsed
+type ParseError struct {
+	Index int    // 1-based position of the share in the input list
+	Input string // the share as given, without surrounding whitespace
+	Err   error  // the reason the share was rejected
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("part %d (%q): %v", e.Index, e.Input, e.Err)
+}
+
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// ParseShares parses a list of shares with ParseShare, skipping empty
+// entries. Chunks of shares (IsChunk) may be given in any order; they are
+// joined with JoinChunks and the joined shares are appended to the others.
+// A share or chunk that does not parse is reported as a *ParseError.
+func ParseShares(inputs []string) ([]Share, error) {
+	shares := make([]Share, 0, len(inputs))
+	var chunks []Chunk
+	for i, input := range inputs {
+		input = strings.TrimSpace(input)
+		if input == "" {
+			continue
+		}
+
+		if IsChunk(input) {
+			c, err := StringToChunk(input)
+			if err != nil {
+				return nil, &ParseError{Index: i + 1, Input: input, Err: err}
+			}
+			chunks = append(chunks, c)
+			continue
+		}
+
+		share, err := ParseShare(input)
+		if err != nil {
+			return nil, &ParseError{Index: i + 1, Input: input, Err: err}
+		}
+		shares = append(shares, share)
+	}
+
+	if len(chunks) > 0 {
+		joined, err := JoinChunks(chunks)
+		if err != nil {
+			return nil, err
+		}
+		shares = append(shares, joined...)
+	}
+	return shares, nil
+}
+
+// ParseShare parses a share in any of the supported encodings, detecting
+// the encoding from its content. The rules are applied in this order:
+//
+//  1. an armored block (ArmorShare) holding exactly one share
+//  2. a string with a colon: "ID:hex", optionally with a version prefix
+//     (ShareToString)
+//  3. a string with a dash: "ID-BASE32", optionally with a version prefix
+//     and in any case (ShareToBase32)
+//  4. a string of hex digits only: hex(ID||Value) (ShareToCompact)
+//  5. anything else: standard base64 of ID||Value, as in the body of an
+//     armored block
+//
+// Hex takes precedence over base64, so a string such as "ab12" that is
+// valid in both is read as compact hex.
+func ParseShare(s string) (Share, error) {
+	s = strings.TrimSpace(s)
+
+	switch {
+	case IsArmored(s):
+		shares, _, err := ParseArmor(s)
+		if err != nil {
+			return Share{}, err
+		}
+		if len(shares) != 1 {
+			return Share{}, errors.New("more than one armored block")
+		}
+		return shares[0], nil
+	case strings.Contains(s, ":"):
+		return StringToShare(s)
+	case strings.Contains(s, "-"):
+		return Base32ToShare(s)
+	case isHex(s):
+		return CompactToShare(s)
+	}
+
+	data, err := base64.StdEncoding.DecodeString(s)
+	if err != nil {
+		return Share{}, errors.New("unrecognized part format")
+	}
+	if len(data) < 2 {
+		return Share{}, errors.New("share data is too short")
+	}
+	return Share{ID: data[0], Value: data[1:]}, nil
+}
+
+// ErrUppercaseHex is returned by CheckCanonicalHex for a hex value with
+// uppercase digits. The canonical form of hex values is lowercase, as
+// written by ShareToString and ShareToCompact; parsing accepts either case,
+// so "1:AB" and "1:ab" are the same share.
+var ErrUppercaseHex = errors.New("uppercase hex digit")
+
+// CheckCanonicalHex checks that the hex value of a share string in the text
+// form (ShareToString, also within a chunk) or the compact form is in
+// lowercase, e.g. to catch an accidental edit of a stored share. Other
+// encodings are not checked: base32 ignores case and base64 depends on it.
+// The error wraps ErrUppercaseHex and reports the 1-based position of the
+// first uppercase digit.
+func CheckCanonicalHex(s string) error {
+	s = strings.TrimSpace(s)
+	value, offset := s, 0
+	if i := strings.LastIndex(s, ":"); i >= 0 {
+		value, offset = s[i+1:], i+1
+	} else if !isHex(s) {
+		return nil
+	}
+
+	for i := 0; i < len(value); i++ {
+		if value[i] >= 'A' && value[i] <= 'F' {
+			return fmt.Errorf("%w at position %d, the canonical form is lowercase", ErrUppercaseHex, offset+i+1)
+		}
+	}
+	return nil
+}
+
+// CanonicalShare returns the canonical text form of a share string in any
+// encoding ParseShare accepts, so that two strings can be compared for the
+// same share
+func CanonicalShare(s string) (string, error) {
+	share, err := ParseShare(s)
+	if err != nil {
+		return "", err
+	}
+	return ShareToString(share), nil
+}
+
+// isHex reports whether s consists of hex digits only
+func isHex(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if _, ok := hexDigit(s[i]); !ok {
+			return false
+		}
+	}
+	return true
+}
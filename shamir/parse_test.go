@@ -0,0 +1,150 @@
+package shamir
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestParseShare(t *testing.T) {
+	share := Share{ID: 7, Value: []byte{0xde, 0xad, 0xbe, 0xef}}
+	raw := append([]byte{share.ID}, share.Value...)
+
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{"Text", "7:deadbeef"},
+		{"Versioned text", "v1:7:deadbeef"},
+		{"Base32", "7-32W353Y"},
+		{"Base32 lowercase", "7-32w353y"},
+		{"Compact", "07deadbeef"},
+		{"Compact uppercase", "07DEADBEEF"},
+		{"Base64", base64.StdEncoding.EncodeToString(raw)},
+		{"Armored", ArmorShare(share, 2)},
+		{"Surrounding whitespace", "  7:deadbeef\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parsed, err := ParseShare(tt.input)
+			if err != nil {
+				t.Fatalf("ParseShare(%q) failed: %v", tt.input, err)
+			}
+			if !parsed.Equal(share) {
+				t.Errorf("ParseShare(%q) = %+v, want %+v", tt.input, parsed, share)
+			}
+		})
+	}
+}
+
+func TestParseSharePrecedence(t *testing.T) {
+	// "abcd" is valid hex and valid base64; hex wins
+	parsed, err := ParseShare("abcd")
+	if err != nil {
+		t.Fatalf("ParseShare failed: %v", err)
+	}
+	if want := (Share{ID: 0xab, Value: []byte{0xcd}}); !parsed.Equal(want) {
+		t.Errorf("ParseShare(%q) = %+v, want compact %+v", "abcd", parsed, want)
+	}
+}
+
+func TestParseShareErrors(t *testing.T) {
+	share := Share{ID: 1, Value: []byte{1, 2}}
+	inputs := []string{
+		"",
+		"1:zz",
+		"07",
+		"not a share!",
+		"AQ==",
+		ArmorShare(share, 0) + ArmorShare(share, 0),
+		strings.TrimSuffix(ArmorShare(share, 0), armorEnd+"\n"),
+	}
+
+	for _, input := range inputs {
+		if _, err := ParseShare(input); err == nil {
+			t.Errorf("ParseShare(%q) should fail", input)
+		}
+	}
+}
+
+func TestCutArmor(t *testing.T) {
+	share := Share{ID: 1, Value: []byte{1, 2}}
+	input := "1:ab\n" + ArmorShare(share, 2) + "02cd,\n" + ArmorShare(share, 0) + "tail"
+
+	got := strings.Fields(CutArmor(input))
+	want := []string{"1:ab", "02cd,", "tail"}
+	if strings.Join(got, " ") != strings.Join(want, " ") {
+		t.Errorf("CutArmor() = %q, want %q", got, want)
+	}
+}
+
+func TestParseShares(t *testing.T) {
+	shares, err := ParseShares([]string{"1:ab", " ", "02cd", "c2/2:3:02", "c1/2:3:01"})
+	if err != nil {
+		t.Fatalf("ParseShares failed: %v", err)
+	}
+	if len(shares) != 3 || shares[0].ID != 1 || shares[1].ID != 2 || shares[2].ID != 3 {
+		t.Fatalf("ParseShares = %+v", shares)
+	}
+	if want := []byte{0x01, 0x02}; !bytes.Equal(shares[2].Value, want) {
+		t.Errorf("Joined chunk value = %x, want %x", shares[2].Value, want)
+	}
+
+	_, err = ParseShares([]string{"1:ab", "", " 3:xyz ", "4:cd"})
+	var perr *ParseError
+	if !errors.As(err, &perr) {
+		t.Fatalf("ParseShares returned %v, want a *ParseError", err)
+	}
+	if perr.Index != 3 || perr.Input != "3:xyz" || perr.Err == nil {
+		t.Errorf("ParseError = %+v, want index 3 and input %q", perr, "3:xyz")
+	}
+	if !strings.Contains(perr.Error(), "part 3") {
+		t.Errorf("ParseError.Error() = %q", perr.Error())
+	}
+}
+
+func TestCheckCanonicalHex(t *testing.T) {
+	for _, s := range []string{"7:deadbeef", "v2:7#Alice:deadbeef", "07deadbeef", "c1/2:7:dead", "7-32W353Y", "B62+7w==", ""} {
+		if err := CheckCanonicalHex(s); err != nil {
+			t.Errorf("CheckCanonicalHex(%q) = %v", s, err)
+		}
+	}
+
+	tests := []struct {
+		input string
+		err   string
+	}{
+		{"7:deADbeef", "uppercase hex digit at position 5, the canonical form is lowercase"},
+		{"v2:7#Alice:DEADBEEF", "uppercase hex digit at position 12, the canonical form is lowercase"},
+		{"07deadbeeF", "uppercase hex digit at position 10, the canonical form is lowercase"},
+		{"c1/2:7:Dead", "uppercase hex digit at position 8, the canonical form is lowercase"},
+	}
+	for _, tt := range tests {
+		err := CheckCanonicalHex(tt.input)
+		if !errors.Is(err, ErrUppercaseHex) || err.Error() != tt.err {
+			t.Errorf("CheckCanonicalHex(%q) = %v, want %q", tt.input, err, tt.err)
+		}
+		// Parsing alone still accepts the share
+		if IsChunk(tt.input) {
+			continue
+		}
+		if _, err := ParseShare(tt.input); err != nil {
+			t.Errorf("ParseShare(%q) failed: %v", tt.input, err)
+		}
+	}
+}
+
+func TestCanonicalShare(t *testing.T) {
+	for _, s := range []string{"7:DEADBEEF", "7:deadbeef", "v1:7:DeadBeef", "07DEADBEEF", "7-32w353y"} {
+		got, err := CanonicalShare(s)
+		if err != nil || got != "7:deadbeef" {
+			t.Errorf("CanonicalShare(%q) = %q, %v, want %q", s, got, err, "7:deadbeef")
+		}
+	}
+	if _, err := CanonicalShare("7:xyz"); err == nil {
+		t.Error("CanonicalShare of an invalid share succeeded")
+	}
+}
@@ -0,0 +1,30 @@
+package shamir
+
+import (
+	"context"
+	"fmt"
+)
+
+// CombineRaw reconstructs the first secretLen bytes shared by the parts
+// without looking at a checksum, padding or header. It is an escape hatch
+// for parts whose checksum byte was lost or that were created without one,
+// e.g. by another implementation, when the length of the secret is known.
+// Nothing verifies the result: wrong or mismatched parts yield wrong bytes
+// rather than an error.
+func CombineRaw(shares []Share, secretLen int) ([]byte, error) {
+	if len(shares) < 2 {
+		return nil, ErrTooFewShares
+	}
+	if secretLen < 0 {
+		return nil, ErrInvalidSecretLength
+	}
+	if secretLen > len(shares[0].Value) {
+		return nil, fmt.Errorf("secret length %d exceeds the part length %d", secretLen, len(shares[0].Value))
+	}
+
+	data, err := combineData(context.Background(), defaultField, shares)
+	if err != nil {
+		return nil, err
+	}
+	return data[:secretLen], nil
+}
@@ -0,0 +1,52 @@
+package shamir
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"errors"
+	"testing"
+)
+
+func TestCombineRaw(t *testing.T) {
+	// Parts of a raw secret, shared without a checksum
+	raw := []byte("no checksum here")
+	shares, err := splitData(context.Background(), defaultField, raw, defaultIDs(4), 3, rand.Reader)
+	if err != nil {
+		t.Fatalf("splitData failed: %v", err)
+	}
+
+	for _, length := range []int{len(raw), 5, 0} {
+		got, err := CombineRaw(shares[1:], length)
+		if err != nil {
+			t.Fatalf("CombineRaw(%d) failed: %v", length, err)
+		}
+		if !bytes.Equal(got, raw[:length]) {
+			t.Errorf("CombineRaw(%d) = %q, want %q", length, got, raw[:length])
+		}
+	}
+
+	// Regular parts whose checksum is ignored
+	secret := []byte("checksum dropped")
+	shares, err = Split(secret, 3, 2)
+	if err != nil {
+		t.Fatalf("Split failed: %v", err)
+	}
+	got, err := CombineRaw(shares[:2], len(secret))
+	if err != nil {
+		t.Fatalf("CombineRaw failed: %v", err)
+	}
+	if !bytes.Equal(got, secret) {
+		t.Errorf("CombineRaw = %q, want %q", got, secret)
+	}
+
+	if _, err := CombineRaw(shares[:2], len(secret)+2); err == nil {
+		t.Error("CombineRaw should reject a length beyond the parts")
+	}
+	if _, err := CombineRaw(shares[:2], -1); !errors.Is(err, ErrInvalidSecretLength) {
+		t.Errorf("CombineRaw(-1) returned %v, want %v", err, ErrInvalidSecretLength)
+	}
+	if _, err := CombineRaw(shares[:1], 1); !errors.Is(err, ErrTooFewShares) {
+		t.Errorf("CombineRaw with one part returned %v, want %v", err, ErrTooFewShares)
+	}
+}
@@ -0,0 +1,120 @@
+package shamir
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"io"
+)
+
+// Scheme bundles the parameters of a k-of-n sharing, so that they are set
+// once instead of being passed to every call. Fields left nil take the
+// defaults of the package level functions: the AES field, the XOR
+// checksum and crypto/rand.
+//
+// The zero Scheme cannot split, but combines shares of any threshold like
+// the package level Combine.
+type Scheme struct {
+	N, K int
+	// Field is the field shares are computed over; shares must be combined
+	// with the same field
+	Field *Field
+	// Checksum protects the secret; shares split with CRC32Checksum have
+	// version VersionCRC32. Nil means the checksum of the share version.
+	Checksum Checksum
+	// Rand is the source of the random coefficients
+	Rand io.Reader
+}
+
+// SplitOption configures how Split or a Scheme built by NewScheme splits
+// secrets. Options added later leave the signatures of both unchanged.
+type SplitOption func(*Scheme)
+
+// WithField makes the scheme compute shares over the field f
+func WithField(f *Field) SplitOption {
+	return func(s *Scheme) { s.Field = f }
+}
+
+// WithChecksum makes the scheme protect the secret with the checksum c
+func WithChecksum(c Checksum) SplitOption {
+	return func(s *Scheme) { s.Checksum = c }
+}
+
+// WithRand makes the scheme read the random coefficients from rng instead
+// of crypto/rand. As with SplitWithReader, shares split with a predictable
+// rng do not protect the secret.
+func WithRand(rng io.Reader) SplitOption {
+	return func(s *Scheme) { s.Rand = rng }
+}
+
+// NewScheme returns a scheme splitting secrets into n shares, k of which
+// recover them, configured by opts
+func NewScheme(n, k int, opts ...SplitOption) (*Scheme, error) {
+	if err := ValidateSplitParams(0, n, k); err != nil {
+		return nil, err
+	}
+	s := &Scheme{N: n, K: k}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s, nil
+}
+
+// Split divides a secret into s.N shares, s.K of which recover it
+func (s *Scheme) Split(secret []byte) ([]Share, error) {
+	return s.SplitContext(context.Background(), secret)
+}
+
+// SplitContext is like Split but aborts with ctx.Err() once the context is
+// done
+func (s *Scheme) SplitContext(ctx context.Context, secret []byte) ([]Share, error) {
+	if err := ValidateSplitParams(len(secret), s.N, s.K); err != nil {
+		return nil, err
+	}
+	c := s.Checksum
+	if c == nil {
+		c = XORChecksum{}
+	}
+
+	shares, err := splitData(ctx, s.field(), withChecksum(secret, c), defaultIDs(s.N), s.K, s.rand())
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := c.(CRC32Checksum); ok {
+		for i := range shares {
+			shares[i].Version = VersionCRC32
+		}
+	}
+	return shares, nil
+}
+
+// Combine recovers a secret from at least s.K shares split with the scheme.
+// A zero K accepts any number of shares from 2 on.
+func (s *Scheme) Combine(shares []Share) ([]byte, error) {
+	return s.CombineContext(context.Background(), shares)
+}
+
+// CombineContext is like Combine but aborts with ctx.Err() once the
+// context is done
+func (s *Scheme) CombineContext(ctx context.Context, shares []Share) ([]byte, error) {
+	if s.K > 0 && len(shares) < s.K {
+		return nil, fmt.Errorf("%w: %d parts required, only %d provided", ErrTooFewShares, s.K, len(shares))
+	}
+	return combineChecksum(ctx, s.field(), shares, s.Checksum)
+}
+
+// field returns the field of the scheme, the AES field by default
+func (s *Scheme) field() *Field {
+	if s.Field == nil {
+		return defaultField
+	}
+	return s.Field
+}
+
+// rand returns the random source of the scheme, crypto/rand by default
+func (s *Scheme) rand() io.Reader {
+	if s.Rand == nil {
+		return rand.Reader
+	}
+	return s.Rand
+}
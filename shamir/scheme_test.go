@@ -0,0 +1,150 @@
+package shamir
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestScheme(t *testing.T) {
+	secret := []byte("scheme secret")
+	f, err := NewField(0x1D)
+	if err != nil {
+		t.Fatalf("NewField failed: %v", err)
+	}
+	seed := bytes.Repeat([]byte{0x5a}, 4096)
+
+	tests := []struct {
+		name    string
+		opts    []SplitOption
+		version byte
+	}{
+		{"defaults", nil, 0},
+		{"CRC-32", []SplitOption{WithChecksum(CRC32Checksum{})}, VersionCRC32},
+		{"field", []SplitOption{WithField(f)}, 0},
+		{"all options", []SplitOption{WithField(f), WithChecksum(CRC32Checksum{}), WithRand(bytes.NewReader(seed))}, VersionCRC32},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := NewScheme(5, 3, tt.opts...)
+			if err != nil {
+				t.Fatalf("NewScheme failed: %v", err)
+			}
+			shares, err := s.Split(secret)
+			if err != nil {
+				t.Fatalf("Split failed: %v", err)
+			}
+			if len(shares) != 5 || shares[0].Version != tt.version {
+				t.Fatalf("Split returned %d shares of version %d", len(shares), shares[0].Version)
+			}
+
+			got, err := s.Combine([]Share{shares[4], shares[0], shares[2]})
+			if err != nil || !bytes.Equal(got, secret) {
+				t.Errorf("Combine = %q, %v", got, err)
+			}
+			if _, err := s.Combine(shares[:2]); !errors.Is(err, ErrTooFewShares) {
+				t.Errorf("Combine of 2 shares = %v, want ErrTooFewShares", err)
+			}
+		})
+	}
+}
+
+func TestSchemeRand(t *testing.T) {
+	seed := bytes.Repeat([]byte{1, 2, 3}, 100)
+	split := func() []Share {
+		s, err := NewScheme(3, 2, WithRand(bytes.NewReader(seed)))
+		if err != nil {
+			t.Fatalf("NewScheme failed: %v", err)
+		}
+		shares, err := s.Split([]byte("same"))
+		if err != nil {
+			t.Fatalf("Split failed: %v", err)
+		}
+		return shares
+	}
+	a, b := split(), split()
+	for i := range a {
+		if !a[i].Equal(b[i]) {
+			t.Errorf("Share %d differs with the same rng: %+v, %+v", i+1, a[i], b[i])
+		}
+	}
+	// The package level functions agree with the scheme
+	want, err := SplitWithReader([]byte("same"), 3, 2, bytes.NewReader(seed))
+	if err != nil || !want[0].Equal(a[0]) {
+		t.Errorf("SplitWithReader = %+v, %v, want %+v", want, err, a)
+	}
+}
+
+func TestSchemeErrors(t *testing.T) {
+	for _, tt := range []struct {
+		n, k int
+		want error
+	}{
+		{3, 1, ErrThresholdTooSmall},
+		{2, 3, ErrThresholdTooLarge},
+		{256, 3, ErrTooManyShares},
+	} {
+		if _, err := NewScheme(tt.n, tt.k); !errors.Is(err, tt.want) {
+			t.Errorf("NewScheme(%d, %d) = %v, want %v", tt.n, tt.k, err, tt.want)
+		}
+	}
+
+	var zero Scheme
+	if _, err := zero.Split([]byte("secret")); err == nil {
+		t.Error("The zero Scheme split a secret")
+	}
+	shares, err := Split([]byte("secret"), 3, 2)
+	if err != nil {
+		t.Fatalf("Split failed: %v", err)
+	}
+	if got, err := zero.Combine(shares); err != nil || string(got) != "secret" {
+		t.Errorf("The zero Scheme combined %q, %v", got, err)
+	}
+}
+
+func TestSplitOptions(t *testing.T) {
+	secret := []byte("split options")
+	f, err := NewField(0x1D)
+	if err != nil {
+		t.Fatalf("NewField failed: %v", err)
+	}
+	seed := bytes.Repeat([]byte{7, 1, 9}, 100)
+
+	tests := []struct {
+		name    string
+		opts    []SplitOption
+		combine func([]Share) ([]byte, error)
+	}{
+		{"none", nil, Combine},
+		{"rand", []SplitOption{WithRand(bytes.NewReader(seed))}, Combine},
+		{"checksum", []SplitOption{WithChecksum(CRC32Checksum{})}, Combine},
+		{"rand and checksum", []SplitOption{WithRand(bytes.NewReader(seed)), WithChecksum(CRC32Checksum{})}, Combine},
+		{"field and checksum", []SplitOption{WithField(f), WithChecksum(CRC32Checksum{})}, (&Scheme{Field: f}).Combine},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			shares, err := Split(secret, 4, 3, tt.opts...)
+			if err != nil {
+				t.Fatalf("Split failed: %v", err)
+			}
+			got, err := tt.combine(shares[1:])
+			if err != nil || !bytes.Equal(got, secret) {
+				t.Errorf("Combine = %q, %v", got, err)
+			}
+		})
+	}
+
+	// Options are applied in order, so a later one wins
+	a, err := Split(secret, 3, 2, WithRand(bytes.NewReader(nil)), WithRand(bytes.NewReader(seed)))
+	if err != nil {
+		t.Fatalf("Split with two rngs failed: %v", err)
+	}
+	b, err := SplitWithReader(secret, 3, 2, bytes.NewReader(seed))
+	if err != nil || !a[0].Equal(b[0]) {
+		t.Errorf("Split with the last rng = %+v, want %+v", a, b)
+	}
+
+	if _, err := Split(secret, 3, 2, WithRand(bytes.NewReader(nil))); !errors.Is(err, ErrRandomSource) {
+		t.Errorf("Split with an empty rng = %v, want ErrRandomSource", err)
+	}
+}
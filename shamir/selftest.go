@@ -0,0 +1,120 @@
+package shamir
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	mathrand "math/rand"
+)
+
+// SelfTestResult is the outcome of one check run by SelfTest
+type SelfTestResult struct {
+	Name string
+	Err  error
+}
+
+// selfTestSchemes are the (n, k) combinations split and combined by
+// SelfTest, including the boundaries k=2, k=n and n=255
+var selfTestSchemes = [][2]int{
+	{2, 2}, {3, 2}, {5, 3}, {10, 10}, {16, 7}, {255, 2}, {255, 128}, {255, 255},
+}
+
+// SelfTest checks that the field arithmetic and splitting and combining
+// work correctly on this platform. It returns one result per check.
+func SelfTest() []SelfTestResult {
+	return []SelfTestResult{
+		{"field multiplication table", checkMulTable()},
+		{"field inverses", checkInverses()},
+		{"split and combine", checkSchemes()},
+		{"empty secret", checkEmptySecret()},
+		{"parameter validation", checkValidation()},
+	}
+}
+
+// checkMulTable compares the multiplication table with bitwise multiplication
+func checkMulTable() error {
+	for a := 0; a < 256; a++ {
+		for b := 0; b < 256; b++ {
+			if got, want := gfMul(byte(a), byte(b)), gfMulPrimitive(byte(a), byte(b), DefaultPoly); got != want {
+				return fmt.Errorf("%d * %d = %d, want %d", a, b, got, want)
+			}
+		}
+	}
+	return nil
+}
+
+// checkInverses verifies a * inv(a) = 1 for all nonzero elements
+func checkInverses() error {
+	for a := 1; a < 256; a++ {
+		if product := gfMul(byte(a), gfInv(byte(a))); product != 1 {
+			return fmt.Errorf("%d * inv(%d) = %d, want 1", a, a, product)
+		}
+	}
+	return nil
+}
+
+// checkSchemes splits random secrets for each scheme and combines them
+// from a random subset of k shares
+func checkSchemes() error {
+	for _, scheme := range selfTestSchemes {
+		n, k := scheme[0], scheme[1]
+
+		secret := make([]byte, 1+mathrand.Intn(64))
+		if _, err := rand.Read(secret); err != nil {
+			return err
+		}
+
+		shares, err := Split(secret, n, k)
+		if err != nil {
+			return fmt.Errorf("n=%d k=%d: %v", n, k, err)
+		}
+
+		subset := make([]Share, k)
+		for i, j := range mathrand.Perm(n)[:k] {
+			subset[i] = shares[j]
+		}
+
+		recovered, err := Combine(subset)
+		if err != nil {
+			return fmt.Errorf("n=%d k=%d: %v", n, k, err)
+		}
+		if !SecretsEqual(recovered, secret) {
+			return fmt.Errorf("n=%d k=%d: recovered secret does not match", n, k)
+		}
+	}
+	return nil
+}
+
+// checkEmptySecret splits and combines an empty secret
+func checkEmptySecret() error {
+	shares, err := Split(nil, 3, 2)
+	if err != nil {
+		return err
+	}
+	recovered, err := Combine(shares[1:])
+	if err != nil {
+		return err
+	}
+	if len(recovered) != 0 {
+		return fmt.Errorf("recovered %d bytes, want 0", len(recovered))
+	}
+	return nil
+}
+
+// checkValidation verifies invalid parameters are rejected
+func checkValidation() error {
+	cases := []struct {
+		n, k int
+		want error
+	}{
+		{3, 1, ErrThresholdTooSmall},
+		{2, 3, ErrThresholdTooLarge},
+		{256, 2, ErrTooManyShares},
+	}
+	for _, c := range cases {
+		if _, err := Split([]byte("x"), c.n, c.k); !errors.Is(err, c.want) {
+			return fmt.Errorf("n=%d k=%d: got %v, want %v", c.n, c.k, err, c.want)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,15 @@
+package shamir
+
+import "testing"
+
+func TestSelfTest(t *testing.T) {
+	results := SelfTest()
+	if len(results) == 0 {
+		t.Fatal("SelfTest returned no results")
+	}
+	for _, r := range results {
+		if r.Err != nil {
+			t.Errorf("%s: %v", r.Name, r.Err)
+		}
+	}
+}
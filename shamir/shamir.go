@@ -1,43 +1,190 @@
 package shamir
 
 import (
+	"bytes"
+	"context"
 	"crypto/rand"
+	"crypto/subtle"
 	"errors"
 	"fmt"
+	"io"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
 )
 
-// Share represents one part of the secret
+// MaxShares is the maximum number of shares over GF(2^8), one per
+// nonzero field element
+const MaxShares = 255
+
+// Errors returned when validating split parameters
+var (
+	ErrThresholdTooSmall   = errors.New("k must be at least 2")
+	ErrThresholdTooLarge   = errors.New("n must be at least k")
+	ErrTooManyShares       = fmt.Errorf("n cannot be greater than %d", MaxShares)
+	ErrInvalidSecretLength = errors.New("secret length cannot be negative")
+)
+
+// ErrRandomSource is returned when the random number generator fails to
+// provide the random coefficients of a split
+var ErrRandomSource = errors.New("random number generator failed")
+
+// Errors returned when combining shares
+var (
+	ErrTooFewShares     = errors.New("minimum 2 parts required")
+	ErrChecksumMismatch = errors.New("checksum verification failed: unable to recover original string")
+	ErrExcessShares     = errors.New("more parts than the threshold")
+)
+
+// CurrentVersion is the newest share format version understood by this package
+const CurrentVersion = VersionFramed
+
+// Share represents one part of the secret. It implements
+// encoding.TextMarshaler, so it encodes to JSON as its text form, and
+// encoding.BinaryMarshaler for compact binary storage.
 type Share struct {
-	ID    byte   `json:"id"`
-	Value []byte `json:"value"`
+	// Version is the share format version. The original format, version 1,
+	// is stored as 0 so that shares built without a version keep it.
+	Version byte
+	ID      byte
+	Value   []byte
+	// Label optionally names the holder of the share, e.g. "alice". It is
+	// kept in the text form only and plays no part in combining.
+	Label string
+}
+
+// MarshalText encodes the share in the format produced by ShareToString
+func (s Share) MarshalText() ([]byte, error) {
+	return []byte(ShareToString(s)), nil
+}
+
+// UnmarshalText decodes a share in the format accepted by StringToShare
+func (s *Share) UnmarshalText(text []byte) error {
+	share, err := StringToShare(string(text))
+	if err != nil {
+		return err
+	}
+	*s = share
+	return nil
+}
+
+// Equal reports whether two shares have the same version, ID and value.
+// Labels are not compared.
+func (s Share) Equal(other Share) bool {
+	return s.Version == other.Version && s.ID == other.ID && bytes.Equal(s.Value, other.Value)
+}
+
+// SecretsEqual reports whether two secrets are equal, in time that depends
+// only on their lengths, so comparing a recovered secret against an expected
+// one does not leak how many leading bytes match
+func SecretsEqual(a, b []byte) bool {
+	return subtle.ConstantTimeCompare(a, b) == 1
+}
+
+// SortShares orders shares by ID in place
+func SortShares(shares []Share) {
+	sort.Slice(shares, func(i, j int) bool {
+		return shares[i].ID < shares[j].ID
+	})
+}
+
+// ctxCheckInterval is the number of secret bytes processed between context checks
+const ctxCheckInterval = 1024
+
+// ProgressFunc receives the number of bytes processed so far and the total
+type ProgressFunc func(done, total int)
+
+type progressKey struct{}
+
+// WithProgress returns a context that makes SplitContext and CombineContext
+// report their progress to fn
+func WithProgress(ctx context.Context, fn ProgressFunc) context.Context {
+	return context.WithValue(ctx, progressKey{}, fn)
+}
+
+// checkpoint checks ctx for cancellation and reports progress if requested
+func checkpoint(ctx context.Context, done, total int) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if fn, ok := ctx.Value(progressKey{}).(ProgressFunc); ok && fn != nil {
+		fn(done, total)
+	}
+	return nil
+}
+
+// TraceFunc receives the polynomial generated for one byte of the secret,
+// lowest degree first, and its values at the share IDs. The coefficients
+// are as sensitive as the secret itself.
+type TraceFunc func(byteIndex int, coeffs []byte, ids, values []byte)
+
+type traceKey struct{}
+
+// WithTrace returns a context that makes SplitContext and
+// SplitWithIDsContext pass every generated polynomial to fn. Secrets are
+// then always split on a single goroutine; SplitParallel ignores fn.
+func WithTrace(ctx context.Context, fn TraceFunc) context.Context {
+	return context.WithValue(ctx, traceKey{}, fn)
 }
 
-// Lookup tables for arithmetic in GF(2^8)
-var gfMulTable [256][256]byte
-var gfInvTable [256]byte
+// DefaultPoly is the reduction polynomial of the default field: the low
+// byte of the AES polynomial x^8 + x^4 + x^3 + x + 1
+const DefaultPoly = 0x1B
 
-func init() {
-	initGF()
+// Field is GF(2^8) defined by an irreducible polynomial, with lookup tables
+// for multiplication and inversion
+type Field struct {
+	poly byte
+	mul  [256][256]byte
+	inv  [256]byte
+}
+
+// defaultField is the field used by Split, Combine and the other package
+// level functions
+var defaultField = newField(DefaultPoly)
+
+// NewField builds GF(2^8) with the reduction polynomial x^8 + poly, where
+// poly holds the coefficients of the lower terms (0x1B for the AES
+// polynomial, 0x1D for the one common in Reed-Solomon codes). It returns an
+// error if the polynomial is not irreducible.
+func NewField(poly byte) (*Field, error) {
+	f := newField(poly)
+	for a := 1; a < 256; a++ {
+		if f.inv[a] == 0 {
+			return nil, fmt.Errorf("polynomial x^8 + 0x%02x is not irreducible", poly)
+		}
+	}
+	return f, nil
 }
 
-// initGF initializes tables for arithmetic in GF(2^8)
-func initGF() {
+// newField initializes tables for arithmetic in GF(2^8) with the given polynomial
+func newField(poly byte) *Field {
+	f := &Field{poly: poly}
+
 	// Initialize multiplication table
 	for a := 0; a < 256; a++ {
 		for b := 0; b < 256; b++ {
-			gfMulTable[a][b] = gfMulPrimitive(byte(a), byte(b))
+			f.mul[a][b] = gfMulPrimitive(byte(a), byte(b), poly)
 		}
 	}
 
 	// Initialize inverse elements table
-	gfInvTable[0] = 0
 	for i := 1; i < 256; i++ {
-		gfInvTable[i] = gfInvPrimitive(byte(i))
+		f.inv[i] = gfInvPrimitive(byte(i), poly)
 	}
+	return f
+}
+
+// Poly returns the lower terms of the field's reduction polynomial
+func (f *Field) Poly() byte {
+	return f.poly
 }
 
-// gfMulPrimitive performs multiplication in GF(2^8) without using tables
-func gfMulPrimitive(a, b byte) byte {
+// gfMulPrimitive performs multiplication in GF(2^8) without using tables,
+// reducing by x^8 + poly
+func gfMulPrimitive(a, b, poly byte) byte {
 	if a == 0 || b == 0 {
 		return 0
 	}
@@ -50,36 +197,37 @@ func gfMulPrimitive(a, b byte) byte {
 		highBit := (a & 0x80) != 0
 		a <<= 1
 		if highBit {
-			a ^= 0x1B // irreducible polynomial x^8 + x^4 + x^3 + x + 1
+			a ^= poly
 		}
 		b >>= 1
 	}
 	return result
 }
 
-// gfInvPrimitive calculates the inverse element in GF(2^8) using brute force
-func gfInvPrimitive(a byte) byte {
+// gfInvPrimitive calculates the inverse element in GF(2^8) using brute force.
+// It returns 0 if a has no inverse.
+func gfInvPrimitive(a, poly byte) byte {
 	if a == 0 {
 		return 0
 	}
 
 	// Try all possible values
 	for i := 1; i < 256; i++ {
-		if gfMulPrimitive(a, byte(i)) == 1 {
+		if gfMulPrimitive(a, byte(i), poly) == 1 {
 			return byte(i)
 		}
 	}
 	return 0
 }
 
-// gfMul performs multiplication in GF(2^8) using tables
+// gfMul performs multiplication in the default field using tables
 func gfMul(a, b byte) byte {
-	return gfMulTable[a][b]
+	return defaultField.mul[a][b]
 }
 
-// gfInv calculates the inverse element in GF(2^8) using tables
+// gfInv calculates the inverse element in the default field using tables
 func gfInv(a byte) byte {
-	return gfInvTable[a]
+	return defaultField.inv[a]
 }
 
 // gfAdd performs addition in GF(2^8) (XOR)
@@ -92,8 +240,14 @@ func gfSub(a, b byte) byte {
 	return a ^ b
 }
 
-// evaluatePolynomial calculates the value of a polynomial at point x
+// evaluatePolynomial calculates the value of a polynomial at point x in
+// the default field
 func evaluatePolynomial(coeffs []byte, x byte) byte {
+	return defaultField.evaluatePolynomial(coeffs, x)
+}
+
+// evaluatePolynomial calculates the value of a polynomial at point x
+func (f *Field) evaluatePolynomial(coeffs []byte, x byte) byte {
 	if len(coeffs) == 0 {
 		return 0
 	}
@@ -102,8 +256,8 @@ func evaluatePolynomial(coeffs []byte, x byte) byte {
 	xPow := byte(1)
 
 	for i := 1; i < len(coeffs); i++ {
-		xPow = gfMul(xPow, x)
-		result = gfAdd(result, gfMul(coeffs[i], xPow))
+		xPow = f.mul[xPow][x]
+		result = gfAdd(result, f.mul[coeffs[i]][xPow])
 	}
 
 	return result
@@ -118,155 +272,665 @@ func calculateChecksum(data []byte) byte {
 	return checksum
 }
 
-// Split divides a secret into n parts, where k parts are needed for recovery
-func Split(secret []byte, n, k int) ([]Share, error) {
-	if k < 2 {
-		return nil, errors.New("k must be at least 2")
+// SplitResult is the outcome of a split along with the parameters needed
+// to describe it, so it can be stored as a self-describing backup
+type SplitResult struct {
+	Shares    []Share `json:"shares"`
+	SecretLen int     `json:"secret_len"`
+	Threshold int     `json:"threshold"`
+}
+
+// Split divides a secret into n parts, where k parts are needed for
+// recovery. Without options it uses the AES field, the XOR checksum and
+// crypto/rand; opts change them as for NewScheme.
+func Split(secret []byte, n, k int, opts ...SplitOption) ([]Share, error) {
+	s, err := NewScheme(n, k, opts...)
+	if err != nil {
+		return nil, err
 	}
-	if n < k {
-		return nil, errors.New("n must be at least k")
+	return s.Split(secret)
+}
+
+// SplitDetailed is like Split but also reports the secret length and threshold
+func SplitDetailed(secret []byte, n, k int) (SplitResult, error) {
+	shares, err := SplitContext(context.Background(), secret, n, k)
+	if err != nil {
+		return SplitResult{}, err
+	}
+
+	return SplitResult{
+		Shares:    shares,
+		SecretLen: len(secret),
+		Threshold: k,
+	}, nil
+}
+
+// SplitContext is like Split but aborts with ctx.Err() once the context is done
+func SplitContext(ctx context.Context, secret []byte, n, k int) ([]Share, error) {
+	s, err := NewScheme(n, k)
+	if err != nil {
+		return nil, err
+	}
+	return s.SplitContext(ctx, secret)
+}
+
+// SplitWithReader is like Split but takes the random coefficients from rng
+// instead of crypto/rand. It is meant for tests and reproducible examples;
+// shares split with a predictable rng do not protect the secret. Reading is
+// sequential, so rng need not be safe for concurrent use.
+func SplitWithReader(secret []byte, n, k int, rng io.Reader) ([]Share, error) {
+	return SplitWithReaderContext(context.Background(), secret, n, k, rng)
+}
+
+// SplitWithReaderContext is like SplitWithReader but aborts with ctx.Err()
+// once the context is done
+func SplitWithReaderContext(ctx context.Context, secret []byte, n, k int, rng io.Reader) ([]Share, error) {
+	if err := ValidateSplitParams(len(secret), n, k); err != nil {
+		return nil, err
+	}
+	return splitWithIDs(ctx, defaultField, secret, defaultIDs(n), k, rng)
+}
+
+// Split is like the package level Split but works over the field f. The
+// parts can only be combined with the same field.
+func (f *Field) Split(secret []byte, n, k int) ([]Share, error) {
+	if err := ValidateSplitParams(len(secret), n, k); err != nil {
+		return nil, err
+	}
+	return splitWithIDs(context.Background(), f, secret, defaultIDs(n), k, rand.Reader)
+}
+
+// defaultIDs returns the share IDs 1..n
+func defaultIDs(n int) []byte {
+	ids := make([]byte, n)
+	for i := range ids {
+		ids[i] = byte(i + 1)
+	}
+	return ids
+}
+
+// SplitWithIDs is like Split but evaluates the polynomials at the given
+// distinct nonzero IDs instead of 1..n
+func SplitWithIDs(secret []byte, ids []byte, k int) ([]Share, error) {
+	return SplitWithIDsContext(context.Background(), secret, ids, k)
+}
+
+// SplitWithIDsContext is like SplitWithIDs but aborts with ctx.Err() once
+// the context is done
+func SplitWithIDsContext(ctx context.Context, secret []byte, ids []byte, k int) ([]Share, error) {
+	if err := ValidateSplitParams(len(secret), len(ids), k); err != nil {
+		return nil, err
+	}
+
+	var seen [256]bool
+	for _, id := range ids {
+		if id == 0 {
+			return nil, errors.New("share ID cannot be 0")
+		}
+		if seen[id] {
+			return nil, fmt.Errorf("duplicate share ID %d", id)
+		}
+		seen[id] = true
 	}
-	if n > 255 {
-		return nil, errors.New("n cannot be greater than 255")
+
+	return splitWithIDs(ctx, defaultField, secret, ids, k, rand.Reader)
+}
+
+// withChecksum returns a copy of the secret with its checksum tag appended,
+// so the caller's slice is never modified
+func withChecksum(secret []byte, c Checksum) []byte {
+	data := make([]byte, 0, len(secret)+c.Size())
+	data = append(data, secret...)
+	return append(data, c.Tag(secret)...)
+}
+
+// readRandom fills buf from rng, failing with ErrRandomSource unless the
+// whole buffer could be read
+func readRandom(rng io.Reader, buf []byte) error {
+	if _, err := io.ReadFull(rng, buf); err != nil {
+		return fmt.Errorf("%w: %v", ErrRandomSource, err)
 	}
+	return nil
+}
+
+// splitWithIDs splits a secret into one share per ID after parameters
+// have been validated, taking random coefficients from rng
+func splitWithIDs(ctx context.Context, f *Field, secret []byte, ids []byte, k int, rng io.Reader) ([]Share, error) {
+	return splitData(ctx, f, withChecksum(secret, XORChecksum{}), ids, k, rng)
+}
 
-	// Add checksum to the secret
-	checksum := calculateChecksum(secret)
-	secretWithChecksum := append(secret, checksum)
+// splitData splits a secret with its checksum already appended into one
+// share per ID
+func splitData(ctx context.Context, f *Field, secretWithChecksum []byte, ids []byte, k int, rng io.Reader) ([]Share, error) {
+	n := len(ids)
 
+	// The parallel path reads crypto/rand from several goroutines
+	trace, _ := ctx.Value(traceKey{}).(TraceFunc)
+	if trace == nil && rng == rand.Reader && len(secretWithChecksum) >= parallelMinSize && runtime.GOMAXPROCS(0) > 1 {
+		return splitParallel(ctx, f, secretWithChecksum, ids, k, runtime.GOMAXPROCS(0))
+	}
+
+	// All shares and their values are allocated up front, so the loop
+	// below only fills in bytes
 	shares := make([]Share, n)
+	for i, id := range ids {
+		shares[i] = Share{ID: id, Value: make([]byte, len(secretWithChecksum))}
+	}
+
+	// Random coefficients are read for up to ctxCheckInterval polynomials at
+	// a time instead of one read per polynomial
+	random := make([]byte, (k-1)*min(ctxCheckInterval, len(secretWithChecksum)))
+	coeffs := make([]byte, k)
+	var pending []byte
 
 	// For each byte of the secret (including checksum), create a separate polynomial
 	for byteIndex := 0; byteIndex < len(secretWithChecksum); byteIndex++ {
-		// Create random coefficients for polynomial of degree k-1
-		coeffs := make([]byte, k)
-		coeffs[0] = secretWithChecksum[byteIndex] // constant term is the secret byte
+		if byteIndex%ctxCheckInterval == 0 {
+			if err := checkpoint(ctx, byteIndex, len(secretWithChecksum)); err != nil {
+				return nil, err
+			}
 
-		// Generate random coefficients for other degrees
-		for i := 1; i < k; i++ {
-			randomBytes := make([]byte, 1)
-			rand.Read(randomBytes)
-			coeffs[i] = randomBytes[0]
+			chunk := min(ctxCheckInterval, len(secretWithChecksum)-byteIndex)
+			pending = random[:(k-1)*chunk]
+			if err := readRandom(rng, pending); err != nil {
+				return nil, err
+			}
 		}
 
+		// Polynomial of degree k-1: the constant term is the secret byte,
+		// the other coefficients are random
+		coeffs[0] = secretWithChecksum[byteIndex]
+		copy(coeffs[1:], pending[:k-1])
+		pending = pending[k-1:]
+
 		// Calculate polynomial values for each part
-		for i := 0; i < n; i++ {
-			shareID := byte(i + 1) // Share ID (from 1 to n)
-			shareValue := evaluatePolynomial(coeffs, shareID)
-
-			if byteIndex == 0 {
-				shares[i] = Share{
-					ID:    shareID,
-					Value: make([]byte, len(secretWithChecksum)),
-				}
+		for i := range shares {
+			shares[i].Value[byteIndex] = f.evaluatePolynomial(coeffs, shares[i].ID)
+		}
+
+		if trace != nil {
+			values := make([]byte, n)
+			for i := range shares {
+				values[i] = shares[i].Value[byteIndex]
 			}
-			shares[i].Value[byteIndex] = shareValue
+			trace(byteIndex, coeffs, ids, values)
 		}
 	}
 
+	if err := checkpoint(ctx, len(secretWithChecksum), len(secretWithChecksum)); err != nil {
+		return nil, err
+	}
+
 	return shares, nil
 }
 
+// ValidateSplitParams checks the parameters of a split without generating
+// any shares, returning the same error Split would
+func ValidateSplitParams(secretLen, n, k int) error {
+	if secretLen < 0 {
+		return ErrInvalidSecretLength
+	}
+	if k < 2 {
+		return ErrThresholdTooSmall
+	}
+	if n < k {
+		return ErrThresholdTooLarge
+	}
+	if n > MaxShares {
+		return ErrTooManyShares
+	}
+	return nil
+}
+
 // Combine recovers a secret from parts
 func Combine(shares []Share) ([]byte, error) {
+	return CombineContext(context.Background(), shares)
+}
+
+// CombineContext is like Combine but aborts with ctx.Err() once the context is done
+func CombineContext(ctx context.Context, shares []Share) ([]byte, error) {
+	var s Scheme
+	return s.CombineContext(ctx, shares)
+}
+
+// CombineReport is like Combine but also reports the IDs of the shares that
+// contributed to the secret, e.g. for audit logs. Repeated copies of the
+// same share are used once; shares with the same ID but different values
+// are rejected.
+func CombineReport(shares []Share) (secret []byte, usedIDs []byte, err error) {
+	used, err := uniqueShares(shares)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	secret, err = Combine(used)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	usedIDs = make([]byte, len(used))
+	for i, share := range used {
+		usedIDs[i] = share.ID
+	}
+	return secret, usedIDs, nil
+}
+
+// CombineExact is like Combine but requires exactly k parts, the threshold
+// of the split. More parts are rejected with ErrExcessShares before any
+// interpolation, for callers who do not want more part material than
+// necessary gathered in one place.
+func CombineExact(shares []Share, k int) ([]byte, error) {
+	if k < 2 {
+		return nil, ErrThresholdTooSmall
+	}
+	if len(shares) > k {
+		return nil, fmt.Errorf("%w: %d parts given for a threshold of %d", ErrExcessShares, len(shares), k)
+	}
+	if len(shares) < k {
+		return nil, fmt.Errorf("%d parts required, only %d given", k, len(shares))
+	}
+	return Combine(shares)
+}
+
+// DedupShares prepares shares collected in any order for Combine: it
+// returns them sorted by ID with repeated copies of the same share removed.
+// Shares with the same ID but different values are rejected. Gaps in the
+// IDs do not matter, as any k shares recover the secret.
+func DedupShares(shares []Share) ([]Share, error) {
+	unique, err := uniqueShares(shares)
+	if err != nil {
+		return nil, err
+	}
+	SortShares(unique)
+	return unique, nil
+}
+
+// uniqueShares returns the first copy of each share, keeping their order,
+// and rejects shares with the same ID but different values
+func uniqueShares(shares []Share) ([]Share, error) {
+	unique := make([]Share, 0, len(shares))
+	seen := make(map[byte]Share, len(shares))
+	for _, share := range shares {
+		if prev, ok := seen[share.ID]; ok {
+			if !prev.Equal(share) {
+				return nil, fmt.Errorf("conflicting shares with ID %d", share.ID)
+			}
+			continue
+		}
+		seen[share.ID] = share
+		unique = append(unique, share)
+	}
+	return unique, nil
+}
+
+// Combine is like the package level Combine for parts created over the
+// field f
+func (f *Field) Combine(shares []Share) ([]byte, error) {
+	return combine(context.Background(), f, shares)
+}
+
+// combine recovers a secret from parts created over the field f, verifying
+// it with the checksum of their version
+func combine(ctx context.Context, f *Field, shares []Share) ([]byte, error) {
+	return combineChecksum(ctx, f, shares, nil)
+}
+
+// combineChecksum recovers a secret from parts created over the field f and
+// verifies it with c, or with the checksum of their version when c is nil
+func combineChecksum(ctx context.Context, f *Field, shares []Share, c Checksum) ([]byte, error) {
+	secretWithChecksum, err := combineData(ctx, f, shares)
+	if err != nil {
+		return nil, err
+	}
+
+	// Verify checksum
+	if c == nil {
+		c = versionChecksum(shares[0].Version)
+	}
+	secret, tag, ok := cutChecksum(secretWithChecksum, c.Size())
+	if !ok {
+		return nil, fmt.Errorf("recovered %d bytes, too short for a %d-byte checksum", len(secretWithChecksum), c.Size())
+	}
+	if !c.Verify(secret, tag) {
+		return nil, fmt.Errorf("%w (%d bytes recovered)", ErrChecksumMismatch, len(secretWithChecksum))
+	}
+
+	switch shares[0].Version {
+	case VersionPadded:
+		return Unpad(secret)
+	case VersionHeader:
+		secret, _, err := parseHeader(secret, len(shares))
+		return secret, err
+	case VersionFramed:
+		return parseFrame(secret)
+	}
+	return secret, nil
+}
+
+// cutChecksum splits recovered data into the secret and the checksum of
+// size bytes at its end; ok is false when data is shorter than the checksum
+func cutChecksum(data []byte, size int) (secret, tag []byte, ok bool) {
+	if size < 0 || len(data) < size {
+		return nil, nil, false
+	}
+	return data[:len(data)-size], data[len(data)-size:], true
+}
+
+// combineData checks that the parts match and interpolates every byte of
+// their values, returning the shared data including the checksum
+func combineData(ctx context.Context, f *Field, shares []Share) ([]byte, error) {
 	if len(shares) < 2 {
-		return nil, errors.New("minimum 2 parts required")
+		return nil, ErrTooFewShares
+	}
+
+	for i, share := range shares {
+		if len(share.Value) == 0 {
+			return nil, fmt.Errorf("share %d has empty value", i+1)
+		}
+	}
+
+	if err := checkShareIDs(shares); err != nil {
+		return nil, err
 	}
 
-	// Check that all parts have the same length
+	// Check that all parts have the same length and version
 	secretLen := len(shares[0].Value)
 	for i := 1; i < len(shares); i++ {
 		if len(shares[i].Value) != secretLen {
 			return nil, errors.New("all parts must have the same length")
 		}
+		if shares[i].Version != shares[0].Version {
+			return nil, errors.New("all parts must have the same version")
+		}
 	}
 
 	secretWithChecksum := make([]byte, secretLen)
 
+	// The Lagrange basis at x=0 depends only on the share IDs, which are the
+	// same for every byte, so it is computed once
+	xs := make([]byte, len(shares))
+	for i, share := range shares {
+		xs[i] = share.ID
+	}
+	basis := f.lagrangeCoefficients(xs, 0)
+
 	// Recover each byte of the secret separately
 	for byteIndex := 0; byteIndex < secretLen; byteIndex++ {
-		// Collect points for interpolation
-		xs := make([]byte, len(shares))
-		ys := make([]byte, len(shares))
+		if byteIndex%ctxCheckInterval == 0 {
+			if err := checkpoint(ctx, byteIndex, secretLen); err != nil {
+				return nil, err
+			}
+		}
 
+		// The constant term is the dot product of the values and the basis
+		var result byte
 		for i, share := range shares {
-			xs[i] = share.ID
-			ys[i] = share.Value[byteIndex]
+			result = gfAdd(result, f.mul[share.Value[byteIndex]][basis[i]])
 		}
-
-		// Use Lagrange interpolation to recover the constant term
-		secretWithChecksum[byteIndex] = lagrangeInterpolation(xs, ys)
+		secretWithChecksum[byteIndex] = result
 	}
 
-	// Verify checksum
-	if len(secretWithChecksum) < 1 {
-		return nil, errors.New("recovered data is too short")
+	if err := checkpoint(ctx, secretLen, secretLen); err != nil {
+		return nil, err
 	}
+	return secretWithChecksum, nil
+}
 
-	secret := secretWithChecksum[:len(secretWithChecksum)-1]
-	expectedChecksum := secretWithChecksum[len(secretWithChecksum)-1]
-	actualChecksum := calculateChecksum(secret)
-
-	if expectedChecksum != actualChecksum {
-		return nil, errors.New("checksum verification failed: unable to recover original string")
+// checkShareIDs checks that the shares have distinct nonzero IDs, which the
+// interpolation in GF(2^8) relies on: a share at x=0 would be taken for the
+// secret, and the Lagrange coefficients of repeated IDs are 0, which turns
+// a set of copies of one share into an all-zero secret passing the XOR
+// checksum. Share.ID is a byte, so every ID is already an element of the
+// field; wider IDs, as of Share16, must not be narrowed to reach here.
+func checkShareIDs(shares []Share) error {
+	var seen [256]bool
+	for _, share := range shares {
+		if share.ID == 0 {
+			return errors.New("share ID cannot be 0")
+		}
+		if seen[share.ID] {
+			return fmt.Errorf("duplicate share ID %d", share.ID)
+		}
+		seen[share.ID] = true
 	}
-
-	return secret, nil
+	return nil
 }
 
 // lagrangeInterpolation recovers the constant term of the polynomial (value at point 0)
 func lagrangeInterpolation(xs, ys []byte) byte {
+	return interpolateAt(xs, ys, 0)
+}
+
+// interpolateAt calculates the value at point x of the polynomial passing
+// through the points (xs[i], ys[i])
+func interpolateAt(xs, ys []byte, x byte) byte {
 	var result byte
+	for i, coeff := range defaultField.lagrangeCoefficients(xs, x) {
+		result = gfAdd(result, gfMul(ys[i], coeff))
+	}
+	return result
+}
+
+// lagrangeCoefficients calculates the Lagrange basis polynomials at point x
+// for the given x-coordinates. Coefficients of duplicate coordinates are 0.
+func (f *Field) lagrangeCoefficients(xs []byte, x byte) []byte {
+	coeffs := make([]byte, len(xs))
 
 	for i := 0; i < len(xs); i++ {
 		var numerator, denominator byte = 1, 1
 
 		for j := 0; j < len(xs); j++ {
 			if i != j {
-				numerator = gfMul(numerator, xs[j])
-				denominator = gfMul(denominator, gfAdd(xs[i], xs[j]))
+				numerator = f.mul[numerator][gfSub(x, xs[j])]
+				denominator = f.mul[denominator][gfAdd(xs[i], xs[j])]
 			}
 		}
 
 		if denominator != 0 {
-			lagrangeBasis := gfMul(numerator, gfInv(denominator))
-			result = gfAdd(result, gfMul(ys[i], lagrangeBasis))
+			coeffs[i] = f.mul[numerator][f.inv[denominator]]
 		}
 	}
 
-	return result
+	return coeffs
+}
+
+// IssueShare mints an additional share with ID newID that is compatible with
+// the existing set. It reconstructs the polynomials from the first k shares
+// and evaluates them at newID, so the secret itself is never assembled.
+func IssueShare(shares []Share, newID byte, k int) (Share, error) {
+	if k < 2 {
+		return Share{}, ErrThresholdTooSmall
+	}
+	if len(shares) < k {
+		return Share{}, fmt.Errorf("at least %d shares required to issue a new one", k)
+	}
+	if newID == 0 {
+		return Share{}, errors.New("share ID cannot be 0")
+	}
+
+	for _, share := range shares {
+		if share.ID == newID {
+			return Share{}, fmt.Errorf("share ID %d is already used", newID)
+		}
+	}
+
+	base := shares[:k]
+	if err := checkShareIDs(base); err != nil {
+		return Share{}, err
+	}
+	valueLen := len(base[0].Value)
+	xs := make([]byte, k)
+	for i, share := range base {
+		if len(share.Value) == 0 {
+			return Share{}, fmt.Errorf("share %d has empty value", i+1)
+		}
+		if len(share.Value) != valueLen {
+			return Share{}, errors.New("all parts must have the same length")
+		}
+		xs[i] = share.ID
+	}
+
+	issued := Share{Version: base[0].Version, ID: newID, Value: make([]byte, valueLen)}
+	basis := defaultField.lagrangeCoefficients(xs, newID)
+	for byteIndex := 0; byteIndex < valueLen; byteIndex++ {
+		var result byte
+		for i, share := range base {
+			result = gfAdd(result, gfMul(share.Value[byteIndex], basis[i]))
+		}
+		issued.Value[byteIndex] = result
+	}
+
+	return issued, nil
 }
 
-// ShareToString converts a Share to string representation
+// ShareToString converts a Share to string representation. A label follows
+// the ID after a "#", as in "1#alice:a1b2".
 func ShareToString(share Share) string {
-	return fmt.Sprintf("%d:%x", share.ID, share.Value)
+	id := strconv.Itoa(int(share.ID))
+	if share.Label != "" {
+		id += "#" + share.Label
+	}
+	if share.Version > 1 {
+		return fmt.Sprintf("v%d:%s:%x", share.Version, id, share.Value)
+	}
+	return fmt.Sprintf("%s:%x", id, share.Value)
+}
+
+// ValidateLabel checks that a share label is non-empty and consists of
+// letters, digits, "_", "-" and "." only, so that it survives the text
+// form and lists of shares separated by commas or spaces
+func ValidateLabel(label string) error {
+	if label == "" {
+		return errors.New("empty label")
+	}
+	for _, r := range label {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) && !strings.ContainsRune("_-.", r) {
+			return fmt.Errorf("invalid character %q in label %q", r, label)
+		}
+	}
+	return nil
 }
 
-// StringToShare converts string representation to Share
+// StringToShare converts string representation to Share. A leading version
+// token such as "v1:" is optional; without it the share is version 1.
 func StringToShare(s string) (Share, error) {
-	var share Share
-	var hexValue string
+	var version byte
+	var prefix string
+	offset := 0
+	if strings.HasPrefix(s, "v") {
+		token, rest, ok := strings.Cut(s, ":")
+		if !ok {
+			return Share{}, errors.New("invalid part format")
+		}
+		v, err := strconv.ParseUint(token[1:], 10, 8)
+		if err != nil || v == 0 {
+			return Share{}, fmt.Errorf("invalid share version %q", token)
+		}
+		if v > CurrentVersion {
+			return Share{}, fmt.Errorf("unsupported share version %d", v)
+		}
+		if v > 1 {
+			version = byte(v)
+		}
+		s, prefix, offset = rest, token+":", len(token)+1
+	}
 
-	n, err := fmt.Sscanf(s, "%d:%s", &share.ID, &hexValue)
-	if err != nil || n != 2 {
+	parts := strings.SplitN(s, ":", 2)
+	if len(parts) != 2 {
 		return Share{}, errors.New("invalid part format")
 	}
 
+	idPart, label, labeled := strings.Cut(parts[0], "#")
+	if labeled {
+		if err := ValidateLabel(label); err != nil {
+			return Share{}, err
+		}
+	}
+
+	id, err := strconv.ParseUint(idPart, 10, 8)
+	if err != nil {
+		return Share{}, invalidIDError(prefix, parts[0], parts[1])
+	}
+
+	value, err := decodeHex(parts[1], offset+len(parts[0])+1)
+	if err != nil {
+		return Share{}, err
+	}
+
+	return Share{Version: version, ID: byte(id), Value: value, Label: label}, nil
+}
+
+// invalidIDError returns the error for a share string whose ID does not
+// parse. When the ID and value look swapped, as in "abcd:1", the error
+// suggests the share string with them in the right order.
+func invalidIDError(prefix, id, value string) error {
+	if _, err := strconv.ParseUint(value, 10, 8); err == nil {
+		if _, err := decodeHex(id, 0); err == nil {
+			return fmt.Errorf("invalid part format (did you mean %s%s:%s?)", prefix, value, id)
+		}
+	}
+	return errors.New("invalid part format")
+}
+
+// ShareToCompact converts a Share to its compact representation hex(ID||Value),
+// e.g. "03a1b2c3". All shares of a secret have the same width and no colon,
+// which makes them easier to lay out in tables or grids. The format has no
+// room for a version, so shares are always read back as version 1.
+func ShareToCompact(share Share) string {
+	return fmt.Sprintf("%02x%x", share.ID, share.Value)
+}
+
+// CompactToShare converts the compact representation produced by
+// ShareToCompact to a Share
+func CompactToShare(s string) (Share, error) {
+	data, err := decodeHex(s, 0)
+	if err != nil {
+		return Share{}, err
+	}
+	if len(data) < 2 {
+		return Share{}, errors.New("compact part is too short")
+	}
+	return Share{ID: data[0], Value: data[1:]}, nil
+}
+
+// decodeHex decodes the hex value of a share string. offset is the position
+// of the value within the share string, used to report 1-based positions.
+func decodeHex(hexValue string, offset int) ([]byte, error) {
+	if len(hexValue) == 0 {
+		return nil, errors.New("empty hex value")
+	}
+
+	// Every character must be a hex digit, so trailing content
+	// is rejected rather than silently dropped
+	for i := 0; i < len(hexValue); i++ {
+		if _, ok := hexDigit(hexValue[i]); !ok {
+			return nil, fmt.Errorf("invalid hex digit at position %d", offset+i+1)
+		}
+	}
+
 	// Check if hex string has even length
 	if len(hexValue)%2 != 0 {
-		return Share{}, errors.New("invalid hex format")
+		return nil, fmt.Errorf("hex value has odd length (%d)", len(hexValue))
 	}
 
 	value := make([]byte, len(hexValue)/2)
 	for i := 0; i < len(hexValue); i += 2 {
-		var b byte
-		n, err := fmt.Sscanf(hexValue[i:i+2], "%02x", &b)
-		if err != nil || n != 1 {
-			return Share{}, errors.New("invalid hex format")
-		}
-		value[i/2] = b
+		hi, _ := hexDigit(hexValue[i])
+		lo, _ := hexDigit(hexValue[i+1])
+		value[i/2] = hi<<4 | lo
 	}
+	return value, nil
+}
 
-	share.Value = value
-	return share, nil
+// hexDigit converts a single hex character to its value
+func hexDigit(c byte) (byte, bool) {
+	switch {
+	case c >= '0' && c <= '9':
+		return c - '0', true
+	case c >= 'a' && c <= 'f':
+		return c - 'a' + 10, true
+	case c >= 'A' && c <= 'F':
+		return c - 'A' + 10, true
+	}
+	return 0, false
 }
@@ -2,7 +2,12 @@ package shamir
 
 import (
 	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"runtime"
 	"testing"
 )
 
@@ -181,14 +186,138 @@ func TestSplitValidation(t *testing.T) {
 	}
 }
 
+func TestValidateSplitParams(t *testing.T) {
+	tests := []struct {
+		name      string
+		secretLen int
+		n, k      int
+		wantErr   error
+	}{
+		{"Valid parameters", 10, 5, 3, nil},
+		{"Empty secret", 0, 2, 2, nil},
+		{"Maximum shares", 10, MaxShares, MaxShares, nil},
+		{"Negative length", -1, 5, 3, ErrInvalidSecretLength},
+		{"k too small", 10, 5, 1, ErrThresholdTooSmall},
+		{"n less than k", 10, 3, 5, ErrThresholdTooLarge},
+		{"n too large", 10, MaxShares + 1, 2, ErrTooManyShares},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateSplitParams(tt.secretLen, tt.n, tt.k)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("ValidateSplitParams() error = %v, want %v", err, tt.wantErr)
+			}
+
+			// Split must agree with the validation for real secrets
+			if tt.secretLen >= 0 {
+				_, splitErr := Split(make([]byte, tt.secretLen), tt.n, tt.k)
+				if !errors.Is(splitErr, tt.wantErr) {
+					t.Errorf("Split() error = %v, want %v", splitErr, tt.wantErr)
+				}
+			}
+		})
+	}
+}
+
+func TestSplitWithIDs(t *testing.T) {
+	secret := []byte("custom ids")
+	ids := []byte{10, 20, 30, 255}
+
+	shares, err := SplitWithIDs(secret, ids, 3)
+	if err != nil {
+		t.Fatalf("SplitWithIDs failed: %v", err)
+	}
+
+	for i, share := range shares {
+		if share.ID != ids[i] {
+			t.Errorf("Share %d has ID %d, want %d", i, share.ID, ids[i])
+		}
+	}
+
+	recovered, err := Combine([]Share{shares[3], shares[0], shares[2]})
+	if err != nil {
+		t.Fatalf("Combine failed: %v", err)
+	}
+	if !bytes.Equal(recovered, secret) {
+		t.Errorf("Recovery failed: got %q, want %q", recovered, secret)
+	}
+
+	invalid := []struct {
+		name string
+		ids  []byte
+		k    int
+	}{
+		{"Zero ID", []byte{0, 1, 2}, 2},
+		{"Duplicate ID", []byte{1, 2, 2}, 2},
+		{"Too few IDs", []byte{1, 2}, 3},
+	}
+
+	for _, tt := range invalid {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := SplitWithIDs(secret, tt.ids, tt.k); err == nil {
+				t.Errorf("SplitWithIDs(%v, k=%d) should fail", tt.ids, tt.k)
+			}
+		})
+	}
+}
+
+func TestIssueShare(t *testing.T) {
+	secret := []byte("new member")
+	n, k := 4, 3
+
+	shares, err := Split(secret, n, k)
+	if err != nil {
+		t.Fatalf("Split failed: %v", err)
+	}
+
+	issued, err := IssueShare(shares, 42, k)
+	if err != nil {
+		t.Fatalf("IssueShare failed: %v", err)
+	}
+	if issued.ID != 42 {
+		t.Errorf("Issued share ID = %d, want 42", issued.ID)
+	}
+
+	// The issued share must combine with any k-1 original shares
+	for _, pair := range [][2]int{{0, 1}, {1, 3}, {2, 3}} {
+		recovered, err := Combine([]Share{shares[pair[0]], issued, shares[pair[1]]})
+		if err != nil {
+			t.Fatalf("Combine with issued share failed: %v", err)
+		}
+		if !bytes.Equal(recovered, secret) {
+			t.Errorf("Recovery with issued share failed: got %q, want %q", recovered, secret)
+		}
+	}
+
+	invalid := []struct {
+		name   string
+		shares []Share
+		newID  byte
+	}{
+		{"ID already used", shares, 2},
+		{"Zero ID", shares, 0},
+		{"Too few shares", shares[:2], 42},
+		{"Duplicate shares", []Share{shares[0], shares[0], shares[1]}, 42},
+	}
+
+	for _, tt := range invalid {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := IssueShare(tt.shares, tt.newID, k); err == nil {
+				t.Error("IssueShare should fail")
+			}
+		})
+	}
+}
+
 func TestCombineValidation(t *testing.T) {
 	// Test with insufficient shares
 	shares := []Share{
 		{ID: 1, Value: []byte{0x12}},
 	}
 	_, err := Combine(shares)
-	if err == nil {
-		t.Error("Combine should fail with only 1 share")
+	if err != ErrTooFewShares {
+		t.Errorf("Combine error = %v, want ErrTooFewShares", err)
 	}
 
 	// Test with mismatched share lengths
@@ -200,6 +329,203 @@ func TestCombineValidation(t *testing.T) {
 	if err == nil {
 		t.Error("Combine should fail with mismatched share lengths")
 	}
+
+	// Test with an empty share value
+	shares = []Share{
+		{ID: 1, Value: []byte{}},
+		{ID: 2, Value: []byte{0x56}},
+	}
+	_, err = Combine(shares)
+	if err == nil || err.Error() != "share 1 has empty value" {
+		t.Errorf("Combine error = %v, want %q", err, "share 1 has empty value")
+	}
+
+	shares = []Share{
+		{ID: 1, Value: []byte{0x12}},
+		{ID: 2, Value: nil},
+	}
+	_, err = Combine(shares)
+	if err == nil || err.Error() != "share 2 has empty value" {
+		t.Errorf("Combine error = %v, want %q", err, "share 2 has empty value")
+	}
+}
+
+func TestCombineChecksumBoundary(t *testing.T) {
+	// One byte of XOR checksum and four of CRC-32 are the shortest values
+	// recovering a secret, the empty one
+	for _, c := range []Checksum{XORChecksum{}, CRC32Checksum{}} {
+		shares, err := SplitWithChecksum(nil, 3, 2, c)
+		if err != nil {
+			t.Fatalf("SplitWithChecksum failed: %v", err)
+		}
+		if len(shares[0].Value) != c.Size() {
+			t.Fatalf("Share of an empty secret has %d bytes, want %d", len(shares[0].Value), c.Size())
+		}
+		secret, err := Combine(shares[:2])
+		if err != nil || len(secret) != 0 {
+			t.Errorf("Combine = %q, %v, want an empty secret", secret, err)
+		}
+	}
+
+	// CRC-32 shares shorter than their checksum
+	shares := []Share{
+		{Version: VersionCRC32, ID: 1, Value: []byte{1, 2, 3}},
+		{Version: VersionCRC32, ID: 2, Value: []byte{4, 5, 6}},
+	}
+	_, err := Combine(shares)
+	if err == nil || err.Error() != "recovered 3 bytes, too short for a 4-byte checksum" {
+		t.Errorf("Combine error = %v", err)
+	}
+
+	// A failed checksum reports how much was recovered
+	shares = []Share{{ID: 1, Value: []byte{1, 2, 3}}, {ID: 2, Value: []byte{4, 5, 7}}}
+	_, err = Combine(shares)
+	if !errors.Is(err, ErrChecksumMismatch) || !bytes.Contains([]byte(err.Error()), []byte("(3 bytes recovered)")) {
+		t.Errorf("Combine error = %v, want ErrChecksumMismatch with the recovered length", err)
+	}
+}
+
+func TestCutChecksum(t *testing.T) {
+	for _, tt := range []struct {
+		data   []byte
+		size   int
+		secret string
+		ok     bool
+	}{
+		{[]byte("abc"), 1, "ab", true},
+		{[]byte("abc"), 3, "", true},
+		{[]byte("abc"), 4, "", false},
+		{nil, 0, "", true},
+		{nil, 1, "", false},
+		{[]byte("abc"), -1, "", false},
+	} {
+		secret, tag, ok := cutChecksum(tt.data, tt.size)
+		if ok != tt.ok || string(secret) != tt.secret || (ok && len(tag) != tt.size) {
+			t.Errorf("cutChecksum(%q, %d) = %q, %q, %v", tt.data, tt.size, secret, tag, ok)
+		}
+	}
+}
+
+func TestCombineReport(t *testing.T) {
+	secret := []byte("audited secret")
+	shares, err := Split(secret, 5, 3)
+	if err != nil {
+		t.Fatalf("Split failed: %v", err)
+	}
+
+	input := []Share{shares[4], shares[1], shares[4], shares[2]}
+	recovered, ids, err := CombineReport(input)
+	if err != nil {
+		t.Fatalf("CombineReport failed: %v", err)
+	}
+	if !bytes.Equal(recovered, secret) {
+		t.Errorf("Recovery failed: got %q, want %q", recovered, secret)
+	}
+	if want := []byte{5, 2, 3}; !bytes.Equal(ids, want) {
+		t.Errorf("Used IDs = %v, want %v", ids, want)
+	}
+
+	conflicting := []Share{shares[0], shares[1], {ID: shares[1].ID, Value: shares[2].Value}}
+	if _, _, err := CombineReport(conflicting); err == nil {
+		t.Error("CombineReport should reject different shares with the same ID")
+	}
+
+	if _, _, err := CombineReport([]Share{shares[0], shares[0]}); !errors.Is(err, ErrTooFewShares) {
+		t.Errorf("CombineReport with one distinct share: got %v, want %v", err, ErrTooFewShares)
+	}
+}
+
+func TestSecretsEqual(t *testing.T) {
+	tests := []struct {
+		a, b []byte
+		want bool
+	}{
+		{[]byte("secret"), []byte("secret"), true},
+		{[]byte("secret"), []byte("secreT"), false},
+		{[]byte("secret"), []byte("secret!"), false},
+		{[]byte("secret"), []byte("sec"), false},
+		{[]byte{}, nil, true},
+		{nil, []byte{0}, false},
+	}
+
+	for _, tt := range tests {
+		if got := SecretsEqual(tt.a, tt.b); got != tt.want {
+			t.Errorf("SecretsEqual(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestCombineExact(t *testing.T) {
+	secret := []byte("exactly k")
+	shares, err := Split(secret, 5, 3)
+	if err != nil {
+		t.Fatalf("Split failed: %v", err)
+	}
+
+	recovered, err := CombineExact(shares[1:4], 3)
+	if err != nil {
+		t.Fatalf("CombineExact failed: %v", err)
+	}
+	if !bytes.Equal(recovered, secret) {
+		t.Errorf("Recovery failed: got %q, want %q", recovered, secret)
+	}
+
+	if _, err := CombineExact(shares[:4], 3); !errors.Is(err, ErrExcessShares) {
+		t.Errorf("CombineExact with k+1 parts returned %v, want %v", err, ErrExcessShares)
+	}
+	if _, err := CombineExact(shares[:2], 3); err == nil {
+		t.Error("CombineExact with k-1 parts should fail")
+	}
+	if _, err := CombineExact(shares[:2], 1); !errors.Is(err, ErrThresholdTooSmall) {
+		t.Errorf("CombineExact with k=1 returned %v, want %v", err, ErrThresholdTooSmall)
+	}
+}
+
+func TestDedupShares(t *testing.T) {
+	secret := []byte("shuffled secret")
+	shares, err := Split(secret, 7, 3)
+	if err != nil {
+		t.Fatalf("Split failed: %v", err)
+	}
+
+	tests := []struct {
+		name  string
+		input []Share
+		ids   []byte
+	}{
+		{"shuffled", []Share{shares[2], shares[0], shares[1]}, []byte{1, 2, 3}},
+		{"gapped", []Share{shares[6], shares[3], shares[0]}, []byte{1, 4, 7}},
+		{"repeated", []Share{shares[5], shares[1], shares[5], shares[4], shares[1]}, []byte{2, 5, 6}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			deduped, err := DedupShares(tt.input)
+			if err != nil {
+				t.Fatalf("DedupShares failed: %v", err)
+			}
+			ids := make([]byte, len(deduped))
+			for i, share := range deduped {
+				ids[i] = share.ID
+			}
+			if !bytes.Equal(ids, tt.ids) {
+				t.Errorf("IDs = %v, want %v", ids, tt.ids)
+			}
+
+			recovered, err := Combine(deduped)
+			if err != nil {
+				t.Fatalf("Combine failed: %v", err)
+			}
+			if !bytes.Equal(recovered, secret) {
+				t.Errorf("Recovery failed: got %q, want %q", recovered, secret)
+			}
+		})
+	}
+
+	conflicting := []Share{shares[3], {ID: shares[3].ID, Value: shares[4].Value}}
+	if _, err := DedupShares(conflicting); err == nil {
+		t.Error("DedupShares should reject different shares with the same ID")
+	}
 }
 
 func TestStringConversion(t *testing.T) {
@@ -257,6 +583,91 @@ func TestStringConversion(t *testing.T) {
 	}
 }
 
+func TestCompactConversion(t *testing.T) {
+	shares := []Share{
+		{ID: 1, Value: []byte{0xa1, 0xb2, 0xc3}},
+		{ID: 16, Value: []byte{0x00}},
+		{ID: 255, Value: []byte{0xff, 0x00, 0x10}},
+	}
+	want := []string{"01a1b2c3", "1000", "ffff0010"}
+
+	for i, share := range shares {
+		compact := ShareToCompact(share)
+		if compact != want[i] {
+			t.Errorf("ShareToCompact(%+v) = %q, want %q", share, compact, want[i])
+		}
+
+		parsed, err := CompactToShare(compact)
+		if err != nil {
+			t.Fatalf("CompactToShare(%q) failed: %v", compact, err)
+		}
+		if !parsed.Equal(share) {
+			t.Errorf("Round trip changed share: got %+v, want %+v", parsed, share)
+		}
+	}
+
+	// Shares of one secret have the same width
+	split, err := Split([]byte("fixed width"), 255, 2)
+	if err != nil {
+		t.Fatalf("Split failed: %v", err)
+	}
+	if a, b := ShareToCompact(split[0]), ShareToCompact(split[254]); len(a) != len(b) {
+		t.Errorf("Compact shares differ in width: %q and %q", a, b)
+	}
+
+	for _, input := range []string{"", "01", "0", "01zz", "1:ab"} {
+		if _, err := CompactToShare(input); err == nil {
+			t.Errorf("CompactToShare(%q) should fail", input)
+		}
+	}
+}
+
+func TestLabeledShares(t *testing.T) {
+	tests := []struct {
+		share Share
+		want  string
+	}{
+		{Share{ID: 1, Value: []byte{0xab, 0xcd}, Label: "alice"}, "1#alice:abcd"},
+		{Share{Version: VersionPadded, ID: 12, Value: []byte{0x01}, Label: "bob.smith-2"}, "v2:12#bob.smith-2:01"},
+		{Share{ID: 3, Value: []byte{0xff}}, "3:ff"},
+	}
+
+	for _, tt := range tests {
+		str := ShareToString(tt.share)
+		if str != tt.want {
+			t.Errorf("ShareToString(%+v) = %q, want %q", tt.share, str, tt.want)
+		}
+
+		parsed, err := ParseShare(str)
+		if err != nil {
+			t.Fatalf("ParseShare(%q) failed: %v", str, err)
+		}
+		if !parsed.Equal(tt.share) || parsed.Label != tt.share.Label {
+			t.Errorf("ParseShare(%q) = %+v, want %+v", str, parsed, tt.share)
+		}
+	}
+
+	// Labels do not take part in combining
+	shares, err := Split([]byte("labeled"), 3, 2)
+	if err != nil {
+		t.Fatalf("Split failed: %v", err)
+	}
+	shares[0].Label, shares[2].Label = "alice", "carol"
+	secret, err := Combine([]Share{shares[0], shares[2]})
+	if err != nil || string(secret) != "labeled" {
+		t.Errorf("Combine of labeled shares = %q, %v", secret, err)
+	}
+
+	for _, input := range []string{"1#:ab", "1#a b:ab", "1#a,b:ab", "1#a#b:ab", "#alice:ab", "alice#1:ab"} {
+		if _, err := StringToShare(input); err == nil {
+			t.Errorf("StringToShare(%q) should fail", input)
+		}
+	}
+	if err := ValidateLabel("Алиса_1"); err != nil {
+		t.Errorf("ValidateLabel rejected a non-ASCII label: %v", err)
+	}
+}
+
 func TestStringConversionErrors(t *testing.T) {
 	tests := []string{
 		"invalid",
@@ -265,6 +676,11 @@ func TestStringConversionErrors(t *testing.T) {
 		"1:xyz",
 		"1:abc", // odd length hex string
 		"1:",    // empty hex string
+		"1:abcd extra",
+		"1:abcd\n2:ef",
+		" 1:abcd",
+		"1:2:abcd",
+		"-1:abcd",
 	}
 
 	for _, test := range tests {
@@ -275,6 +691,89 @@ func TestStringConversionErrors(t *testing.T) {
 			}
 		})
 	}
+
+	// Hex errors should describe what exactly is wrong
+	messages := []struct {
+		input   string
+		wantErr string
+	}{
+		{"1:abc", "hex value has odd length (3)"},
+		{"1:xyz", "invalid hex digit at position 3"},
+		{"1:abzd", "invalid hex digit at position 5"},
+		{"1:abcd extra", "invalid hex digit at position 7"},
+	}
+
+	for _, tt := range messages {
+		t.Run(tt.input+"_message", func(t *testing.T) {
+			_, err := StringToShare(tt.input)
+			if err == nil || err.Error() != tt.wantErr {
+				t.Errorf("StringToShare(%q) error = %v, want %q", tt.input, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestStringToShareVersion(t *testing.T) {
+	legacy, err := StringToShare("3:1234abcd")
+	if err != nil {
+		t.Fatalf("StringToShare(legacy) failed: %v", err)
+	}
+
+	versioned, err := StringToShare("v1:3:1234abcd")
+	if err != nil {
+		t.Fatalf("StringToShare(versioned) failed: %v", err)
+	}
+
+	if !versioned.Equal(legacy) {
+		t.Errorf("Versioned share %+v differs from legacy share %+v", versioned, legacy)
+	}
+	if got := ShareToString(versioned); got != "3:1234abcd" {
+		t.Errorf("ShareToString() = %q, want %q", got, "3:1234abcd")
+	}
+
+	messages := []struct {
+		input   string
+		wantErr string
+	}{
+		{"v6:3:1234abcd", "unsupported share version 6"},
+		{"v0:3:1234abcd", `invalid share version "v0"`},
+		{"vx:3:1234abcd", `invalid share version "vx"`},
+		{"v1", "invalid part format"},
+		{"v1:3", "invalid part format"},
+		{"v1:3:12zz", "invalid hex digit at position 8"},
+	}
+
+	for _, tt := range messages {
+		t.Run(tt.input, func(t *testing.T) {
+			_, err := StringToShare(tt.input)
+			if err == nil || err.Error() != tt.wantErr {
+				t.Errorf("StringToShare(%q) error = %v, want %q", tt.input, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestStringToShareSwapped(t *testing.T) {
+	tests := []struct {
+		input   string
+		wantErr string
+	}{
+		{"abcd:1", "invalid part format (did you mean 1:abcd?)"},
+		{"v2:1234abcd:17", "invalid part format (did you mean v2:17:1234abcd?)"},
+		// Not a valid share either way round
+		{"abcd:256", "invalid part format"},
+		{"abc:1", "invalid part format"},
+		{"xyz:1", "invalid part format"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			_, err := StringToShare(tt.input)
+			if err == nil || err.Error() != tt.wantErr {
+				t.Errorf("StringToShare(%q) error = %v, want %q", tt.input, err, tt.wantErr)
+			}
+		})
+	}
 }
 
 func TestEmptySecret(t *testing.T) {
@@ -310,9 +809,70 @@ func TestLargeSecret(t *testing.T) {
 	if err != nil {
 		t.Fatalf("Combine failed with large secret: %v", err)
 	}
-
+
+	if !bytes.Equal(recovered, secret) {
+		t.Error("Recovery failed for large secret")
+	}
+}
+
+func TestSplitDoesNotModifySecret(t *testing.T) {
+	// A secret with spare capacity must not receive the checksum byte
+	backing := []byte("secret--spare")
+	secret := backing[:6]
+	original := append([]byte(nil), backing...)
+
+	shares, err := Split(secret, 5, 3)
+	if err != nil {
+		t.Fatalf("Split failed: %v", err)
+	}
+
+	if !bytes.Equal(backing, original) {
+		t.Errorf("Split modified the caller's buffer: got %q, want %q", backing, original)
+	}
+
+	recovered, err := Combine(shares[:3])
+	if err != nil {
+		t.Fatalf("Combine failed: %v", err)
+	}
+	if !bytes.Equal(recovered, []byte("secret")) {
+		t.Errorf("Recovery failed: got %q, want %q", recovered, "secret")
+	}
+}
+
+func TestSplitSubsliceAliasing(t *testing.T) {
+	secret := make([]byte, 4, 8)
+	copy(secret, "abcd")
+
+	if _, err := Split(secret, 3, 2); err != nil {
+		t.Fatalf("Split failed: %v", err)
+	}
+
+	if len(secret) != 4 || string(secret) != "abcd" {
+		t.Errorf("Split changed the secret: got %q (len %d)", secret, len(secret))
+	}
+	if spare := secret[4:8]; !bytes.Equal(spare, make([]byte, 4)) {
+		t.Errorf("Split wrote into spare capacity: %x", spare)
+	}
+}
+
+func TestMaximumShares(t *testing.T) {
+	secret := bytes.Repeat([]byte{0xFF}, 255)
+
+	shares, err := Split(secret, MaxShares, MaxShares)
+	if err != nil {
+		t.Fatalf("Split failed: %v", err)
+	}
+
+	if last := shares[MaxShares-1].ID; last != MaxShares {
+		t.Errorf("Last share ID = %d, want %d", last, MaxShares)
+	}
+
+	recovered, err := Combine(shares)
+	if err != nil {
+		t.Fatalf("Combine failed: %v", err)
+	}
 	if !bytes.Equal(recovered, secret) {
-		t.Error("Recovery failed for large secret")
+		t.Errorf("Recovery failed with %d shares", MaxShares)
 	}
 }
 
@@ -426,6 +986,9 @@ func TestChecksumValidation(t *testing.T) {
 		if err == nil || !bytes.Contains([]byte(err.Error()), []byte("checksum verification failed")) {
 			t.Error("Combine should fail with checksum error for modified checksum")
 		}
+		if !errors.Is(err, ErrChecksumMismatch) {
+			t.Errorf("Combine error = %v, want ErrChecksumMismatch", err)
+		}
 	})
 }
 
@@ -457,6 +1020,319 @@ func TestChecksumWithDifferentSecrets(t *testing.T) {
 	}
 }
 
+func TestSplitDetailed(t *testing.T) {
+	secret := []byte("detailed secret")
+	n, k := 5, 3
+
+	result, err := SplitDetailed(secret, n, k)
+	if err != nil {
+		t.Fatalf("SplitDetailed failed: %v", err)
+	}
+
+	if len(result.Shares) != n {
+		t.Errorf("Expected %d shares, got %d", n, len(result.Shares))
+	}
+	if result.SecretLen != len(secret) {
+		t.Errorf("SecretLen = %d, want %d", result.SecretLen, len(secret))
+	}
+	if result.Threshold != k {
+		t.Errorf("Threshold = %d, want %d", result.Threshold, k)
+	}
+
+	recovered, err := Combine(result.Shares[:k])
+	if err != nil {
+		t.Fatalf("Combine failed: %v", err)
+	}
+	if !bytes.Equal(recovered, secret) {
+		t.Errorf("Recovery failed: got %q, want %q", string(recovered), string(secret))
+	}
+
+	if _, err := SplitDetailed(secret, 2, 3); err == nil {
+		t.Error("SplitDetailed should fail when n is less than k")
+	}
+}
+
+func TestShareJSON(t *testing.T) {
+	share := Share{ID: 1, Value: []byte{0x12, 0x34, 0xab, 0xcd}}
+
+	data, err := json.Marshal(share)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	if string(data) != `"1:1234abcd"` {
+		t.Errorf("Marshal = %s, want %q", data, "1:1234abcd")
+	}
+
+	var got Share
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	if !got.Equal(share) {
+		t.Errorf("Unmarshal = %+v, want %+v", got, share)
+	}
+
+	// Versioned shares keep their prefix
+	versioned := Share{Version: VersionHeader, ID: 2, Value: []byte{0xff}}
+	data, err = json.Marshal([]Share{share, versioned})
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	if string(data) != `["1:1234abcd","v3:2:ff"]` {
+		t.Errorf("Marshal = %s", data)
+	}
+	var shares []Share
+	if err := json.Unmarshal(data, &shares); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	if len(shares) != 2 || !shares[1].Equal(versioned) {
+		t.Errorf("Unmarshal = %+v, want %+v", shares, versioned)
+	}
+
+	if err := json.Unmarshal([]byte(`"not a share"`), &got); err == nil {
+		t.Error("expected error for malformed share")
+	}
+}
+
+func TestShareEqual(t *testing.T) {
+	share := Share{ID: 1, Value: []byte{0x12, 0x34}}
+
+	tests := []struct {
+		name  string
+		other Share
+		want  bool
+	}{
+		{"Identical", Share{ID: 1, Value: []byte{0x12, 0x34}}, true},
+		{"Different ID", Share{ID: 2, Value: []byte{0x12, 0x34}}, false},
+		{"Different value", Share{ID: 1, Value: []byte{0x12, 0x35}}, false},
+		{"Different length", Share{ID: 1, Value: []byte{0x12}}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := share.Equal(tt.other); got != tt.want {
+				t.Errorf("Equal() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSortShares(t *testing.T) {
+	shares, err := Split([]byte("sort me"), 6, 3)
+	if err != nil {
+		t.Fatalf("Split failed: %v", err)
+	}
+
+	shuffled := []Share{shares[4], shares[1], shares[5], shares[0], shares[3], shares[2]}
+	SortShares(shuffled)
+
+	for i := range shuffled {
+		if !shuffled[i].Equal(shares[i]) {
+			t.Errorf("Position %d: got share %d, want share %d", i, shuffled[i].ID, shares[i].ID)
+		}
+	}
+}
+
+func TestContextCancellation(t *testing.T) {
+	secret := bytes.Repeat([]byte("x"), 4096)
+
+	shares, err := Split(secret, 5, 3)
+	if err != nil {
+		t.Fatalf("Split failed: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := SplitContext(ctx, secret, 5, 3); !errors.Is(err, context.Canceled) {
+		t.Errorf("SplitContext() error = %v, want %v", err, context.Canceled)
+	}
+
+	if _, err := CombineContext(ctx, shares[:3]); !errors.Is(err, context.Canceled) {
+		t.Errorf("CombineContext() error = %v, want %v", err, context.Canceled)
+	}
+
+	// A live context behaves exactly like the plain functions
+	recovered, err := CombineContext(context.Background(), shares[:3])
+	if err != nil {
+		t.Fatalf("CombineContext failed: %v", err)
+	}
+	if !bytes.Equal(recovered, secret) {
+		t.Error("Recovery with context failed")
+	}
+}
+
+func FuzzStringToShare(f *testing.F) {
+	seeds := []string{
+		"1:1234abcd",
+		"255:00ff",
+		"001:ab",
+		"1:ABCD",
+		"256:abcd",
+		"1:abc",
+		"1:xyz",
+		"1:",
+		":ab",
+		"1:abcd extra",
+		"v1:1:ab",
+		"v2:1:ab",
+		"invalid",
+	}
+	for _, seed := range seeds {
+		f.Add(seed)
+	}
+
+	f.Fuzz(func(t *testing.T, s string) {
+		share, err := StringToShare(s)
+		if err != nil {
+			return
+		}
+
+		str := ShareToString(share)
+		reparsed, err := StringToShare(str)
+		if err != nil {
+			t.Fatalf("StringToShare(%q) failed on re-serialized share: %v", str, err)
+		}
+		if !reparsed.Equal(share) {
+			t.Errorf("Round trip of %q changed share: got %q", s, ShareToString(reparsed))
+		}
+	})
+}
+
+func FuzzSplitCombine(f *testing.F) {
+	f.Add([]byte("Hello, World!"), uint8(5), uint8(3), uint8(0))
+	f.Add([]byte{}, uint8(2), uint8(2), uint8(1))
+	f.Add([]byte{0x00, 0xff}, uint8(255), uint8(10), uint8(200))
+
+	f.Fuzz(func(t *testing.T, secret []byte, n, k, offset uint8) {
+		// Map the fuzzed parameters into a valid scheme
+		if n < 2 {
+			n = 2
+		}
+		k = 2 + k%(n-1)
+
+		shares, err := Split(secret, int(n), int(k))
+		if err != nil {
+			t.Fatalf("Split(n=%d, k=%d) failed: %v", n, k, err)
+		}
+
+		// Take k consecutive shares starting at a fuzzed offset
+		subset := make([]Share, k)
+		for i := range subset {
+			subset[i] = shares[(int(offset)+i)%int(n)]
+		}
+
+		recovered, err := Combine(subset)
+		if err != nil {
+			t.Fatalf("Combine failed: %v", err)
+		}
+		if !bytes.Equal(recovered, secret) {
+			t.Errorf("Recovery failed: got %x, want %x", recovered, secret)
+		}
+	})
+}
+
+func TestProgressReporting(t *testing.T) {
+	secret := bytes.Repeat([]byte("p"), 3000)
+
+	var calls, lastDone, lastTotal int
+	ctx := WithProgress(context.Background(), func(done, total int) {
+		if done < lastDone {
+			t.Errorf("Progress went backwards: %d after %d", done, lastDone)
+		}
+		calls++
+		lastDone, lastTotal = done, total
+	})
+
+	shares, err := SplitContext(ctx, secret, 5, 3)
+	if err != nil {
+		t.Fatalf("SplitContext failed: %v", err)
+	}
+	if calls < 2 || lastDone != len(secret)+1 || lastTotal != len(secret)+1 {
+		t.Errorf("Split progress: %d calls, last %d/%d, want final %d/%d",
+			calls, lastDone, lastTotal, len(secret)+1, len(secret)+1)
+	}
+
+	calls, lastDone, lastTotal = 0, 0, 0
+	if _, err := CombineContext(ctx, shares[:3]); err != nil {
+		t.Fatalf("CombineContext failed: %v", err)
+	}
+	if calls < 2 || lastDone != lastTotal || lastTotal != len(secret)+1 {
+		t.Errorf("Combine progress: %d calls, last %d/%d", calls, lastDone, lastTotal)
+	}
+}
+
+func TestTrace(t *testing.T) {
+	secret := []byte("trace")
+	ids := []byte{3, 7, 200}
+
+	var calls int
+	ctx := WithTrace(context.Background(), func(byteIndex int, coeffs []byte, traceIDs, values []byte) {
+		if byteIndex != calls {
+			t.Errorf("Expected byte %d, got %d", calls, byteIndex)
+		}
+		calls++
+
+		if len(coeffs) != 2 {
+			t.Errorf("Expected 2 coefficients for k=2, got %d", len(coeffs))
+		}
+		if byteIndex < len(secret) && coeffs[0] != secret[byteIndex] {
+			t.Errorf("Byte %d: constant term %02x, want %02x", byteIndex, coeffs[0], secret[byteIndex])
+		}
+		if !bytes.Equal(traceIDs, ids) {
+			t.Errorf("Expected IDs %v, got %v", ids, traceIDs)
+		}
+		for i, id := range traceIDs {
+			if want := evaluatePolynomial(coeffs, id); values[i] != want {
+				t.Errorf("Byte %d: f(%d) = %02x, want %02x", byteIndex, id, values[i], want)
+			}
+		}
+	})
+
+	shares, err := SplitWithIDsContext(ctx, secret, ids, 2)
+	if err != nil {
+		t.Fatalf("SplitWithIDsContext failed: %v", err)
+	}
+	if calls != len(secret)+1 {
+		t.Errorf("Expected %d traced polynomials, got %d", len(secret)+1, calls)
+	}
+
+	recovered, err := Combine(shares[1:])
+	if err != nil || !bytes.Equal(recovered, secret) {
+		t.Errorf("Combine() = %q, %v, want %q", recovered, err, secret)
+	}
+}
+
+func TestCombineMatchesPerByteInterpolation(t *testing.T) {
+	secret := make([]byte, 4096)
+	for i := range secret {
+		secret[i] = byte(i * 7)
+	}
+
+	shares, err := Split(secret, 8, 5)
+	if err != nil {
+		t.Fatalf("Split failed: %v", err)
+	}
+	testShares := []Share{shares[6], shares[1], shares[3], shares[7], shares[0]}
+
+	recovered, err := Combine(testShares)
+	if err != nil {
+		t.Fatalf("Combine failed: %v", err)
+	}
+
+	// Interpolating every byte from scratch must give the same result
+	xs := make([]byte, len(testShares))
+	ys := make([]byte, len(testShares))
+	for byteIndex := 0; byteIndex < len(secret); byteIndex++ {
+		for i, share := range testShares {
+			xs[i] = share.ID
+			ys[i] = share.Value[byteIndex]
+		}
+		if got := lagrangeInterpolation(xs, ys); got != recovered[byteIndex] {
+			t.Fatalf("Byte %d: Combine gave %d, per-byte interpolation %d", byteIndex, recovered[byteIndex], got)
+		}
+	}
+}
+
 func BenchmarkSplit(b *testing.B) {
 	secret := []byte("benchmark secret for testing performance")
 
@@ -469,6 +1345,75 @@ func BenchmarkSplit(b *testing.B) {
 	}
 }
 
+// BenchmarkSplit64KB measures the sequential split of a 64KB secret
+func BenchmarkSplit64KB(b *testing.B) {
+	defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(1))
+	secret := bytes.Repeat([]byte("0123456789abcdef"), 4096)
+
+	b.SetBytes(int64(len(secret)))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := Split(secret, 10, 5); err != nil {
+			b.Fatalf("Split failed: %v", err)
+		}
+	}
+}
+
+// benchmarkSizes and benchmarkSchemes are swept by BenchmarkSplitSizes and
+// BenchmarkCombineSizes
+var (
+	benchmarkSizes = []struct {
+		name string
+		size int
+	}{
+		{"16B", 16},
+		{"1KB", 1024},
+		{"64KB", 64 * 1024},
+	}
+	benchmarkSchemes = []struct{ k, n int }{
+		{2, 3},
+		{5, 9},
+		{10, 20},
+	}
+)
+
+func BenchmarkSplitSizes(b *testing.B) {
+	for _, size := range benchmarkSizes {
+		secret := bytes.Repeat([]byte{0x5a}, size.size)
+		for _, scheme := range benchmarkSchemes {
+			b.Run(fmt.Sprintf("%s/k%d-n%d", size.name, scheme.k, scheme.n), func(b *testing.B) {
+				b.SetBytes(int64(len(secret)))
+				for i := 0; i < b.N; i++ {
+					if _, err := Split(secret, scheme.n, scheme.k); err != nil {
+						b.Fatalf("Split failed: %v", err)
+					}
+				}
+			})
+		}
+	}
+}
+
+func BenchmarkCombineSizes(b *testing.B) {
+	for _, size := range benchmarkSizes {
+		secret := bytes.Repeat([]byte{0x5a}, size.size)
+		for _, scheme := range benchmarkSchemes {
+			shares, err := Split(secret, scheme.n, scheme.k)
+			if err != nil {
+				b.Fatalf("Split failed: %v", err)
+			}
+
+			b.Run(fmt.Sprintf("%s/k%d-n%d", size.name, scheme.k, scheme.n), func(b *testing.B) {
+				b.SetBytes(int64(len(secret)))
+				for i := 0; i < b.N; i++ {
+					if _, err := Combine(shares[:scheme.k]); err != nil {
+						b.Fatalf("Combine failed: %v", err)
+					}
+				}
+			})
+		}
+	}
+}
+
 func BenchmarkCombine(b *testing.B) {
 	secret := []byte("benchmark secret for testing performance")
 	shares, err := Split(secret, 10, 5)
@@ -486,3 +1431,148 @@ func BenchmarkCombine(b *testing.B) {
 		}
 	}
 }
+
+func BenchmarkCombine4KB(b *testing.B) {
+	secret := bytes.Repeat([]byte("0123456789abcdef"), 256)
+	shares, err := Split(secret, 10, 5)
+	if err != nil {
+		b.Fatalf("Split failed: %v", err)
+	}
+
+	testShares := shares[:5]
+
+	b.SetBytes(int64(len(secret)))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := Combine(testShares); err != nil {
+			b.Fatalf("Combine failed: %v", err)
+		}
+	}
+}
+
+// BenchmarkCombine4KBPerByte measures recomputing the Lagrange basis for
+// every byte, as Combine did before the basis was precomputed
+func BenchmarkCombine4KBPerByte(b *testing.B) {
+	secret := bytes.Repeat([]byte("0123456789abcdef"), 256)
+	shares, err := Split(secret, 10, 5)
+	if err != nil {
+		b.Fatalf("Split failed: %v", err)
+	}
+
+	testShares := shares[:5]
+	xs := make([]byte, len(testShares))
+	ys := make([]byte, len(testShares))
+
+	b.SetBytes(int64(len(secret)))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for byteIndex := 0; byteIndex < len(secret)+1; byteIndex++ {
+			for j, share := range testShares {
+				xs[j] = share.ID
+				ys[j] = share.Value[byteIndex]
+			}
+			lagrangeInterpolation(xs, ys)
+		}
+	}
+}
+
+// failingReader fails every read with err after yielding n bytes
+type failingReader struct {
+	n   int
+	err error
+}
+
+func (r *failingReader) Read(p []byte) (int, error) {
+	if r.n == 0 {
+		return 0, r.err
+	}
+	read := min(r.n, len(p))
+	r.n -= read
+	return read, nil
+}
+
+func TestSplitWithReaderFailure(t *testing.T) {
+	tests := []struct {
+		name string
+		rng  io.Reader
+	}{
+		{"Failing", &failingReader{err: errors.New("entropy source unavailable")}},
+		{"Failing midway", &failingReader{n: 10, err: errors.New("entropy source unavailable")}},
+		{"Short", &failingReader{n: 3, err: io.EOF}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			shares, err := SplitWithReader([]byte("secret"), 5, 3, tt.rng)
+			if !errors.Is(err, ErrRandomSource) {
+				t.Errorf("SplitWithReader() error = %v, want %v", err, ErrRandomSource)
+			}
+			if shares != nil {
+				t.Error("No shares may be returned when the random source fails")
+			}
+		})
+	}
+}
+
+func TestSplitWithReaderDeterministic(t *testing.T) {
+	secret := []byte("reproducible")
+	first, err := SplitWithReader(secret, 4, 3, bytes.NewReader(bytes.Repeat([]byte{0x5a}, 1000)))
+	if err != nil {
+		t.Fatalf("SplitWithReader failed: %v", err)
+	}
+	second, err := SplitWithReader(secret, 4, 3, bytes.NewReader(bytes.Repeat([]byte{0x5a}, 1000)))
+	if err != nil {
+		t.Fatalf("SplitWithReader failed: %v", err)
+	}
+
+	for i := range first {
+		if !first[i].Equal(second[i]) {
+			t.Errorf("Share %d differs for the same random input", first[i].ID)
+		}
+	}
+
+	recovered, err := Combine(first[1:])
+	if err != nil {
+		t.Fatalf("Combine failed: %v", err)
+	}
+	if !bytes.Equal(recovered, secret) {
+		t.Errorf("Recovery failed: got %q, want %q", recovered, secret)
+	}
+}
+
+func TestCombineShareIDs(t *testing.T) {
+	shares, err := Split([]byte("ids"), 3, 2)
+	if err != nil {
+		t.Fatalf("Split failed: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		shares []Share
+		err    string
+	}{
+		// Copies of one share used to interpolate an all-zero secret whose
+		// XOR checksum matched
+		{"same share twice", []Share{shares[0], shares[0]}, "duplicate share ID 1"},
+		{"conflicting IDs", []Share{shares[0], {ID: 1, Value: shares[1].Value}}, "duplicate share ID 1"},
+		{"zero ID", []Share{shares[0], {ID: 0, Value: shares[1].Value}}, "share ID cannot be 0"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Combine(tt.shares); err == nil || err.Error() != tt.err {
+				t.Errorf("Combine() error = %v, want %q", err, tt.err)
+			}
+			if _, _, err := CombineLenient(tt.shares); err == nil || err.Error() != tt.err {
+				t.Errorf("CombineLenient() error = %v, want %q", err, tt.err)
+			}
+		})
+	}
+
+	// IDs beyond the field do not fit in Share.ID and are rejected when
+	// parsed, before they could be narrowed
+	for _, s := range []string{"256:abcd", "v4:300:abcd"} {
+		if _, err := StringToShare(s); err == nil {
+			t.Errorf("StringToShare(%q) succeeded", s)
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package shamir
+
+import (
+	"errors"
+	"unicode/utf8"
+)
+
+// ErrInvalidUTF8 is returned by SplitText and CombineText for secrets that
+// are not valid UTF-8 text
+var ErrInvalidUTF8 = errors.New("secret is not valid UTF-8 text")
+
+// SplitText is like Split for a text secret. It rejects strings that are
+// not valid UTF-8, so that CombineText returns exactly the same string.
+func SplitText(secret string, n, k int) ([]Share, error) {
+	if !utf8.ValidString(secret) {
+		return nil, ErrInvalidUTF8
+	}
+	return Split([]byte(secret), n, k)
+}
+
+// CombineText is like Combine for a text secret. A recovered secret that
+// is not valid UTF-8 is returned as ErrInvalidUTF8 instead of a lossy
+// string; use Combine for binary secrets.
+func CombineText(shares []Share) (string, error) {
+	secret, err := Combine(shares)
+	if err != nil {
+		return "", err
+	}
+	if !utf8.Valid(secret) {
+		return "", ErrInvalidUTF8
+	}
+	return string(secret), nil
+}
+
+// SplitToStrings is like Split but returns the shares in their text form
+// (ShareToString). Together with CombineStrings it only takes and returns
+// plain types, which suits bindings such as syscall/js in the browser.
+func SplitToStrings(secret []byte, n, k int, opts ...SplitOption) ([]string, error) {
+	shares, err := Split(secret, n, k, opts...)
+	if err != nil {
+		return nil, err
+	}
+	parts := make([]string, len(shares))
+	for i, share := range shares {
+		parts[i] = ShareToString(share)
+	}
+	return parts, nil
+}
+
+// CombineStrings parses shares in any encoding accepted by ParseShares and
+// recovers the secret from them
+func CombineStrings(parts []string) ([]byte, error) {
+	shares, err := ParseShares(parts)
+	if err != nil {
+		return nil, err
+	}
+	return Combine(shares)
+}
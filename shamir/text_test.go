@@ -0,0 +1,67 @@
+package shamir
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestSplitTextRoundTrip(t *testing.T) {
+	for _, secret := range []string{"", "plain ascii", "пароль 🔑 密码"} {
+		shares, err := SplitText(secret, 5, 3)
+		if err != nil {
+			t.Fatalf("SplitText(%q) failed: %v", secret, err)
+		}
+
+		got, err := CombineText(shares[1:4])
+		if err != nil {
+			t.Fatalf("CombineText failed: %v", err)
+		}
+		if got != secret {
+			t.Errorf("CombineText = %q, want %q", got, secret)
+		}
+	}
+}
+
+func TestTextInvalidUTF8(t *testing.T) {
+	if _, err := SplitText("\xff\xfe", 3, 2); !errors.Is(err, ErrInvalidUTF8) {
+		t.Errorf("SplitText error = %v, want %v", err, ErrInvalidUTF8)
+	}
+
+	// Binary secrets split with Split cannot be combined as text
+	shares, err := Split([]byte{0x80, 'a', 0xc3}, 3, 2)
+	if err != nil {
+		t.Fatalf("Split failed: %v", err)
+	}
+	if _, err := CombineText(shares[:2]); !errors.Is(err, ErrInvalidUTF8) {
+		t.Errorf("CombineText error = %v, want %v", err, ErrInvalidUTF8)
+	}
+
+	// Errors from Combine are passed through
+	if _, err := CombineText(shares[:1]); !errors.Is(err, ErrTooFewShares) {
+		t.Errorf("CombineText error = %v, want %v", err, ErrTooFewShares)
+	}
+}
+
+func TestSplitToStringsRoundTrip(t *testing.T) {
+	secret := []byte{0x00, 0xff, 'w', 'a', 's', 'm'}
+	parts, err := SplitToStrings(secret, 4, 3, WithChecksum(CRC32Checksum{}))
+	if err != nil {
+		t.Fatalf("SplitToStrings failed: %v", err)
+	}
+	if len(parts) != 4 || !strings.HasPrefix(parts[0], "v4:1:") {
+		t.Fatalf("SplitToStrings = %q", parts)
+	}
+
+	// Empty entries are skipped, as with ParseShares
+	recovered, err := CombineStrings([]string{parts[3], "", parts[0], parts[1]})
+	if err != nil || !bytes.Equal(recovered, secret) {
+		t.Errorf("CombineStrings = %q, %v, want %q", recovered, err, secret)
+	}
+
+	var perr *ParseError
+	if _, err := CombineStrings([]string{parts[0], "not a part"}); !errors.As(err, &perr) || perr.Index != 2 {
+		t.Errorf("CombineStrings with an invalid part = %v, want a *ParseError for part 2", err)
+	}
+}
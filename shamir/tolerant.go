@@ -0,0 +1,115 @@
+package shamir
+
+import (
+	"errors"
+	"fmt"
+)
+
+// MaxCombineSubsets caps the number of k-subsets CombineTolerant tries
+const MaxCombineSubsets = 10000
+
+// ErrNoValidSubset is returned by CombineTolerant when no k-subset of the
+// shares recovers a secret that passes checksum verification
+var ErrNoValidSubset = errors.New("no subset of the parts recovers the secret")
+
+// CombineTolerant recovers a secret from more shares than needed when some
+// of them may be corrupt. Instead of using all shares it combines subsets
+// of exactly k shares, in order, until one passes checksum verification,
+// and returns the secret with the IDs of that subset.
+//
+// At most MaxCombineSubsets subsets are tried. The one-byte checksum lets a
+// corrupt subset pass with a probability of 1/256, so with many corrupt
+// shares the result should be checked by other means.
+func CombineTolerant(shares []Share, k int) (secret []byte, usedIDs []byte, err error) {
+	if k < 2 {
+		return nil, nil, ErrThresholdTooSmall
+	}
+	if len(shares) < k {
+		return nil, nil, fmt.Errorf("%w: %d parts required, only %d provided", ErrTooFewShares, k, len(shares))
+	}
+
+	var seen [256]bool
+	for _, share := range shares {
+		if seen[share.ID] {
+			return nil, nil, fmt.Errorf("duplicate share ID %d", share.ID)
+		}
+		seen[share.ID] = true
+	}
+
+	// indices is the current subset, advanced in lexicographic order
+	indices := make([]int, k)
+	for i := range indices {
+		indices[i] = i
+	}
+	subset := make([]Share, k)
+
+	for tried := 0; tried < MaxCombineSubsets; tried++ {
+		for i, index := range indices {
+			subset[i] = shares[index]
+		}
+		if secret, err := Combine(subset); err == nil {
+			usedIDs = make([]byte, k)
+			for i, share := range subset {
+				usedIDs[i] = share.ID
+			}
+			return secret, usedIDs, nil
+		}
+
+		if !nextSubset(indices, len(shares)) {
+			return nil, nil, ErrNoValidSubset
+		}
+	}
+
+	return nil, nil, fmt.Errorf("%w within the first %d subsets", ErrNoValidSubset, MaxCombineSubsets)
+}
+
+// nextSubset advances indices, a k-subset of 0..n-1 in increasing order, to
+// the next subset in lexicographic order. It reports false after the last.
+func nextSubset(indices []int, n int) bool {
+	// Find the rightmost index that can still move and reset the ones
+	// after it
+	k := len(indices)
+	i := k - 1
+	for i >= 0 && indices[i] == n-k+i {
+		i--
+	}
+	if i < 0 {
+		return false
+	}
+	indices[i]++
+	for j := i + 1; j < k; j++ {
+		indices[j] = indices[j-1] + 1
+	}
+	return true
+}
+
+// MinimalSubsets returns the subsets of exactly k of the shares, in
+// lexicographic order of their positions, e.g. to check that every subset
+// recovers the same secret. There are C(n, k) of them, which grows quickly,
+// so at most limit subsets are returned; a limit of 0 or less means no
+// limit. The subsets share the values of the given shares. For k outside
+// 1..len(shares) the result is empty.
+func MinimalSubsets(shares []Share, k, limit int) [][]Share {
+	if k < 1 || k > len(shares) {
+		return nil
+	}
+
+	indices := make([]int, k)
+	for i := range indices {
+		indices[i] = i
+	}
+
+	var subsets [][]Share
+	for limit <= 0 || len(subsets) < limit {
+		subset := make([]Share, k)
+		for i, index := range indices {
+			subset[i] = shares[index]
+		}
+		subsets = append(subsets, subset)
+
+		if !nextSubset(indices, len(shares)) {
+			break
+		}
+	}
+	return subsets
+}
@@ -0,0 +1,122 @@
+package shamir
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestCombineTolerantCorruptShare(t *testing.T) {
+	secret := []byte("one bad apple")
+	shares, err := Split(secret, 4, 3)
+	if err != nil {
+		t.Fatalf("Split failed: %v", err)
+	}
+
+	for corrupt := range shares {
+		t.Run(fmt.Sprintf("share %d", corrupt+1), func(t *testing.T) {
+			input := make([]Share, len(shares))
+			copy(input, shares)
+			value := bytes.Clone(shares[corrupt].Value)
+			value[3] ^= 0x40
+			input[corrupt] = Share{ID: shares[corrupt].ID, Value: value}
+
+			if _, err := Combine(input); err == nil {
+				t.Fatal("Combine with a corrupt share should fail")
+			}
+
+			recovered, ids, err := CombineTolerant(input, 3)
+			if err != nil {
+				t.Fatalf("CombineTolerant failed: %v", err)
+			}
+			if !bytes.Equal(recovered, secret) {
+				t.Errorf("Recovery failed: got %q, want %q", recovered, secret)
+			}
+			if len(ids) != 3 || bytes.IndexByte(ids, shares[corrupt].ID) >= 0 {
+				t.Errorf("Used IDs %v must be 3 IDs without the corrupt %d", ids, shares[corrupt].ID)
+			}
+		})
+	}
+}
+
+func TestCombineTolerantErrors(t *testing.T) {
+	shares, err := Split([]byte("secret"), 3, 2)
+	if err != nil {
+		t.Fatalf("Split failed: %v", err)
+	}
+
+	if _, _, err := CombineTolerant(shares, 1); !errors.Is(err, ErrThresholdTooSmall) {
+		t.Errorf("k=1: got %v, want %v", err, ErrThresholdTooSmall)
+	}
+	if _, _, err := CombineTolerant(shares[:2], 3); !errors.Is(err, ErrTooFewShares) {
+		t.Errorf("Too few shares: got %v, want %v", err, ErrTooFewShares)
+	}
+	if _, _, err := CombineTolerant([]Share{shares[0], shares[1], shares[0]}, 2); err == nil {
+		t.Error("Duplicate IDs should be rejected")
+	}
+
+	// Two of three shares truncated: no pair has equal lengths
+	broken := []Share{shares[0], {ID: 2, Value: shares[1].Value[:2]}, {ID: 3, Value: shares[2].Value[:3]}}
+	if _, _, err := CombineTolerant(broken, 2); !errors.Is(err, ErrNoValidSubset) {
+		t.Errorf("All subsets failing: got %v, want %v", err, ErrNoValidSubset)
+	}
+}
+
+func TestCombineTolerantLimit(t *testing.T) {
+	shares, err := Split([]byte("limited search"), 20, 10)
+	if err != nil {
+		t.Fatalf("Split failed: %v", err)
+	}
+
+	// With 11 shares truncated to different lengths every 10-subset
+	// contains one of them, and there are C(20, 10) = 184756 subsets
+	for i := 0; i < 11; i++ {
+		shares[i].Value = shares[i].Value[:1+i]
+	}
+
+	if _, _, err := CombineTolerant(shares, 10); !errors.Is(err, ErrNoValidSubset) {
+		t.Errorf("CombineTolerant() error = %v, want %v", err, ErrNoValidSubset)
+	}
+}
+
+func TestMinimalSubsets(t *testing.T) {
+	secret := []byte("every subset")
+	shares, err := Split(secret, 7, 3)
+	if err != nil {
+		t.Fatalf("Split failed: %v", err)
+	}
+
+	// C(7, 3) = 35
+	subsets := MinimalSubsets(shares, 3, 0)
+	if len(subsets) != 35 {
+		t.Fatalf("got %d subsets, want 35", len(subsets))
+	}
+
+	seen := make(map[[3]byte]bool)
+	for _, subset := range subsets {
+		ids := [3]byte{subset[0].ID, subset[1].ID, subset[2].ID}
+		if ids[0] >= ids[1] || ids[1] >= ids[2] || seen[ids] {
+			t.Errorf("subset %v is out of order or repeated", ids)
+		}
+		seen[ids] = true
+
+		recovered, err := Combine(subset)
+		if err != nil {
+			t.Fatalf("Combine(%v) failed: %v", ids, err)
+		}
+		if !bytes.Equal(recovered, secret) {
+			t.Errorf("Combine(%v) = %q, want %q", ids, recovered, secret)
+		}
+	}
+
+	if got := MinimalSubsets(shares, 3, 10); len(got) != 10 || got[9][1].ID != 4 || got[9][2].ID != 5 {
+		t.Errorf("limited to 10, got %d subsets", len(got))
+	}
+	if got := MinimalSubsets(shares, 7, 0); len(got) != 1 {
+		t.Errorf("k = n gave %d subsets, want 1", len(got))
+	}
+	if got := MinimalSubsets(shares, 8, 0); got != nil {
+		t.Errorf("k > n gave %d subsets, want none", len(got))
+	}
+}
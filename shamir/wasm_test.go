@@ -0,0 +1,24 @@
+package shamir
+
+import (
+	"go/build"
+	"testing"
+)
+
+// TestPortableImports checks that the package builds for js/wasm without
+// file, network or process access, so it can run in the browser where
+// crypto/rand is backed by crypto.getRandomValues
+func TestPortableImports(t *testing.T) {
+	ctx := build.Default
+	ctx.GOOS, ctx.GOARCH = "js", "wasm"
+	pkg, err := ctx.ImportDir(".", 0)
+	if err != nil {
+		t.Fatalf("ImportDir for js/wasm failed: %v", err)
+	}
+	forbidden := map[string]bool{"os": true, "os/exec": true, "io/fs": true, "io/ioutil": true, "path/filepath": true, "net": true, "net/http": true, "syscall": true}
+	for _, imp := range pkg.Imports {
+		if forbidden[imp] {
+			t.Errorf("The shamir package imports %q, which is not available in the browser", imp)
+		}
+	}
+}
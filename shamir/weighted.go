@@ -0,0 +1,34 @@
+package shamir
+
+import "fmt"
+
+// SplitWeighted splits a secret among holders of different weight: holder
+// i receives weights[i] shares with consecutive IDs, so any group of holders
+// whose weights add up to k recovers the secret. This builds simple
+// hierarchical policies on the ordinary scheme; e.g. weights 2, 1, 1, 1
+// with k = 2 let the first holder recover the secret alone, or any two of
+// the others together. Combine needs no changes, as it only sees shares.
+// The weights may add up to at most MaxShares.
+func SplitWeighted(secret []byte, weights []int, k int) ([][]Share, error) {
+	total := 0
+	for i, w := range weights {
+		if w < 1 {
+			return nil, fmt.Errorf("holder %d: weight must be at least 1, got %d", i+1, w)
+		}
+		total += w
+		if total > MaxShares {
+			return nil, ErrTooManyShares
+		}
+	}
+
+	shares, err := SplitWithIDs(secret, defaultIDs(total), k)
+	if err != nil {
+		return nil, err
+	}
+
+	held := make([][]Share, len(weights))
+	for i, w := range weights {
+		held[i], shares = shares[:w:w], shares[w:]
+	}
+	return held, nil
+}
@@ -0,0 +1,55 @@
+package shamir
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestSplitWeighted(t *testing.T) {
+	// The CEO alone, or any two VPs
+	secret := []byte("launch codes")
+	held, err := SplitWeighted(secret, []int{2, 1, 1, 1}, 2)
+	if err != nil {
+		t.Fatalf("SplitWeighted failed: %v", err)
+	}
+	ceo, vp1, vp2, vp3 := held[0], held[1][0], held[2][0], held[3][0]
+	if len(ceo) != 2 || ceo[0].ID != 1 || ceo[1].ID != 2 || vp1.ID != 3 || vp3.ID != 5 {
+		t.Fatalf("Unexpected allocation: %+v", held)
+	}
+
+	for name, shares := range map[string][]Share{
+		"CEO":        ceo,
+		"VP 1 and 2": {vp1, vp2},
+		"VP 1 and 3": {vp3, vp1},
+		"CEO and VP": {ceo[1], vp2},
+	} {
+		got, err := Combine(shares)
+		if err != nil || string(got) != string(secret) {
+			t.Errorf("%s recovered %q, %v", name, got, err)
+		}
+	}
+
+	if _, err := Combine([]Share{vp2}); !errors.Is(err, ErrTooFewShares) {
+		t.Errorf("A single VP got %v, want ErrTooFewShares", err)
+	}
+}
+
+func TestSplitWeightedErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		weights []int
+		k       int
+		want    error
+	}{
+		{"zero weight", []int{2, 0}, 2, nil},
+		{"too many shares", []int{200, 56}, 2, ErrTooManyShares},
+		{"total below threshold", []int{1, 1}, 3, ErrThresholdTooLarge},
+		{"threshold too small", []int{2, 1}, 1, ErrThresholdTooSmall},
+	}
+	for _, tt := range tests {
+		_, err := SplitWeighted([]byte("secret"), tt.weights, tt.k)
+		if err == nil || (tt.want != nil && !errors.Is(err, tt.want)) {
+			t.Errorf("%s: SplitWeighted returned %v, want %v", tt.name, err, tt.want)
+		}
+	}
+}
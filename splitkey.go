@@ -0,0 +1,91 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"shamir-cli/shamir"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	splitKeyParts     int
+	splitKeyThreshold int
+	splitKeyOutDir    string
+)
+
+var splitKeyCmd = &cobra.Command{
+	Use:   "split-key [key_file]",
+	Short: "Split a private key file into armored part files",
+	Long: `Splits a private key file, such as an SSH or PGP key, into armored parts.
+The file is treated as opaque bytes. By default 3 parts are created and any
+2 of them recover the key.
+
+Each part is written next to the key as <key>.share-<ID>.asc with
+permissions 0600, or into the directory given with --out-dir.
+
+Example:
+  shamir-cli split-key ~/.ssh/id_ed25519
+  cat ~/.ssh/id_ed25519.share-*.asc | shamir-cli combine --out-file id_ed25519`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		out := cmd.OutOrStdout()
+
+		if splitKeyThreshold < 2 {
+			return newError(codeThresholdTooSmall, "split.threshold_too_small")
+		}
+		if splitKeyParts < splitKeyThreshold {
+			return newError(codeThresholdTooLarge, "split.parts_below_threshold")
+		}
+		if splitKeyParts > shamir.MaxShares {
+			return newError(codeTooManyShares, "split.too_many_parts", shamir.MaxShares)
+		}
+
+		outDir := splitKeyOutDir
+		if outDir == "" {
+			outDir = filepath.Dir(args[0])
+		}
+
+		paths, err := splitKeyFile(args[0], outDir, splitKeyParts, splitKeyThreshold)
+		if err != nil {
+			return err
+		}
+
+		fmt.Fprintf(out, "%s\n\n", tr("split.header", splitKeyParts, splitKeyThreshold))
+		for i, path := range paths {
+			fmt.Fprintln(out, tr("split.part_written", i+1, path))
+		}
+		name := filepath.Base(args[0])
+		pattern := filepath.Join(outDir, name+".share-*.asc")
+		fmt.Fprintf(out, "\n%s\n", tr("splitkey.hint", splitKeyThreshold, pattern, name))
+		return nil
+	},
+}
+
+// splitKeyFile splits the key file at path into n armored parts, k of which
+// recover it, and writes them to dir named after the key
+func splitKeyFile(path, dir string, n, k int) ([]string, error) {
+	key, err := os.ReadFile(path)
+	if err != nil {
+		return nil, newError(codeIO, "split.read_failed", err)
+	}
+
+	shares, err := shamir.Split(key, n, k)
+	if err != nil {
+		return nil, newError(errorCode(err, codeSplit), "split.failed", err)
+	}
+
+	paths, err := writeShareFiles(dir, filepath.Base(path)+".share", shares, k, formatArmor)
+	if err != nil {
+		return nil, newError(codeIO, "split.write_failed", err)
+	}
+	return paths, nil
+}
+
+func init() {
+	splitKeyCmd.Flags().IntVarP(&splitKeyParts, "parts", "n", 3, "total number of parts")
+	splitKeyCmd.Flags().IntVarP(&splitKeyThreshold, "threshold", "k", 2, "number of parts required for recovery")
+	splitKeyCmd.Flags().StringVar(&splitKeyOutDir, "out-dir", "", "directory to write the parts to (default: next to the key)")
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// timeout limits how long a command may run, set by the --timeout flag.
+// Zero means no limit.
+var timeout time.Duration
+
+// commandContext returns the context for running cmd, which is canceled
+// once --timeout has passed
+func commandContext(cmd *cobra.Command) (context.Context, context.CancelFunc) {
+	if timeout > 0 {
+		return context.WithTimeout(cmd.Context(), timeout)
+	}
+	return context.WithCancel(cmd.Context())
+}
+
+// readAllContext reads r until EOF like io.ReadAll, but gives up with
+// ctx.Err() once the context is done, e.g. on a reader that hangs
+func readAllContext(ctx context.Context, r io.Reader) ([]byte, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	type result struct {
+		data []byte
+		err  error
+	}
+	done := make(chan result, 1)
+	go func() {
+		data, err := io.ReadAll(r)
+		done <- result{data, err}
+	}()
+
+	select {
+	case res := <-done:
+		return res.data, res.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// readFileContext is like os.ReadFile but gives up once the context is done
+func readFileContext(ctx context.Context, path string) ([]byte, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return readAllContext(ctx, f)
+}
+
+// timeoutError replaces an error caused by the expired --timeout with one
+// reporting the timeout
+func timeoutError(err error) error {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return newError(codeTimeout, "timeout.expired", timeout)
+	}
+	return err
+}
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"shamir-cli/shamir"
+)
+
+// newTracePrinter returns a TraceFunc that prints the polynomial generated
+// for the first byte of the secret and its value at each part ID to w
+func newTracePrinter(w io.Writer) shamir.TraceFunc {
+	return func(byteIndex int, coeffs []byte, ids, values []byte) {
+		if byteIndex != 0 {
+			return
+		}
+
+		fmt.Fprintln(w, tr("verbose.polynomial", len(coeffs)-1, formatPolynomial(coeffs)))
+		for i, id := range ids {
+			fmt.Fprintf(w, "  f(%d) = 0x%02x\n", id, values[i])
+		}
+		fmt.Fprintln(w)
+	}
+}
+
+// formatPolynomial renders coefficients, lowest degree first, as
+// "0x68 + 0x1f*x + 0xa2*x^2"
+func formatPolynomial(coeffs []byte) string {
+	terms := make([]string, len(coeffs))
+	for i, c := range coeffs {
+		switch i {
+		case 0:
+			terms[i] = fmt.Sprintf("0x%02x", c)
+		case 1:
+			terms[i] = fmt.Sprintf("0x%02x*x", c)
+		default:
+			terms[i] = fmt.Sprintf("0x%02x*x^%d", c, i)
+		}
+	}
+	return strings.Join(terms, " + ")
+}
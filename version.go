@@ -0,0 +1,30 @@
+package main
+
+import (
+	"fmt"
+
+	"shamir-cli/shamir"
+
+	"github.com/spf13/cobra"
+)
+
+// splitShareVersion is the format version of the parts split writes
+// without --pad
+const splitShareVersion = 1
+
+var versionCmd = &cobra.Command{
+	Use:   "version",
+	Short: "Print version and build information",
+	Long: `Prints the version of this binary, the git commit and date it was built
+from, and the version of the part format it writes by default. Parts in a
+newer format than the binary reads must be combined with a newer build.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		out := cmd.OutOrStdout()
+		fmt.Fprintln(out, tr("version.version", version))
+		fmt.Fprintln(out, tr("version.commit", commit))
+		fmt.Fprintln(out, tr("version.date", buildDate))
+		fmt.Fprintln(out, tr("version.format", splitShareVersion, shamir.CurrentVersion))
+		return nil
+	},
+}
@@ -0,0 +1,170 @@
+// Package vss implements Feldman's verifiable secret sharing.
+//
+// Unlike the shamir package, which works byte by byte over GF(2^8), the
+// secret here is a single integer shared over the prime field Z_q. The
+// dealer publishes commitments g^a_i mod p to the polynomial coefficients,
+// which let every holder check their share without learning the secret.
+// The group is the 2048-bit MODP group from RFC 3526, whose modulus p is a
+// safe prime p = 2q+1; g = 2 generates its subgroup of prime order q.
+package vss
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+// Errors returned when splitting or combining
+var (
+	ErrThresholdTooSmall = errors.New("k must be at least 2")
+	ErrThresholdTooLarge = errors.New("n must be at least k")
+	ErrSecretTooLarge    = errors.New("secret must be a non-negative integer below the group order")
+)
+
+// p is the 2048-bit MODP group prime from RFC 3526, section 3
+var p, _ = new(big.Int).SetString(
+	"FFFFFFFFFFFFFFFFC90FDAA22168C234C4C6628B80DC1CD1"+
+		"29024E088A67CC74020BBEA63B139B22514A08798E3404DD"+
+		"EF9519B3CD3A431B302B0A6DF25F14374FE1356D6D51C245"+
+		"E485B576625E7EC6F44C42E9A637ED6B0BFF5CB6F406B7ED"+
+		"EE386BFB5A899FA5AE9F24117C4B1FE649286651ECE45B3D"+
+		"C2007CB8A163BF0598DA48361C55D39A69163FA8FD24CF5F"+
+		"83655D23DCA3AD961C62F356208552BB9ED529077096966D"+
+		"670C354E4ABC9804F1746C08CA18217C32905E462E36CE3B"+
+		"E39E772C180E86039B2783A2EC07A28FB5C55DF06F4C52C9"+
+		"DE2BCBF6955817183995497CEA956AE515D2261898FA0510"+
+		"15728E5A8AACAA68FFFFFFFFFFFFFFFF", 16)
+
+// q is the order of the subgroup generated by g, q = (p-1)/2
+var q = new(big.Int).Rsh(p, 1)
+
+// g generates the subgroup of order q
+var g = big.NewInt(2)
+
+// Share is one part of a secret: the value of the polynomial at X
+type Share struct {
+	X int      `json:"x"`
+	Y *big.Int `json:"y"`
+}
+
+// Commitments are g^a_i mod p for the coefficients a_i of the polynomial,
+// lowest degree first. Commitments[0] commits to the secret itself.
+type Commitments []*big.Int
+
+// SplitVSS divides secret into n shares, k of which are needed for
+// recovery, and returns the commitments to publish alongside them
+func SplitVSS(secret *big.Int, n, k int) ([]Share, Commitments, error) {
+	if k < 2 {
+		return nil, nil, ErrThresholdTooSmall
+	}
+	if n < k {
+		return nil, nil, ErrThresholdTooLarge
+	}
+	if secret.Sign() < 0 || secret.Cmp(q) >= 0 {
+		return nil, nil, ErrSecretTooLarge
+	}
+
+	coeffs := make([]*big.Int, k)
+	coeffs[0] = new(big.Int).Set(secret)
+	for i := 1; i < k; i++ {
+		c, err := rand.Int(rand.Reader, q)
+		if err != nil {
+			return nil, nil, err
+		}
+		coeffs[i] = c
+	}
+
+	commitments := make(Commitments, k)
+	for i, c := range coeffs {
+		commitments[i] = new(big.Int).Exp(g, c, p)
+	}
+
+	shares := make([]Share, n)
+	for j := range shares {
+		x := big.NewInt(int64(j + 1))
+
+		// Horner's rule modulo q
+		y := new(big.Int)
+		for i := k - 1; i >= 0; i-- {
+			y.Mul(y, x)
+			y.Add(y, coeffs[i])
+			y.Mod(y, q)
+		}
+		shares[j] = Share{X: j + 1, Y: y}
+	}
+
+	return shares, commitments, nil
+}
+
+// VerifyShareVSS checks share against the published commitments by testing
+// g^y == prod C_i^(x^i) mod p. It returns an error if the share is not
+// consistent with the commitments.
+func VerifyShareVSS(share Share, commitments Commitments) error {
+	if len(commitments) < 2 {
+		return errors.New("at least 2 commitments required")
+	}
+	if share.X < 1 {
+		return fmt.Errorf("share x must be positive, got %d", share.X)
+	}
+	if share.Y == nil || share.Y.Sign() < 0 || share.Y.Cmp(q) >= 0 {
+		return errors.New("share value out of range")
+	}
+
+	x := big.NewInt(int64(share.X))
+	power := big.NewInt(1) // x^i mod q
+	expected := big.NewInt(1)
+	for _, c := range commitments {
+		term := new(big.Int).Exp(c, power, p)
+		expected.Mul(expected, term)
+		expected.Mod(expected, p)
+		power.Mul(power, x)
+		power.Mod(power, q)
+	}
+
+	if new(big.Int).Exp(g, share.Y, p).Cmp(expected) != 0 {
+		return fmt.Errorf("share %d does not match the commitments", share.X)
+	}
+	return nil
+}
+
+// Combine recovers the secret from k or more shares using Lagrange
+// interpolation at x = 0 modulo q
+func Combine(shares []Share) (*big.Int, error) {
+	if len(shares) < 2 {
+		return nil, errors.New("minimum 2 parts required")
+	}
+
+	seen := make(map[int]bool, len(shares))
+	for _, share := range shares {
+		if share.X < 1 || share.Y == nil {
+			return nil, fmt.Errorf("invalid share %d", share.X)
+		}
+		if seen[share.X] {
+			return nil, fmt.Errorf("duplicate share x %d", share.X)
+		}
+		seen[share.X] = true
+	}
+
+	secret := new(big.Int)
+	for i, si := range shares {
+		numerator, denominator := big.NewInt(1), big.NewInt(1)
+		for j, sj := range shares {
+			if i == j {
+				continue
+			}
+			numerator.Mul(numerator, big.NewInt(int64(-sj.X)))
+			numerator.Mod(numerator, q)
+			denominator.Mul(denominator, big.NewInt(int64(si.X-sj.X)))
+			denominator.Mod(denominator, q)
+		}
+
+		term := new(big.Int).ModInverse(denominator, q)
+		term.Mul(term, numerator)
+		term.Mul(term, si.Y)
+		secret.Add(secret, term)
+		secret.Mod(secret, q)
+	}
+
+	return secret, nil
+}
@@ -0,0 +1,110 @@
+package vss
+
+import (
+	"math/big"
+	"testing"
+)
+
+func TestGroup(t *testing.T) {
+	if !p.ProbablyPrime(20) || !q.ProbablyPrime(20) {
+		t.Fatal("p and q must be prime")
+	}
+	if new(big.Int).Exp(g, q, p).Cmp(big.NewInt(1)) != 0 {
+		t.Error("g must generate the subgroup of order q")
+	}
+}
+
+func TestSplitVSSAndCombine(t *testing.T) {
+	secret := new(big.Int).SetBytes([]byte("verifiable secret"))
+
+	shares, commitments, err := SplitVSS(secret, 5, 3)
+	if err != nil {
+		t.Fatalf("SplitVSS failed: %v", err)
+	}
+	if len(shares) != 5 || len(commitments) != 3 {
+		t.Fatalf("Expected 5 shares and 3 commitments, got %d and %d", len(shares), len(commitments))
+	}
+
+	for _, share := range shares {
+		if err := VerifyShareVSS(share, commitments); err != nil {
+			t.Errorf("VerifyShareVSS(share %d) failed: %v", share.X, err)
+		}
+	}
+
+	recovered, err := Combine([]Share{shares[4], shares[1], shares[2]})
+	if err != nil {
+		t.Fatalf("Combine failed: %v", err)
+	}
+	if recovered.Cmp(secret) != 0 {
+		t.Errorf("Recovered %x, want %x", recovered, secret)
+	}
+
+	// Fewer than k shares give an unrelated value
+	partial, err := Combine(shares[:2])
+	if err != nil {
+		t.Fatalf("Combine failed: %v", err)
+	}
+	if partial.Cmp(secret) == 0 {
+		t.Error("2 of 3 required shares should not recover the secret")
+	}
+}
+
+func TestVerifyShareVSSTampered(t *testing.T) {
+	shares, commitments, err := SplitVSS(big.NewInt(42), 3, 2)
+	if err != nil {
+		t.Fatalf("SplitVSS failed: %v", err)
+	}
+
+	tamperedY := Share{X: shares[0].X, Y: new(big.Int).Add(shares[0].Y, big.NewInt(1))}
+	tamperedY.Y.Mod(tamperedY.Y, q)
+	if err := VerifyShareVSS(tamperedY, commitments); err == nil {
+		t.Error("Share with a modified value should fail verification")
+	}
+
+	movedX := Share{X: shares[1].X, Y: shares[0].Y}
+	if err := VerifyShareVSS(movedX, commitments); err == nil {
+		t.Error("Share with a wrong x should fail verification")
+	}
+
+	forged := append(Commitments{}, commitments...)
+	forged[0] = new(big.Int).Exp(g, big.NewInt(43), p)
+	if err := VerifyShareVSS(shares[0], forged); err == nil {
+		t.Error("Share should fail verification against forged commitments")
+	}
+
+	if err := VerifyShareVSS(Share{X: 1, Y: q}, commitments); err == nil {
+		t.Error("Share value outside Z_q should be rejected")
+	}
+}
+
+func TestSplitVSSValidation(t *testing.T) {
+	tests := []struct {
+		name    string
+		secret  *big.Int
+		n, k    int
+		wantErr error
+	}{
+		{"k too small", big.NewInt(1), 3, 1, ErrThresholdTooSmall},
+		{"n below k", big.NewInt(1), 2, 3, ErrThresholdTooLarge},
+		{"negative secret", big.NewInt(-1), 3, 2, ErrSecretTooLarge},
+		{"secret equal to q", q, 3, 2, ErrSecretTooLarge},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, _, err := SplitVSS(tt.secret, tt.n, tt.k); err != tt.wantErr {
+				t.Errorf("SplitVSS() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCombineValidation(t *testing.T) {
+	one := big.NewInt(1)
+	if _, err := Combine([]Share{{X: 1, Y: one}}); err == nil {
+		t.Error("Expected error with a single share")
+	}
+	if _, err := Combine([]Share{{X: 1, Y: one}, {X: 1, Y: one}}); err == nil {
+		t.Error("Expected error with duplicate x")
+	}
+}
@@ -0,0 +1,87 @@
+//go:build js && wasm
+
+// Command wasm exposes split and combine to JavaScript when built with
+//
+//	GOOS=js GOARCH=wasm go build -o shamir.wasm ./wasm
+//
+// and loaded with wasm_exec.js from the Go distribution. It registers a
+// global object shamir with two functions:
+//
+//	shamir.split(secret, n, k)  // {parts: ["1:...", ...]} or {error: "..."}
+//	shamir.combine(parts)       // {secret: Uint8Array} or {error: "..."}
+//
+// The secret is passed as a string, whose UTF-8 bytes are split, or as a
+// Uint8Array, so binary secrets round-trip. It is always returned as a
+// Uint8Array. Random
+// coefficients come from crypto/rand, which uses crypto.getRandomValues in
+// the browser.
+package main
+
+import (
+	"syscall/js"
+
+	"shamir-cli/shamir"
+)
+
+func main() {
+	js.Global().Set("shamir", js.ValueOf(map[string]any{
+		"split":   js.FuncOf(split),
+		"combine": js.FuncOf(combine),
+	}))
+	// Keep the functions callable after main returns
+	select {}
+}
+
+const splitUsage = "usage: split(secret: string | Uint8Array, n: number, k: number)"
+
+// split implements shamir.split(secret, n, k)
+func split(this js.Value, args []js.Value) any {
+	if len(args) != 3 || args[1].Type() != js.TypeNumber || args[2].Type() != js.TypeNumber {
+		return result("error", splitUsage)
+	}
+
+	var secret []byte
+	switch {
+	case args[0].Type() == js.TypeString:
+		secret = []byte(args[0].String())
+	case args[0].InstanceOf(js.Global().Get("Uint8Array")):
+		secret = make([]byte, args[0].Length())
+		js.CopyBytesToGo(secret, args[0])
+	default:
+		return result("error", splitUsage)
+	}
+
+	parts, err := shamir.SplitToStrings(secret, args[1].Int(), args[2].Int())
+	if err != nil {
+		return result("error", err.Error())
+	}
+	list := make([]any, len(parts))
+	for i, part := range parts {
+		list[i] = part
+	}
+	return result("parts", list)
+}
+
+// combine implements shamir.combine(parts)
+func combine(this js.Value, args []js.Value) any {
+	if len(args) != 1 || !js.Global().Get("Array").Call("isArray", args[0]).Bool() {
+		return result("error", "usage: combine(parts: string[])")
+	}
+
+	parts := make([]string, args[0].Length())
+	for i := range parts {
+		parts[i] = args[0].Index(i).String()
+	}
+	secret, err := shamir.CombineStrings(parts)
+	if err != nil {
+		return result("error", err.Error())
+	}
+	array := js.Global().Get("Uint8Array").New(len(secret))
+	js.CopyBytesToJS(array, secret)
+	return result("secret", array)
+}
+
+// result returns a JavaScript object with the single given property
+func result(key string, value any) js.Value {
+	return js.ValueOf(map[string]any{key: value})
+}
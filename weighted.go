@@ -0,0 +1,98 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"shamir-cli/shamir"
+
+	"github.com/spf13/cobra"
+)
+
+var splitWeightedCmd = &cobra.Command{
+	Use:   "split-weighted [secret] [threshold] [holder:weight]...",
+	Short: "Split a secret among holders of different weight",
+	Long: `Splits a secret among holders of different weight. Each holder given as
+holder:weight receives as many parts as their weight, so any group of
+holders whose weights add up to the threshold recovers the secret. This
+expresses simple hierarchical policies; e.g. "the CEO, or any two VPs":
+
+  shamir-cli split-weighted "secret" 2 ceo:2 vp1:1 vp2:1 vp3:1
+
+The parts are ordinary parts with consecutive IDs, labeled with the name
+of their holder, so combine needs nothing special: pass all parts of the
+holders taking part. The weights may add up to at most 255.`,
+	Args: cobra.MinimumNArgs(3),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		out := cmd.OutOrStdout()
+
+		secret := []byte(args[0])
+		k, err := strconv.Atoi(args[1])
+		if err != nil {
+			return newError(codeUsage, "split.invalid_threshold", args[1])
+		}
+		if k < 2 {
+			return newError(codeThresholdTooSmall, "split.threshold_too_small")
+		}
+
+		holders, weights, err := parseWeightSpecs(args[2:])
+		if err != nil {
+			return err
+		}
+		total := 0
+		for _, w := range weights {
+			total += w
+		}
+		if total > shamir.MaxShares {
+			return newError(codeTooManyShares, "weighted.too_many_parts", total, shamir.MaxShares)
+		}
+		if total < k {
+			return newError(codeThresholdTooLarge, "weighted.weight_below_threshold", total, k)
+		}
+		if len(secret) == 0 {
+			return newError(codeInvalidSecretLength, "split.empty_secret")
+		}
+
+		held, err := shamir.SplitWeighted(secret, weights, k)
+		if err != nil {
+			return newError(errorCode(err, codeSplit), "split.failed", err)
+		}
+
+		fmt.Fprintf(out, "%s\n\n", colorize(out, colorBold, tr("weighted.header", len(holders), total, k)))
+		for i, shares := range held {
+			fmt.Fprintln(out, tr("weighted.holder", holders[i], weights[i]))
+			for _, share := range shares {
+				share.Label = holders[i]
+				fmt.Fprintf(out, "  %s\n", shamir.ShareToString(share))
+			}
+		}
+		fmt.Fprintf(out, "\n%s\n", tr("weighted.hint", k))
+		return nil
+	},
+}
+
+// parseWeightSpecs parses holder:weight specs into holder names, which
+// label the parts, and weights
+func parseWeightSpecs(specs []string) ([]string, []int, error) {
+	holders := make([]string, 0, len(specs))
+	weights := make([]int, 0, len(specs))
+	seen := make(map[string]bool)
+	for _, spec := range specs {
+		holder, weight, ok := strings.Cut(spec, ":")
+		w, err := strconv.Atoi(weight)
+		if !ok || err != nil || w < 1 {
+			return nil, nil, newError(codeUsage, "weighted.invalid_spec", spec)
+		}
+		if err := shamir.ValidateLabel(holder); err != nil {
+			return nil, nil, newError(codeUsage, "split.invalid_label", err)
+		}
+		if seen[holder] {
+			return nil, nil, newError(codeUsage, "weighted.duplicate_holder", holder)
+		}
+		seen[holder] = true
+		holders = append(holders, holder)
+		weights = append(weights, w)
+	}
+	return holders, weights, nil
+}
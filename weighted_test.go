@@ -0,0 +1,140 @@
+package main
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestSplitWeighted(t *testing.T) {
+	out, err := executeCommand(t, "", "split-weighted", "weighted secret", "3", "ceo:3", "vp1:2", "vp2:2", "staff:1")
+	if err != nil {
+		t.Fatalf("split-weighted failed: %v", err)
+	}
+
+	parts := make(map[string][]string)
+	for _, line := range strings.Split(out, "\n") {
+		if part := strings.TrimPrefix(line, "  "); part != line {
+			_, rest, _ := strings.Cut(part, "#")
+			holder, _, _ := strings.Cut(rest, ":")
+			parts[holder] = append(parts[holder], part)
+		}
+	}
+	if len(parts["ceo"]) != 3 || len(parts["vp1"]) != 2 || len(parts["vp2"]) != 2 || len(parts["staff"]) != 1 {
+		t.Fatalf("Unexpected allocation %v in:\n%s", parts, out)
+	}
+
+	recovering := [][]string{{"ceo"}, {"vp1", "staff"}, {"vp2", "vp1"}}
+	for _, group := range recovering {
+		var given []string
+		for _, holder := range group {
+			given = append(given, parts[holder]...)
+		}
+		out, err := executeCommand(t, "", "combine", strings.Join(given, ","))
+		if err != nil {
+			t.Errorf("%v failed to recover the secret: %v", group, err)
+		} else if out != "Recovered secret: weighted secret\n" {
+			t.Errorf("%v recovered %q", group, out)
+		}
+	}
+
+	// A VP alone holds 2 of the 3 parts needed
+	if out, err := executeCommand(t, "", "combine", strings.Join(parts["vp2"], ",")); err == nil && strings.Contains(out, "weighted secret") {
+		t.Errorf("A single VP recovered the secret: %q", out)
+	}
+}
+
+func TestSplitWeightedErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		code string
+	}{
+		{"no weight", []string{"split-weighted", "secret", "2", "ceo", "vp:1"}, codeUsage},
+		{"zero weight", []string{"split-weighted", "secret", "2", "ceo:0", "vp:2"}, codeUsage},
+		{"duplicate holder", []string{"split-weighted", "secret", "2", "vp:1", "vp:1"}, codeUsage},
+		{"invalid holder", []string{"split-weighted", "secret", "2", "v p:1", "vp:1"}, codeUsage},
+		{"below threshold", []string{"split-weighted", "secret", "4", "ceo:2", "vp:1"}, codeThresholdTooLarge},
+		{"too many parts", []string{"split-weighted", "secret", "2", "ceo:200", "vp:56"}, codeTooManyShares},
+		{"threshold too small", []string{"split-weighted", "secret", "1", "ceo:2"}, codeThresholdTooSmall},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := executeCommand(t, "", tt.args...)
+			var e *cliError
+			if !errors.As(err, &e) || e.Code != tt.code {
+				t.Errorf("%v returned %v, want error code %q", tt.args, err, tt.code)
+			}
+		})
+	}
+}
+
+func TestCombineRequireHolders(t *testing.T) {
+	out, err := executeCommand(t, "", "split-weighted", "policy secret", "3", "ceo:3", "vp1:2", "vp2:2", "staff:1")
+	if err != nil {
+		t.Fatalf("split-weighted failed: %v", err)
+	}
+	parts := make(map[string][]string)
+	for _, line := range strings.Split(out, "\n") {
+		if part := strings.TrimPrefix(line, "  "); part != line {
+			_, rest, _ := strings.Cut(part, "#")
+			holder, _, _ := strings.Cut(rest, ":")
+			parts[holder] = append(parts[holder], part)
+		}
+	}
+
+	tests := []struct {
+		name    string
+		holders []string
+		require string
+		ok      bool
+	}{
+		{"one holder, no policy", []string{"ceo"}, "0", true},
+		{"one holder", []string{"ceo"}, "2", false},
+		{"two holders", []string{"vp1", "staff"}, "2", true},
+		{"two holders, three required", []string{"vp1", "staff"}, "3", false},
+		{"three holders", []string{"ceo", "vp2", "staff"}, "3", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var given []string
+			for _, holder := range tt.holders {
+				given = append(given, parts[holder]...)
+			}
+			out, err := executeCommand(t, "", "combine", "--require-holders", tt.require, strings.Join(given, ","))
+			if tt.ok {
+				if err != nil || out != "Recovered secret: policy secret\n" {
+					t.Errorf("combine = %q, %v", out, err)
+				}
+				return
+			}
+			var e *cliError
+			if !errors.As(err, &e) || e.Code != codeInsufficientHolders {
+				t.Errorf("combine returned %v, want error code %q", err, codeInsufficientHolders)
+			}
+			if strings.Contains(out, "policy secret") {
+				t.Errorf("combine revealed the secret: %q", out)
+			}
+		})
+	}
+
+	// Unlabeled parts do not count as holders
+	var unlabeled []string
+	for _, holder := range []string{"vp1", "vp2"} {
+		for _, part := range parts[holder] {
+			id, rest, _ := strings.Cut(part, "#")
+			_, value, _ := strings.Cut(rest, ":")
+			unlabeled = append(unlabeled, id+":"+value)
+		}
+	}
+	_, err = executeCommand(t, "", "combine", "--require-holders", "1", strings.Join(unlabeled, ","))
+	var e *cliError
+	if !errors.As(err, &e) || e.Code != codeInsufficientHolders {
+		t.Errorf("combine of unlabeled parts returned %v, want error code %q", err, codeInsufficientHolders)
+	}
+
+	_, err = executeCommand(t, "", "combine", "--require-holders", "-1", strings.Join(parts["ceo"], ","))
+	if !errors.As(err, &e) || e.Code != codeUsage {
+		t.Errorf("combine --require-holders -1 returned %v, want error code %q", err, codeUsage)
+	}
+}